@@ -0,0 +1,53 @@
+// Command simulate runs synthetic players through the matchmaking rules
+// offline, and reports how long they waited and what groups they ended up in.
+//
+//	go run ./cmd/simulate -config cmd/simulate/config.example.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jcserv/rivalslfg/internal/simulation"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to a JSON config file, defaults are used for anything it leaves out")
+	seed := flag.Int64("seed", 0, "overrides the config's seed if set")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	if err := run(*configPath, *seed, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, seed int64, asJSON bool) error {
+	cfg := simulation.DefaultConfig()
+	if configPath != "" {
+		loaded, err := simulation.LoadConfig(configPath)
+		if err != nil {
+			return err
+		}
+		cfg = loaded
+	}
+	if seed != 0 {
+		cfg.Seed = seed
+	}
+
+	report, err := simulation.New(cfg).Run(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return report.Write(os.Stdout)
+}
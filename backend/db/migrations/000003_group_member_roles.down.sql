@@ -0,0 +1 @@
+ALTER TABLE GroupMembers DROP COLUMN role;
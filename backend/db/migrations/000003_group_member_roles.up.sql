@@ -0,0 +1,9 @@
+-- The role that each member was seated in, which may differ from the role on their profile
+ALTER TABLE GroupMembers ADD COLUMN role TEXT;
+
+UPDATE GroupMembers gm
+SET role = LOWER(p.role)
+FROM Players p
+WHERE p.id = gm.player_id;
+
+ALTER TABLE GroupMembers ALTER COLUMN role SET NOT NULL;
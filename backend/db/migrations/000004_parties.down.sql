@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS PartyMembers;
+DROP TABLE IF EXISTS Parties;
+DROP FUNCTION IF EXISTS generate_party_id;
@@ -0,0 +1,38 @@
+CREATE OR REPLACE FUNCTION generate_party_id() 
+RETURNS char(4) AS $$
+DECLARE
+    chars char[] := ARRAY['A','B','C','D','E','F','G','H','I','J','K','L','M',
+                         'N','O','P','Q','R','S','T','U','V','W','X','Y','Z'];
+    result char(4) := '';
+    i integer := 0;
+BEGIN
+    -- Generate a random 4-letter string
+    WHILE i < 4 LOOP
+        result := result || chars[1 + floor(random() * 26)];
+        i := i + 1;
+    END LOOP;
+    
+    -- If ID already exists, try again (recursive)
+    IF EXISTS (SELECT 1 FROM parties WHERE id = result) THEN
+        result := generate_party_id();
+    END IF;
+    
+    RETURN result;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE Parties (
+    id CHAR(4) PRIMARY KEY DEFAULT generate_party_id(),
+    leader_id INTEGER NOT NULL REFERENCES Players(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE PartyMembers (
+    party_id CHAR(4) NOT NULL REFERENCES Parties(id) ON DELETE CASCADE,
+    -- Players can only be in one party at a time
+    player_id INTEGER NOT NULL UNIQUE REFERENCES Players(id),
+    -- Roles the player is willing to play, in order of preference
+    roles TEXT[] NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (party_id, player_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS GroupInviteRedemptions;
+DROP TABLE IF EXISTS GroupInvites;
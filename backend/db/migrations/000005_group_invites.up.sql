@@ -0,0 +1,22 @@
+CREATE TABLE GroupInvites (
+    id SERIAL PRIMARY KEY,
+    group_id CHAR(4) NOT NULL REFERENCES Groups(id) ON DELETE CASCADE,
+    token TEXT NOT NULL UNIQUE,
+    created_by INTEGER NOT NULL REFERENCES Players(id),
+    -- How many players can join with the invite
+    max_uses INTEGER NOT NULL CHECK (max_uses > 0),
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX group_invites_group_id_idx ON GroupInvites (group_id);
+
+-- Who joined with each invite
+CREATE TABLE GroupInviteRedemptions (
+    invite_id INTEGER NOT NULL REFERENCES GroupInvites(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES Players(id),
+    redeemed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (invite_id, player_id)
+);
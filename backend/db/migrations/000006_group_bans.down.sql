@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS GroupBans;
+ALTER TABLE GroupMembers DROP COLUMN session_id;
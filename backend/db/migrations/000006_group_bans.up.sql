@@ -0,0 +1,13 @@
+-- The session that each member joined from, so that bans can outlast their player ID
+ALTER TABLE GroupMembers ADD COLUMN session_id TEXT;
+
+CREATE TABLE GroupBans (
+    group_id CHAR(4) NOT NULL REFERENCES Groups(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES Players(id),
+    session_id TEXT,
+    banned_by INTEGER NOT NULL REFERENCES Players(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, player_id)
+);
+
+CREATE INDEX group_bans_session_id_idx ON GroupBans (group_id, session_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS JoinRequests;
+ALTER TABLE Groups DROP COLUMN request_to_join;
@@ -0,0 +1,20 @@
+-- Private groups can let players ask to join, instead of requiring the passcode
+ALTER TABLE Groups ADD COLUMN request_to_join BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE JoinRequests (
+    id SERIAL PRIMARY KEY,
+    group_id CHAR(4) NOT NULL REFERENCES Groups(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES Players(id),
+    -- Roles the player is willing to be seated in, in order of preference
+    roles TEXT[] NOT NULL,
+    session_id TEXT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    CONSTRAINT valid_status CHECK (status IN ('pending', 'approved', 'denied', 'cancelled', 'expired'))
+);
+
+-- Players can only have one request pending at a time
+CREATE UNIQUE INDEX join_requests_pending_idx ON JoinRequests (player_id) WHERE status = 'pending';
+CREATE INDEX join_requests_group_id_idx ON JoinRequests (group_id, status);
@@ -0,0 +1,17 @@
+CREATE TABLE GroupWaitlist (
+    id SERIAL PRIMARY KEY,
+    group_id CHAR(4) NOT NULL REFERENCES Groups(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES Players(id),
+    -- The only role the player is waiting for, or NULL if any of their roles will do
+    role TEXT,
+    -- Roles the player is willing to be seated in, in order of preference
+    roles TEXT[] NOT NULL,
+    session_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Set while a slot is being held for the player to accept
+    offered_role TEXT,
+    offer_expires_at TIMESTAMPTZ,
+    UNIQUE (group_id, player_id)
+);
+
+CREATE INDEX group_waitlist_offer_idx ON GroupWaitlist (offer_expires_at) WHERE offer_expires_at IS NOT NULL;
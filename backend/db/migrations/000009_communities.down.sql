@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS groups_community_id_idx;
+DROP TABLE IF EXISTS CommunityModerators;
+ALTER TABLE Community DROP COLUMN created_at;
+ALTER TABLE Community DROP COLUMN invite_only;
+ALTER TABLE Community DROP COLUMN regions;
+ALTER TABLE Community DROP COLUMN max_rank;
+ALTER TABLE Community DROP COLUMN min_rank;
@@ -0,0 +1,17 @@
+-- Rules that every group in the community has to follow
+ALTER TABLE Community ADD COLUMN min_rank INTEGER;
+ALTER TABLE Community ADD COLUMN max_rank INTEGER;
+-- Regions that groups can be in, or any region if empty
+ALTER TABLE Community ADD COLUMN regions TEXT[] NOT NULL DEFAULT '{}';
+-- Groups in invite-only communities have to be private
+ALTER TABLE Community ADD COLUMN invite_only BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE Community ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE TABLE CommunityModerators (
+    community_id INTEGER NOT NULL REFERENCES Community(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES Players(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (community_id, player_id)
+);
+
+CREATE INDEX groups_community_id_idx ON Groups (community_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS groups_starts_at_idx;
+DROP TABLE IF EXISTS GroupRsvps;
+ALTER TABLE Groups DROP COLUMN duration_minutes;
+ALTER TABLE Groups DROP COLUMN starts_at;
+ALTER TABLE Groups DROP COLUMN scheduled;
@@ -0,0 +1,17 @@
+-- Scheduled groups are created ahead of time, and go live at starts_at
+ALTER TABLE Groups ADD COLUMN scheduled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE Groups ADD COLUMN starts_at TIMESTAMPTZ;
+ALTER TABLE Groups ADD COLUMN duration_minutes INTEGER;
+
+CREATE TABLE GroupRsvps (
+    id SERIAL PRIMARY KEY,
+    group_id CHAR(4) NOT NULL REFERENCES Groups(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES Players(id),
+    -- The role that the player's slot is held in
+    role TEXT NOT NULL,
+    session_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (group_id, player_id)
+);
+
+CREATE INDEX groups_starts_at_idx ON Groups (starts_at) WHERE scheduled;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS groups_tags_idx;
+ALTER TABLE Groups DROP COLUMN tags;
+ALTER TABLE Groups DROP COLUMN language;
+ALTER TABLE Groups DROP COLUMN description;
@@ -0,0 +1,6 @@
+-- Owners can describe their group, and say what language it's played in. An empty language is unspecified.
+ALTER TABLE Groups ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE Groups ADD COLUMN language TEXT NOT NULL DEFAULT '';
+ALTER TABLE Groups ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX groups_tags_idx ON Groups USING GIN (tags);
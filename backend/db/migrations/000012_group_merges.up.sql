@@ -0,0 +1,18 @@
+-- Owners of partially filled groups can propose moving their members into another group
+CREATE TABLE MergeProposals (
+    id SERIAL PRIMARY KEY,
+    -- The group whose members are moved, which is deleted once the merge is accepted
+    source_id CHAR(4) NOT NULL REFERENCES Groups(id) ON DELETE CASCADE,
+    target_id CHAR(4) NOT NULL REFERENCES Groups(id) ON DELETE CASCADE,
+    proposed_by INTEGER NOT NULL REFERENCES Players(id),
+    status TEXT NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    CONSTRAINT valid_status CHECK (status IN ('pending', 'accepted', 'declined', 'expired')),
+    CONSTRAINT distinct_groups CHECK (source_id <> target_id)
+);
+
+-- Groups can only have one merge into the same group pending at a time
+CREATE UNIQUE INDEX merge_proposals_pending_idx ON MergeProposals (source_id, target_id) WHERE status = 'pending';
+CREATE INDEX merge_proposals_target_id_idx ON MergeProposals (target_id, status);
@@ -0,0 +1 @@
+ALTER TABLE PartyMembers DROP COLUMN IF EXISTS session_id;
@@ -0,0 +1,2 @@
+-- The session each member joined the party from, so that bans keyed to it are checked when the party joins a group
+ALTER TABLE PartyMembers ADD COLUMN session_id TEXT;
@@ -0,0 +1,22 @@
+-- name: GetGroupBans :many
+SELECT
+    b.player_id,
+    p.name,
+    b.banned_by,
+    b.created_at
+FROM GroupBans b
+JOIN Players p ON p.id = b.player_id
+WHERE b.group_id = @group_id
+ORDER BY b.created_at DESC;
+
+-- name: DeleteGroupBan :execrows
+DELETE FROM GroupBans
+WHERE group_id = @group_id
+AND player_id = @player_id;
+
+-- name: IsPlayerBanned :one
+SELECT EXISTS (
+    SELECT 1 FROM GroupBans
+    WHERE group_id = @group_id
+    AND (player_id = @player_id OR session_id = NULLIF(@session_id::TEXT, ''))
+);
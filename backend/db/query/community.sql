@@ -0,0 +1,88 @@
+-- name: AddCommunityModerator :execrows
+-- Only players that exist can be made moderators
+INSERT INTO CommunityModerators (
+    community_id,
+    player_id
+)
+SELECT
+    @community_id,
+    @player_id
+WHERE EXISTS (SELECT 1 FROM Players WHERE id = @player_id)
+ON CONFLICT (community_id, player_id) DO NOTHING;
+
+-- name: CreateCommunity :one
+INSERT INTO Community (
+    name,
+    description,
+    link,
+    min_rank,
+    max_rank,
+    regions,
+    invite_only
+)
+VALUES (
+    @name,
+    @description,
+    @link,
+    @min_rank,
+    @max_rank,
+    @regions,
+    @invite_only
+)
+RETURNING *;
+
+-- name: DeleteCommunity :execrows
+-- Communities that still have groups are kept, since every group needs one
+DELETE FROM Community
+WHERE id = @id
+AND NOT EXISTS (SELECT 1 FROM Groups WHERE community_id = @id);
+
+-- name: GetCommunities :many
+SELECT *
+FROM Community
+ORDER BY id;
+
+-- name: GetCommunity :one
+SELECT *
+FROM Community
+WHERE id = @id;
+
+-- name: GetCommunityModerators :many
+SELECT
+    m.player_id,
+    p.name,
+    m.created_at
+FROM CommunityModerators m
+JOIN Players p ON p.id = m.player_id
+WHERE m.community_id = @community_id
+ORDER BY m.created_at;
+
+-- name: IsCommunityModerator :one
+SELECT EXISTS (
+    SELECT 1 FROM CommunityModerators
+    WHERE community_id = @community_id
+    AND player_id = @player_id
+);
+
+-- name: RemoveCommunityModerator :execrows
+-- The last moderator can't be removed, so that someone can always manage the community
+DELETE FROM CommunityModerators
+WHERE community_id = @community_id
+AND player_id = @player_id
+AND EXISTS (
+    SELECT 1 FROM CommunityModerators
+    WHERE community_id = @community_id
+    AND player_id != @player_id
+);
+
+-- name: UpdateCommunity :exec
+UPDATE Community
+SET
+    name = @name,
+    description = @description,
+    link = @link,
+    min_rank = @min_rank,
+    max_rank = @max_rank,
+    regions = @regions,
+    invite_only = @invite_only
+WHERE id = @id;
@@ -67,7 +67,15 @@ new_group AS (
         strategists,
         platform,
         voice_chat,
-        mic
+        mic,
+        request_to_join,
+        community_id,
+        scheduled,
+        starts_at,
+        duration_minutes,
+        description,
+        language,
+        tags
     )
     SELECT
         @owner,
@@ -79,7 +87,15 @@ new_group AS (
         @strategists,
         @platform,
         @group_voice_chat,
-        @group_mic
+        @group_mic,
+        @request_to_join,
+        @community_id,
+        sqlc.narg('starts_at')::TIMESTAMPTZ IS NOT NULL,
+        sqlc.narg('starts_at'),
+        sqlc.narg('duration_minutes'),
+        @description,
+        @language,
+        @tags
     WHERE 
         NOT EXISTS (SELECT 1 FROM existing_membership) AND
         (@group_id = '' OR NOT EXISTS (SELECT 1 FROM Groups WHERE id = @group_id))
@@ -102,12 +118,14 @@ new_membership AS (
     INSERT INTO GroupMembers (
         group_id,
         player_id,
-        leader
+        leader,
+        role
     )
     SELECT 
         fg.group_id,
         fp.player_id,
-        true
+        true,
+        LOWER(@role)
     FROM final_group fg, final_player fp
     WHERE NOT EXISTS (SELECT 1 FROM existing_membership)
     RETURNING group_id::text, player_id::integer
@@ -119,4 +137,147 @@ FROM (
     UNION ALL
     SELECT * FROM new_membership
 ) results
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: LockGroup :one
+-- Locks the group until the end of the transaction, so that members can be added without racing other joins
+SELECT id::text
+FROM Groups
+WHERE id = @id
+FOR UPDATE;
+
+-- name: AddGroupMember :exec
+INSERT INTO GroupMembers (
+    group_id,
+    player_id,
+    leader,
+    role,
+    session_id
+)
+VALUES (
+    @group_id,
+    @player_id,
+    @leader,
+    @role,
+    NULLIF(@session_id::TEXT, '')
+);
+
+-- name: DeleteGroupMembers :many
+DELETE FROM GroupMembers
+WHERE group_id = @group_id
+RETURNING player_id;
+
+-- name: GetGroupMemberSessions :many
+-- The session that each member joined the group from, so that bans elsewhere can be checked against them
+SELECT player_id, session_id
+FROM GroupMembers
+WHERE group_id = @group_id
+ORDER BY player_id;
+
+-- name: MoveGroupMembers :many
+-- Moves every member of the source group into the target group, where none of them lead
+UPDATE GroupMembers
+SET
+    group_id = @target_id,
+    leader = false
+WHERE group_id = @source_id
+RETURNING player_id, session_id;
+
+-- name: DeleteGroup :execrows
+DELETE FROM Groups
+WHERE id = @id;
+
+-- name: UpdateGroup :exec
+UPDATE Groups
+SET
+    region = @region,
+    gamemode = @gamemode,
+    open = @open,
+    vanguards = @vanguards,
+    duelists = @duelists,
+    strategists = @strategists,
+    platform = @platform,
+    voice_chat = @voice_chat,
+    mic = @mic,
+    request_to_join = @request_to_join,
+    description = @description,
+    language = @language,
+    tags = @tags,
+    updated_at = NOW(),
+    last_active_at = NOW()
+WHERE id = @id;
+
+-- name: SetMemberRole :exec
+-- Moves the member to another role in the group
+UPDATE GroupMembers
+SET role = @role
+WHERE group_id = @group_id
+AND player_id = @player_id;
+
+-- name: StartGroup :exec
+-- Makes the scheduled group live, as if it had just been active
+UPDATE Groups
+SET
+    scheduled = false,
+    updated_at = NOW(),
+    last_active_at = NOW()
+WHERE id = @id;
+
+-- name: TouchGroup :exec
+-- Marks the group as active, e.g. when members join, leave or chat
+UPDATE Groups
+SET last_active_at = NOW()
+WHERE id = @id;
+
+-- name: GetIdleGroups :many
+-- Scheduled groups aren't idle, since nobody is expected to be active in them until they start
+SELECT id::text, last_active_at
+FROM Groups
+WHERE last_active_at < @idle_since
+AND NOT scheduled
+ORDER BY last_active_at;
+
+-- name: LockIdleGroup :one
+-- Locks the group until the end of the transaction, as long as it's still idle
+SELECT id::text
+FROM Groups
+WHERE id = @id
+AND last_active_at < @idle_since
+AND NOT scheduled
+FOR UPDATE;
+
+-- name: GetDueGroups :many
+-- Scheduled groups that are due to start
+SELECT id::text
+FROM Groups
+WHERE scheduled
+AND starts_at <= NOW()
+ORDER BY starts_at;
+
+-- name: GetGroupPasscode :one
+SELECT passcode
+FROM Groups
+WHERE id = @id;
+
+-- name: RotateGroupPasscode :one
+UPDATE Groups
+SET
+    passcode = generate_passcode(),
+    updated_at = NOW(),
+    last_active_at = NOW()
+WHERE id = @id
+RETURNING passcode;
+
+-- name: SetGroupLeader :exec
+-- Makes the player the group's only leader
+UPDATE GroupMembers
+SET leader = (player_id = @player_id)
+WHERE group_id = @group_id;
+
+-- name: SetGroupOwner :exec
+UPDATE Groups
+SET
+    owner = @owner,
+    updated_at = NOW(),
+    last_active_at = NOW()
+WHERE id = @id;
@@ -0,0 +1,44 @@
+-- name: CreateGroupInvite :one
+-- Nothing is created if the group doesn't exist
+INSERT INTO GroupInvites (
+    group_id,
+    token,
+    created_by,
+    max_uses,
+    expires_at
+)
+SELECT g.id, @token, @created_by, @max_uses, @expires_at
+FROM Groups g
+WHERE g.id = @group_id
+RETURNING *;
+
+-- name: GetGroupInvites :many
+SELECT *
+FROM GroupInvites
+WHERE group_id = @group_id
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetGroupInviteRedemptions :many
+SELECT
+    r.invite_id,
+    r.player_id,
+    p.name,
+    r.redeemed_at
+FROM GroupInviteRedemptions r
+JOIN GroupInvites i ON i.id = r.invite_id
+JOIN Players p ON p.id = r.player_id
+WHERE i.group_id = @group_id
+ORDER BY r.redeemed_at;
+
+-- name: RevokeGroupInvite :execrows
+UPDATE GroupInvites
+SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = @id
+AND group_id = @group_id;
+
+-- name: RevokeGroupInvites :exec
+-- Revokes every invite to the group that hasn't been revoked yet
+UPDATE GroupInvites
+SET revoked_at = NOW()
+WHERE group_id = @group_id
+AND revoked_at IS NULL;
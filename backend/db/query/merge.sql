@@ -0,0 +1,68 @@
+-- name: CreateMergeProposal :one
+-- Proposing the same merge again while it's pending is a no-op
+INSERT INTO MergeProposals (
+    source_id,
+    target_id,
+    proposed_by,
+    expires_at
+)
+VALUES (
+    @source_id,
+    @target_id,
+    @proposed_by,
+    @expires_at
+)
+ON CONFLICT (source_id, target_id) WHERE status = 'pending' DO NOTHING
+RETURNING *;
+
+-- name: ExpireMergeProposals :exec
+-- Marks the group's proposals that ran out as expired, so that they no longer count as pending
+UPDATE MergeProposals
+SET
+    status = 'expired',
+    resolved_at = NOW()
+WHERE source_id = @source_id
+AND status = 'pending'
+AND expires_at <= NOW();
+
+-- name: GetMergeProposal :one
+-- Proposals that are still pending past their expiry are reported as expired
+SELECT
+    id,
+    source_id::text AS source_id,
+    target_id::text AS target_id,
+    proposed_by,
+    (CASE
+        WHEN status = 'pending' AND expires_at <= NOW() THEN 'expired'
+        ELSE status
+    END)::text AS status,
+    expires_at,
+    created_at
+FROM MergeProposals
+WHERE id = @id
+AND (source_id = @group_id OR target_id = @group_id);
+
+-- name: GetMergeProposals :many
+-- Pending proposals that the group made or was sent
+SELECT
+    id,
+    source_id::text AS source_id,
+    target_id::text AS target_id,
+    proposed_by,
+    status,
+    expires_at,
+    created_at
+FROM MergeProposals
+WHERE (source_id = @group_id OR target_id = @group_id)
+AND status = 'pending'
+AND expires_at > NOW()
+ORDER BY created_at;
+
+-- name: ResolveMergeProposal :execrows
+-- Only pending proposals can be resolved, so that each one is accepted or declined at most once
+UPDATE MergeProposals
+SET
+    status = @status,
+    resolved_at = NOW()
+WHERE id = @id
+AND status = 'pending';
@@ -0,0 +1,126 @@
+-- name: CreateParty :one
+WITH 
+-- Players can't create a party while they're in one
+party_check AS (
+    SELECT 1 FROM PartyMembers
+    WHERE player_id = @leader_id
+    LIMIT 1
+),
+new_party AS (
+    INSERT INTO Parties (leader_id)
+    SELECT @leader_id
+    WHERE NOT EXISTS (SELECT 1 FROM party_check)
+    RETURNING id
+),
+new_member AS (
+    INSERT INTO PartyMembers (party_id, player_id, roles, session_id)
+    SELECT id, @leader_id, @roles::text[], NULLIF(@session_id::TEXT, '')
+    FROM new_party
+    RETURNING party_id
+)
+SELECT 
+    CASE
+        WHEN EXISTS (SELECT 1 FROM party_check) THEN '400a'
+        WHEN EXISTS (SELECT 1 FROM new_member) THEN '200'
+        ELSE '500'
+    END as status,
+    COALESCE((SELECT party_id FROM new_member), '')::text as party_id;
+
+-- name: JoinParty :one
+WITH 
+party_check AS (
+    SELECT 1 FROM PartyMembers
+    WHERE player_id = @player_id
+    LIMIT 1
+),
+party_size AS (
+    SELECT COUNT(*) as member_count
+    FROM PartyMembers
+    WHERE party_id = @party_id
+),
+new_member AS (
+    INSERT INTO PartyMembers (party_id, player_id, roles, session_id)
+    SELECT @party_id, @player_id, @roles::text[], NULLIF(@session_id::TEXT, '')
+    WHERE EXISTS (SELECT 1 FROM Parties WHERE id = @party_id)
+    AND NOT EXISTS (SELECT 1 FROM party_check)
+    AND (SELECT member_count FROM party_size) < @max_size::integer
+    RETURNING player_id
+)
+SELECT 
+    CASE
+        WHEN EXISTS (SELECT 1 FROM party_check) THEN '400a'
+        WHEN NOT EXISTS (SELECT 1 FROM Parties WHERE id = @party_id) THEN '404'
+        WHEN EXISTS (SELECT 1 FROM new_member) THEN '200'
+        WHEN (SELECT member_count FROM party_size) >= @max_size::integer THEN '400f'
+        ELSE '500'
+    END as status;
+
+-- name: LeaveParty :one
+WITH 
+member_check AS (
+    SELECT pm.party_id, p.leader_id
+    FROM PartyMembers pm
+    JOIN Parties p ON p.id = pm.party_id
+    WHERE pm.party_id = @party_id
+    AND pm.player_id = @player_id
+),
+-- Longest standing member takes over if the leader leaves
+next_leader AS (
+    SELECT pm.player_id
+    FROM PartyMembers pm
+    WHERE pm.party_id = @party_id
+    AND pm.player_id != @player_id
+    ORDER BY pm.joined_at
+    LIMIT 1
+),
+remove_member AS (
+    DELETE FROM PartyMembers
+    WHERE party_id = @party_id
+    AND player_id = @player_id
+    AND EXISTS (SELECT 1 FROM member_check)
+    RETURNING player_id
+),
+promote_leader AS (
+    UPDATE Parties
+    SET leader_id = (SELECT player_id FROM next_leader)
+    WHERE id = @party_id
+    AND EXISTS (SELECT 1 FROM member_check WHERE leader_id = @player_id)
+    AND EXISTS (SELECT 1 FROM next_leader)
+    RETURNING id
+),
+delete_empty_party AS (
+    DELETE FROM Parties
+    WHERE id = @party_id
+    AND EXISTS (SELECT 1 FROM member_check)
+    AND NOT EXISTS (SELECT 1 FROM next_leader)
+    RETURNING id
+)
+SELECT 
+    CASE
+        WHEN NOT EXISTS (SELECT 1 FROM member_check) THEN '404'
+        WHEN NOT EXISTS (SELECT 1 FROM next_leader) THEN '204'
+        ELSE '200'
+    END as status;
+
+-- name: GetPartyMembers :many
+-- Members are returned in the order they joined, along with the group they're currently in
+SELECT 
+    pm.party_id::text,
+    p.leader_id,
+    pm.player_id,
+    pl.name,
+    pl.platform,
+    pl.role,
+    pl.rank,
+    pl.characters,
+    pl.voice_chat,
+    pl.mic,
+    pm.roles,
+    gm.group_id,
+    pm.session_id
+FROM PartyMembers pm
+JOIN Parties p ON p.id = pm.party_id
+JOIN Players pl ON pl.id = pm.player_id
+LEFT JOIN GroupMembers gm ON gm.player_id = pm.player_id
+WHERE pm.party_id = @party_id
+ORDER BY pm.joined_at, pm.player_id;
@@ -7,6 +7,14 @@ player_check AS (
     LIMIT 1
 ),
 
+-- Banned players can't rejoin, even as a new player from the same session
+ban_check AS (
+    SELECT 1 FROM GroupBans b
+    WHERE b.group_id = @group_id
+    AND (b.player_id = @player_id OR b.session_id = NULLIF(@session_id::TEXT, ''))
+    LIMIT 1
+),
+
 group_members_base AS (
     SELECT 
         gm.group_id,
@@ -14,7 +22,7 @@ group_members_base AS (
         p.name,
         gm.leader,
         p.platform,
-        LOWER(p.role) as role,
+        gm.role,
         p.rank as rank_val,
         p.characters,
         p.voice_chat,
@@ -26,15 +34,74 @@ group_members_base AS (
 group_details AS (
     SELECT 
         group_id,
+        COUNT(*) as member_count,
         COUNT(CASE WHEN role = 'vanguard' THEN 1 END) as curr_vanguards,
         COUNT(CASE WHEN role = 'duelist' THEN 1 END) as curr_duelists,
         COUNT(CASE WHEN role = 'strategist' THEN 1 END) as curr_strategists,
-        MIN(rank_val) as min_rank,
-        MAX(rank_val) as max_rank
+        array_agg(rank_val) as ranks
     FROM group_members_base
     GROUP BY group_id
 ),
 
+-- Slots held for waitlisted players stay taken until the offer lapses, unless it's the player's own
+held_offers AS (
+    SELECT
+        COUNT(*) as held_count,
+        COUNT(CASE WHEN w.offered_role = 'vanguard' THEN 1 END) as held_vanguards,
+        COUNT(CASE WHEN w.offered_role = 'duelist' THEN 1 END) as held_duelists,
+        COUNT(CASE WHEN w.offered_role = 'strategist' THEN 1 END) as held_strategists
+    FROM GroupWaitlist w
+    WHERE w.group_id = @group_id
+    AND w.offer_expires_at > NOW()
+    AND w.player_id <> @player_id
+),
+
+-- Pick the first of the player's preferred roles that has an open slot
+seat AS (
+    SELECT pref.role
+    FROM Groups g
+    JOIN group_details gd ON gd.group_id = g.id
+    CROSS JOIN held_offers ho
+    CROSS JOIN unnest(@roles::text[]) WITH ORDINALITY AS pref(role, ord)
+    WHERE g.id = @group_id
+    AND (
+        (g.vanguards + g.duelists + g.strategists = 0)
+        OR (pref.role = 'vanguard' AND gd.curr_vanguards + ho.held_vanguards < g.vanguards)
+        OR (pref.role = 'duelist' AND gd.curr_duelists + ho.held_duelists < g.duelists)
+        OR (pref.role = 'strategist' AND gd.curr_strategists + ho.held_strategists < g.strategists)
+    )
+    ORDER BY pref.ord
+    LIMIT 1
+),
+
+-- Groups can't grow past their role queue, or the gamemode's team size without one
+full_check AS (
+    SELECT 1
+    FROM Groups g
+    JOIN group_details gd ON gd.group_id = g.id
+    CROSS JOIN held_offers ho
+    WHERE g.id = @group_id
+    AND gd.member_count + ho.held_count >= LEAST(
+        @team_size::integer,
+        CASE
+            WHEN g.vanguards + g.duelists + g.strategists = 0 THEN @team_size::integer
+            ELSE g.vanguards + g.duelists + g.strategists
+        END
+    )
+),
+
+-- An invite lets the player in without the passcode, as long as it has uses left
+valid_invite AS (
+    SELECT i.id
+    FROM GroupInvites i
+    WHERE i.token = @invite
+    AND i.group_id = @group_id
+    AND i.revoked_at IS NULL
+    AND i.expires_at > NOW()
+    AND i.uses < i.max_uses
+    FOR UPDATE
+),
+
 -- Check all requirements in a single query
 valid_group AS (
     SELECT g.id
@@ -44,30 +111,30 @@ valid_group AS (
     AND g.region = @region
     -- Platform check
     AND g.platform = @platform
-    -- Role queue check (only if enabled)
-    AND (
-        (g.vanguards + g.duelists + g.strategists = 0)
-        OR
-        (
-            -- Can fill at least one role
-            (@role = 'vanguard' AND gd.curr_vanguards < g.vanguards)
-            OR (@role = 'duelist' AND gd.curr_duelists < g.duelists)
-            OR (@role = 'strategist' AND gd.curr_strategists < g.strategists)
-        )
+    -- Role queue check, the player must fit into one of their preferred roles
+    AND EXISTS (SELECT 1 FROM seat)
+    -- Rank check, every member must be within the ranks allowed by the gamemode's rank policy
+    AND gd.ranks <@ @allowed_ranks::integer[]
+    -- Community rank check, the player must be within the ranks that the group's community allows
+    AND NOT EXISTS (
+        SELECT 1 FROM Community c
+        WHERE c.id = g.community_id
+        AND (@rank_val::integer < c.min_rank OR @rank_val::integer > c.max_rank)
     )
-    -- Rank check
-    AND (
-        -- Allow Bronze-Gold players to group with each other
-        @rank_val BETWEEN 0 AND 22 AND gd.min_rank BETWEEN 0 AND 22
-        OR (
-            ABS(gd.min_rank - @rank_val) <= 10
-            AND ABS(gd.max_rank - @rank_val) <= 10
-        )
-    )
-    -- If group is not open, check if passcode is correct
+    -- Scheduled groups are RSVP'd to until they start
+    AND NOT g.scheduled
+    -- Banned players are turned away
+    AND NOT EXISTS (SELECT 1 FROM ban_check)
+    -- Capacity check
+    AND NOT EXISTS (SELECT 1 FROM full_check)
+    -- Voice chat and mic
+    AND (NOT g.voice_chat OR @voice_chat)
+    AND (NOT g.mic OR @mic)
+    -- If group is not open, check if passcode or invite is correct
     AND (
         g.open 
         OR (NOT g.open AND g.passcode = @passcode)
+        OR EXISTS (SELECT 1 FROM valid_invite)
     )
     LIMIT 1
 ),
@@ -109,7 +176,9 @@ group_member_creation AS (
     INSERT INTO GroupMembers (
         group_id,
         player_id,
-        leader
+        leader,
+        role,
+        session_id
     )
     SELECT 
         @group_id,
@@ -117,10 +186,29 @@ group_member_creation AS (
             (SELECT id FROM player_creation),
             @player_id
         ),
-        false
+        false,
+        (SELECT role FROM seat),
+        NULLIF(@session_id::TEXT, '')
     WHERE EXISTS (SELECT 1 FROM valid_group)
     AND NOT EXISTS (SELECT 1 FROM player_check)
     RETURNING player_id
+),
+
+-- Use up the invite that the player joined with, and remember who redeemed it
+invite_redemption AS (
+    UPDATE GroupInvites
+    SET uses = uses + 1
+    WHERE id = (SELECT id FROM valid_invite)
+    AND EXISTS (SELECT 1 FROM group_member_creation)
+    RETURNING id
+),
+
+redemption_creation AS (
+    INSERT INTO GroupInviteRedemptions (invite_id, player_id)
+    SELECT ir.id, gmc.player_id
+    FROM invite_redemption ir, group_member_creation gmc
+    ON CONFLICT (invite_id, player_id) DO UPDATE SET redeemed_at = NOW()
+    RETURNING invite_id
 )
 
 -- Return status code
@@ -128,13 +216,17 @@ SELECT
     CASE
         WHEN EXISTS (SELECT 1 FROM player_check) THEN '400a'
         WHEN NOT EXISTS (SELECT 1 FROM Groups g WHERE g.id = @group_id) THEN '404'
+        WHEN EXISTS (SELECT 1 FROM ban_check) THEN '403b'
         WHEN EXISTS (SELECT 1 FROM group_member_creation) THEN '200'
+        WHEN EXISTS (SELECT 1 FROM Groups g WHERE g.id = @group_id AND g.scheduled) THEN '400s'
         WHEN EXISTS (
             SELECT 1 FROM Groups g 
             WHERE g.id = @group_id
             AND NOT g.open 
             AND g.passcode != @passcode
+            AND NOT EXISTS (SELECT 1 FROM valid_invite)
         ) THEN '403'
+        WHEN EXISTS (SELECT 1 FROM full_check) THEN '400f'
         WHEN NOT EXISTS (SELECT 1 FROM valid_group) THEN '400e'
         ELSE '500'
     END as status,
@@ -208,6 +300,19 @@ remove_member AS (
     AND EXISTS (SELECT 1 FROM group_check)
     RETURNING group_id
 ),
+ban_creation AS (
+    -- Ban the player from rejoining if asked, unless the group is going away
+    INSERT INTO GroupBans (group_id, player_id, session_id, banned_by)
+    SELECT gc.group_id, gc.player_id, gc.session_id, @banned_by::INTEGER
+    FROM group_check gc
+    WHERE @ban::BOOLEAN
+    AND NOT EXISTS (SELECT 1 FROM is_last_member WHERE is_last)
+    ON CONFLICT (group_id, player_id) DO UPDATE SET
+        session_id = EXCLUDED.session_id,
+        banned_by = EXCLUDED.banned_by,
+        created_at = NOW()
+    RETURNING player_id
+),
 delete_empty_group AS (
     -- Delete group if this was the last member
     DELETE FROM Groups g
@@ -227,4 +332,56 @@ SELECT
     COALESCE(
         (SELECT player_id FROM next_leader)::INTEGER,
         0
-    ) as new_leader_id;
\ No newline at end of file
+    ) as new_leader_id;
+
+-- name: SetPlayerCharacters :exec
+UPDATE Players
+SET characters = @characters
+WHERE id = @id;
+
+-- name: UpsertPlayer :one
+-- Creates the player if they don't exist yet, otherwise refreshes their profile
+INSERT INTO Players (
+    id,
+    name,
+    platform,
+    role,
+    rank,
+    characters,
+    voice_chat,
+    mic,
+    vanguards,
+    duelists,
+    strategists
+)
+VALUES (
+    COALESCE(NULLIF(@id::integer, 0), nextval(pg_get_serial_sequence('players', 'id'))),
+    @name,
+    @platform,
+    @role,
+    @rank,
+    @characters,
+    @voice_chat,
+    @mic,
+    @vanguards,
+    @duelists,
+    @strategists
+)
+ON CONFLICT (id) DO UPDATE SET
+    name = EXCLUDED.name,
+    platform = EXCLUDED.platform,
+    role = EXCLUDED.role,
+    rank = EXCLUDED.rank,
+    characters = EXCLUDED.characters,
+    voice_chat = EXCLUDED.voice_chat,
+    mic = EXCLUDED.mic,
+    vanguards = EXCLUDED.vanguards,
+    duelists = EXCLUDED.duelists,
+    strategists = EXCLUDED.strategists
+RETURNING id;
+
+-- name: GetPlayerGroup :one
+SELECT group_id::text
+FROM GroupMembers
+WHERE player_id = @player_id
+LIMIT 1;
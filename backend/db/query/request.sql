@@ -0,0 +1,90 @@
+-- name: CreateJoinRequest :one
+INSERT INTO JoinRequests (
+    group_id,
+    player_id,
+    roles,
+    session_id,
+    expires_at
+)
+VALUES (
+    @group_id,
+    @player_id,
+    @roles,
+    NULLIF(@session_id::TEXT, ''),
+    @expires_at
+)
+RETURNING *;
+
+-- name: ExpireJoinRequests :exec
+-- Marks the player's requests that ran out as expired, so that they no longer count as pending
+UPDATE JoinRequests
+SET
+    status = 'expired',
+    resolved_at = NOW()
+WHERE player_id = @player_id
+AND status = 'pending'
+AND expires_at <= NOW();
+
+-- name: GetPendingJoinRequest :one
+SELECT id, group_id::text
+FROM JoinRequests
+WHERE player_id = @player_id
+AND status = 'pending'
+AND expires_at > NOW();
+
+-- name: GetJoinRequest :one
+-- Requests that are still pending past their expiry are reported as expired
+SELECT
+    r.id,
+    r.group_id::text AS group_id,
+    r.player_id,
+    p.name,
+    p.platform,
+    p.rank,
+    p.characters,
+    p.voice_chat,
+    p.mic,
+    r.roles,
+    r.session_id,
+    (CASE
+        WHEN r.status = 'pending' AND r.expires_at <= NOW() THEN 'expired'
+        ELSE r.status
+    END)::text AS status,
+    r.expires_at,
+    r.created_at
+FROM JoinRequests r
+JOIN Players p ON p.id = r.player_id
+WHERE r.id = @id
+AND r.group_id = @group_id;
+
+-- name: GetJoinRequests :many
+SELECT
+    r.id,
+    r.group_id::text AS group_id,
+    r.player_id,
+    p.name,
+    p.platform,
+    p.rank,
+    p.characters,
+    p.voice_chat,
+    p.mic,
+    r.roles,
+    r.session_id,
+    r.status,
+    r.expires_at,
+    r.created_at
+FROM JoinRequests r
+JOIN Players p ON p.id = r.player_id
+WHERE r.group_id = @group_id
+AND r.status = 'pending'
+AND r.expires_at > NOW()
+ORDER BY r.created_at;
+
+-- name: ResolveJoinRequest :execrows
+-- Only pending requests can be resolved, so that each one is approved, denied or cancelled at most once
+UPDATE JoinRequests
+SET
+    status = @status,
+    resolved_at = NOW()
+WHERE id = @id
+AND status = 'pending';
@@ -0,0 +1,44 @@
+-- name: AddRsvp :one
+-- Players can only RSVP to a group once, so doing it again is a no-op
+INSERT INTO GroupRsvps (
+    group_id,
+    player_id,
+    role,
+    session_id
+)
+VALUES (
+    @group_id,
+    @player_id,
+    @role,
+    NULLIF(@session_id::TEXT, '')
+)
+ON CONFLICT (group_id, player_id) DO NOTHING
+RETURNING *;
+
+-- name: DeleteRsvps :exec
+DELETE FROM GroupRsvps
+WHERE group_id = @group_id;
+
+-- name: GetRsvps :many
+SELECT
+    r.id,
+    r.group_id::text AS group_id,
+    r.player_id,
+    p.name,
+    p.platform,
+    p.rank,
+    p.characters,
+    p.voice_chat,
+    p.mic,
+    r.role,
+    r.session_id,
+    r.created_at
+FROM GroupRsvps r
+JOIN Players p ON p.id = r.player_id
+WHERE r.group_id = @group_id
+ORDER BY r.id;
+
+-- name: RemoveRsvp :execrows
+DELETE FROM GroupRsvps
+WHERE group_id = @group_id
+AND player_id = @player_id;
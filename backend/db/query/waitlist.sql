@@ -0,0 +1,64 @@
+-- name: AddToWaitlist :one
+-- Players can only wait on a group once, so joining again is a no-op
+INSERT INTO GroupWaitlist (
+    group_id,
+    player_id,
+    role,
+    roles,
+    session_id
+)
+VALUES (
+    @group_id,
+    @player_id,
+    NULLIF(@role::TEXT, ''),
+    @roles,
+    NULLIF(@session_id::TEXT, '')
+)
+ON CONFLICT (group_id, player_id) DO NOTHING
+RETURNING *;
+
+-- name: ClearLapsedOffers :exec
+-- Players who let their offer lapse lose their place, so that the slot passes down the list
+DELETE FROM GroupWaitlist
+WHERE group_id = @group_id
+AND offer_expires_at <= NOW();
+
+-- name: GetLapsedOfferGroups :many
+SELECT DISTINCT group_id::text
+FROM GroupWaitlist
+WHERE offer_expires_at <= NOW();
+
+-- name: GetWaitlist :many
+SELECT
+    w.id,
+    w.group_id::text AS group_id,
+    w.player_id,
+    p.name,
+    p.platform,
+    p.rank,
+    p.characters,
+    p.voice_chat,
+    p.mic,
+    w.role,
+    w.roles,
+    w.session_id,
+    w.created_at,
+    w.offered_role,
+    w.offer_expires_at
+FROM GroupWaitlist w
+JOIN Players p ON p.id = w.player_id
+WHERE w.group_id = @group_id
+ORDER BY w.id;
+
+-- name: OfferWaitlistSlot :exec
+UPDATE GroupWaitlist
+SET
+    offered_role = @offered_role,
+    offer_expires_at = @offer_expires_at
+WHERE id = @id;
+
+-- name: RemoveFromWaitlist :one
+DELETE FROM GroupWaitlist
+WHERE group_id = @group_id
+AND player_id = @player_id
+RETURNING offer_expires_at;
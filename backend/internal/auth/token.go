@@ -2,6 +2,7 @@ package auth
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,18 +13,31 @@ func getSecretKey() []byte {
 	return env.GetBytes("JWT_SECRET_KEY", []byte("you cant skip lunch"))
 }
 
-func GenerateToken(subject string, additionalClaims map[string]string, additionalRights ...Right) (string, error) {
+// NewSessionID returns a random ID for a new session. Sessions are carried over
+// when tokens are reissued, so they identify the player's device for as long
+// as it keeps its token.
+func NewSessionID() (string, error) {
 	sessionID := make([]byte, 32)
 	if _, err := rand.Read(sessionID); err != nil {
 		return "", err
 	}
+	return hex.EncodeToString(sessionID), nil
+}
+
+// GenerateToken starts a new session, unless a sessionId is given in additionalClaims.
+func GenerateToken(subject string, additionalClaims map[string]string, additionalRights ...Right) (string, error) {
+	sessionID, err := NewSessionID()
+	if err != nil {
+		return "", err
+	}
 
 	rights := append(BaseRights, additionalRights...)
 	claims := jwt.MapClaims{
-		"sub":    subject,
-		"iat":    time.Now().Unix(),
-		"exp":    time.Now().Add(24 * time.Hour).Unix(),
-		"rights": rights,
+		"sub":       subject,
+		"iat":       time.Now().Unix(),
+		"exp":       time.Now().Add(24 * time.Hour).Unix(),
+		"rights":    rights,
+		"sessionId": sessionID,
 	}
 	for k, v := range additionalClaims {
 		claims[k] = v
@@ -20,6 +20,27 @@ func TestTokenService(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotEmpty(t, token)
 		})
+
+		t.Run("StartsNewSession", func(t *testing.T) {
+			first, _ := GenerateToken("1", nil)
+			second, _ := GenerateToken("1", nil)
+			firstClaims, err := ValidateToken(first)
+			assert.NoError(t, err)
+			secondClaims, err := ValidateToken(second)
+			assert.NoError(t, err)
+
+			assert.NotEmpty(t, firstClaims["sessionId"])
+			assert.NotEqual(t, firstClaims["sessionId"], secondClaims["sessionId"])
+		})
+
+		t.Run("CarriesOverSession", func(t *testing.T) {
+			token, _ := GenerateToken("1", map[string]string{
+				"sessionId": "abc",
+			})
+			claims, err := ValidateToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "abc", claims["sessionId"])
+		})
 	})
 
 	t.Run("ValidateToken", func(t *testing.T) {
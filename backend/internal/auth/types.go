@@ -40,7 +40,8 @@ var GroupOwnerRights = []Right{
 	RightLeaveGroup,
 }
 
-// TODO: How do we return the updated token after promoting a player?
+// When ownership changes hands, the previous owner is reissued GroupMemberRights
+// in the response, and the new owner is sent GroupOwnerRights over the websocket.
 
 func IsEqual(s string, r Right) bool {
 	return string(r) == s
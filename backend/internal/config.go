@@ -2,6 +2,7 @@ package internal
 
 import (
 	"errors"
+	"time"
 
 	"github.com/jcserv/rivalslfg/internal/utils/env"
 )
@@ -13,6 +14,16 @@ type Configuration struct {
 	DatabaseURL  string
 	CacheURL     string
 	JWTSecretKey string
+
+	// Groups are deleted once nobody has been active in them for GroupIdleTTL,
+	// after warning their members GroupIdleWarning beforehand. They're hidden
+	// from browsing once they've been idle for GroupStaleAfter.
+	GroupIdleTTL     time.Duration
+	GroupIdleWarning time.Duration
+	GroupStaleAfter  time.Duration
+
+	// CommunityAdmins are the players who can create communities, before anyone moderates one.
+	CommunityAdmins []int32
 }
 
 func NewConfiguration() (*Configuration, error) {
@@ -23,6 +34,20 @@ func NewConfiguration() (*Configuration, error) {
 	cfg.DatabaseURL = env.GetString("DATABASE_URL", "")
 	cfg.CacheURL = env.GetString("CACHE_URL", "")
 	cfg.JWTSecretKey = env.GetString("JWT_SECRET_KEY", "")
+
+	var err error
+	if cfg.GroupIdleTTL, err = env.GetDuration("GROUP_IDLE_TTL", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.GroupIdleWarning, err = env.GetDuration("GROUP_IDLE_WARNING", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.GroupStaleAfter, err = env.GetDuration("GROUP_STALE_AFTER", 30*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.CommunityAdmins, err = env.GetInt32s("COMMUNITY_ADMIN_PLAYER_IDS"); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
@@ -36,5 +61,14 @@ func (c *Configuration) Validate() error {
 	if c.JWTSecretKey == "" {
 		return errors.New("JWT_SECRET_KEY is required")
 	}
+	if c.GroupIdleTTL <= 0 {
+		return errors.New("GROUP_IDLE_TTL must be positive")
+	}
+	if c.GroupIdleWarning < 0 || c.GroupIdleWarning >= c.GroupIdleTTL {
+		return errors.New("GROUP_IDLE_WARNING must be less than GROUP_IDLE_TTL")
+	}
+	if c.GroupStaleAfter <= 0 {
+		return errors.New("GROUP_STALE_AFTER must be positive")
+	}
 	return nil
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -15,7 +16,7 @@ WITH group_members_base AS (
         p.name,
         gm.leader,
         p.platform,
-        LOWER(p.role) as role,
+        gm.role,
         p.rank as rank_val,
         rank_value_to_id(p.rank) as rank_id,
         p.characters,
@@ -42,8 +43,7 @@ group_details AS (
                 'mic', mic
             )
         ) as players,
-        MIN(rank_val) as min_rank,
-        MAX(rank_val) as max_rank,
+        array_agg(rank_val) as ranks,
         COUNT(CASE WHEN role = 'vanguard' THEN 1 END) as curr_vanguards,
         COUNT(CASE WHEN role = 'duelist' THEN 1 END) as curr_duelists,
         COUNT(CASE WHEN role = 'strategist' THEN 1 END) as curr_strategists
@@ -65,32 +65,55 @@ requirements_check AS (
                 ELSE TRUE
             END
         )
+        AND ($17::INTEGER IS NULL OR g.community_id = $17)
+        -- Scheduled groups are only listed when browsing by start time, since they can't be joined until then.
+        -- They're listed if they're played at any point in the window, so groups that run into it are included.
+        AND CASE
+            WHEN $18::TIMESTAMPTZ IS NULL AND $19::TIMESTAMPTZ IS NULL THEN NOT g.scheduled
+            ELSE g.scheduled
+                AND ($18::TIMESTAMPTZ IS NULL OR g.starts_at + make_interval(mins => COALESCE(g.duration_minutes, 0)) >= $18)
+                AND ($19::TIMESTAMPTZ IS NULL OR g.starts_at <= $19)
+        END
+        -- Groups must have every tag in $20, any of the tags in $21, and one of the languages in $22
+        AND (COALESCE(cardinality($20::TEXT[]), 0) = 0 OR g.tags @> $20)
+        AND (COALESCE(cardinality($21::TEXT[]), 0) = 0 OR g.tags && $21)
+        AND (COALESCE(cardinality($22::TEXT[]), 0) = 0 OR g.language = ANY($22))
+        -- Groups that have been idle for too long are hidden, unless they haven't started yet
+        AND ($14::TIMESTAMPTZ IS NULL OR g.scheduled OR g.last_active_at >= $14)
+        -- Groups that the player is banned from are hidden
+        AND NOT EXISTS (
+            SELECT 1 FROM GroupBans b
+            WHERE b.group_id = g.id
+            AND (b.player_id = $15::INTEGER OR b.session_id = $16::TEXT)
+        )
         -- Player requirements check
         AND CASE 
             -- If rank value is provided, use it as a trigger for all player requirements
             WHEN $8::INTEGER IS NOT NULL THEN (
                 -- Platform check
                 g.platform = $4
-                -- Role queue check
+                -- Role queue check, at least one of the player's preferred roles must have an open slot
                 AND (
                     g.vanguards + g.duelists + g.strategists = 0
-                    OR (
-                        CASE $5::TEXT
-                            WHEN 'vanguard' THEN gd.curr_vanguards < g.vanguards 
+                    OR EXISTS (
+                        SELECT 1 FROM unnest($5::TEXT[]) AS pref(role)
+                        WHERE CASE pref.role
+                            WHEN 'vanguard' THEN gd.curr_vanguards < g.vanguards
                             WHEN 'duelist' THEN gd.curr_duelists < g.duelists
                             WHEN 'strategist' THEN gd.curr_strategists < g.strategists
                             ELSE FALSE
                         END
                     )
                 )
-                -- Rank check
-                AND (
-                    -- Allow Bronze-Gold players to group with each other
-                    ($8::INTEGER BETWEEN 0 AND 22 AND gd.min_rank BETWEEN 0 AND 22)
-                    OR (
-                        ABS(gd.min_rank - $8::INTEGER) <= 10 
-                        AND ABS(gd.max_rank - $8::INTEGER) <= 10
-                    )
+                -- Rank check, every member must be within the ranks allowed by the gamemode's rank policy
+                AND gd.ranks <@ ARRAY(
+                    SELECT jsonb_array_elements_text($13::jsonb -> g.gamemode)::INTEGER
+                )
+                -- Community rank check, the player must be within the ranks that the group's community allows
+                AND NOT EXISTS (
+                    SELECT 1 FROM Community c
+                    WHERE c.id = g.community_id
+                    AND ($8::INTEGER < c.min_rank OR $8::INTEGER > c.max_rank)
                 )
                 -- Voice chat and mic
                 AND (NOT g.voice_chat OR $6::BOOLEAN)
@@ -107,6 +130,7 @@ SELECT
     g.region,
     g.gamemode,
     open,
+    g.request_to_join,
     jsonb_build_object(
         'vanguards', g.vanguards,
         'duelists', g.duelists,
@@ -124,7 +148,16 @@ SELECT
         FROM Groups g2
         WHERE g2.id IN (SELECT group_id FROM requirements_check)
     ) ELSE 0 END as total_count,
-    g.last_active_at
+    g.last_active_at,
+    (SELECT c.min_rank FROM Community c WHERE c.id = g.community_id) AS min_rank,
+    (SELECT c.max_rank FROM Community c WHERE c.id = g.community_id) AS max_rank,
+    g.scheduled,
+    g.starts_at,
+    g.duration_minutes,
+    (SELECT COUNT(*) FROM GroupRsvps r WHERE r.group_id = g.id) AS rsvps,
+    g.description,
+    g.language,
+    g.tags
 FROM Groups g
 JOIN group_details gd ON g.id = gd.group_id
 WHERE g.id IN (SELECT group_id FROM requirements_check)
@@ -135,8 +168,18 @@ LIMIT $11 OFFSET $12;
 `
 
 type GetGroupsParams struct {
-	RegionFilter   string `json:"regionFilter"`
-	GamemodeFilter string `json:"gamemodeFilter"`
+	RegionFilter    string `json:"regionFilter"`
+	GamemodeFilter  string `json:"gamemodeFilter"`
+	CommunityFilter *int32 `json:"communityFilter"`
+
+	// Lists scheduled groups that are played within the window instead of live groups, if either end is set
+	StartsAfter  *time.Time `json:"startsAfter"`
+	StartsBefore *time.Time `json:"startsBefore"`
+
+	// Groups must have all of TagsFilter, any of AnyTagsFilter, and one of LanguagesFilter, if they're set
+	TagsFilter      []string `json:"tagsFilter"`
+	AnyTagsFilter   []string `json:"anyTagsFilter"`
+	LanguagesFilter []string `json:"languagesFilter"`
 
 	// OpenFilter is a string to account for when we don't want to filter
 	OpenFilter string `json:"openFilter"`
@@ -146,11 +189,36 @@ type GetGroupsParams struct {
 	Count      bool   `json:"count"`
 
 	// Player requirements (all optional)
-	Platform  *string `json:"platform"`
-	Role      *string `json:"role"`
-	RankVal   *int32  `json:"rankVal"`
-	VoiceChat *bool   `json:"voiceChat"`
-	Mic       *bool   `json:"mic"`
+	Platform  *string  `json:"platform"`
+	Role      *string  `json:"role"`
+	Roles     []string `json:"roles"` // Preferred roles, takes precedence over Role if set
+	RankVal   *int32   `json:"rankVal"`
+	VoiceChat *bool    `json:"voiceChat"`
+	Mic       *bool    `json:"mic"`
+
+	// Ranks that the player can group with, keyed by gamemode. Set from RankVal by the group service, unless given.
+	AllowedRanks map[string][]int32 `json:"allowedRanks"`
+
+	// Sorts by how well the player's roles and character pool round out each group, done by the group service.
+	CompositionSort string   `json:"compositionSort"`
+	Characters      []string `json:"characters"`
+
+	// Hides groups that haven't been active since then, set by the group service.
+	ActiveSince *time.Time `json:"activeSince"`
+
+	// Hides groups that the player is banned from, by their ID or session.
+	PlayerID  *int32  `json:"playerId"`
+	SessionID *string `json:"sessionId"`
+}
+
+func (arg GetGroupsParams) rolePreferences() []string {
+	if len(arg.Roles) > 0 {
+		return arg.Roles
+	}
+	if arg.Role != nil {
+		return []string{*arg.Role}
+	}
+	return nil
 }
 
 type GetGroupsRow struct {
@@ -168,14 +236,28 @@ func (g *GetGroupsRow) ToGroupWithPlayers() GroupWithPlayers {
 			Region:        g.Region,
 			Gamemode:      g.Gamemode,
 			Open:          g.Open,
+			RequestToJoin: g.RequestToJoin,
 			Passcode:      g.Passcode,
 			RoleQueue:     g.RoleQueue,
 			GroupSettings: g.GroupSettings,
 			LastActiveAt:  g.LastActiveAt,
+			MinRank:       g.MinRank,
+			MaxRank:       g.MaxRank,
+
+			Scheduled:       g.Scheduled,
+			StartsAt:        g.StartsAt,
+			DurationMinutes: g.DurationMinutes,
+
+			Description: g.Description,
+			Language:    g.Language,
+			Tags:        g.Tags,
 		},
-		Name:    g.Name,
-		Size:    g.Size,
-		Players: g.Players,
+		Name:           g.Name,
+		Size:           g.Size,
+		SlotsRemaining: g.SlotsRemaining,
+		Full:           g.Full,
+		Rsvps:          g.Rsvps,
+		Players:        g.Players,
 	}
 }
 
@@ -190,7 +272,7 @@ func (q *Queries) GetGroups(ctx context.Context, arg GetGroupsParams) (*GetGroup
 		arg.GamemodeFilter,
 		arg.OpenFilter,
 		arg.Platform,
-		arg.Role,
+		arg.rolePreferences(),
 		arg.VoiceChat,
 		arg.Mic,
 		arg.RankVal,
@@ -198,6 +280,16 @@ func (q *Queries) GetGroups(ctx context.Context, arg GetGroupsParams) (*GetGroup
 		arg.SizeSort,
 		arg.Limit,
 		arg.Offset,
+		arg.AllowedRanks,
+		arg.ActiveSince,
+		arg.PlayerID,
+		arg.SessionID,
+		arg.CommunityFilter,
+		arg.StartsAfter,
+		arg.StartsBefore,
+		arg.TagsFilter,
+		arg.AnyTagsFilter,
+		arg.LanguagesFilter,
 	)
 	if err != nil {
 		return nil, err
@@ -219,16 +311,27 @@ func (q *Queries) GetGroups(ctx context.Context, arg GetGroupsParams) (*GetGroup
 			&g.Region,
 			&g.Gamemode,
 			&g.Open,
+			&g.RequestToJoin,
 			&g.RoleQueue,
 			&g.GroupSettings,
 			&g.Players,
 			&g.Size,
 			&g.TotalCount,
 			&g.LastActiveAt,
+			&g.MinRank,
+			&g.MaxRank,
+			&g.Scheduled,
+			&g.StartsAt,
+			&g.DurationMinutes,
+			&g.Rsvps,
+			&g.Description,
+			&g.Language,
+			&g.Tags,
 		); err != nil {
 			return nil, err
 		}
 		g.Name = fmt.Sprintf("%s's Group", g.Owner)
+		g.SetSlots()
 		result.TotalCount = g.TotalCount
 		result.Groups = append(result.Groups, g.ToGroupWithPlayers())
 	}
@@ -243,7 +346,7 @@ WITH group_members AS (
         p.id as player_id,
         p.name,
         p.platform,
-        p.role,
+        gm.role,
         rank_value_to_id(p.rank) as rank,
         p.characters,
         p.voice_chat,
@@ -265,6 +368,7 @@ SELECT
     g.region,
     g.gamemode,
     open,
+    g.request_to_join,
     g.passcode,
     jsonb_build_object(
         'vanguards', g.vanguards,
@@ -293,7 +397,16 @@ SELECT
         '[]'::jsonb
     ) as players,
     COUNT(gm.player_id) AS size,
-    g.last_active_at
+    g.last_active_at,
+    (SELECT c.min_rank FROM Community c WHERE c.id = g.community_id) AS min_rank,
+    (SELECT c.max_rank FROM Community c WHERE c.id = g.community_id) AS max_rank,
+    g.scheduled,
+    g.starts_at,
+    g.duration_minutes,
+    (SELECT COUNT(*) FROM GroupRsvps r WHERE r.group_id = g.id) AS rsvps,
+    g.description,
+    g.language,
+    g.tags
 FROM Groups g
 LEFT JOIN group_members gm ON g.id = gm.group_id
 WHERE g.id = $1
@@ -309,12 +422,22 @@ func (q *Queries) GetGroupByID(ctx context.Context, id string) (*GroupWithPlayer
 		&g.Region,
 		&g.Gamemode,
 		&g.Open,
+		&g.RequestToJoin,
 		&g.Passcode,
 		&g.RoleQueue,
 		&g.GroupSettings,
 		&g.Players,
 		&g.Size,
 		&g.LastActiveAt,
+		&g.MinRank,
+		&g.MaxRank,
+		&g.Scheduled,
+		&g.StartsAt,
+		&g.DurationMinutes,
+		&g.Rsvps,
+		&g.Description,
+		&g.Language,
+		&g.Tags,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -324,5 +447,6 @@ func (q *Queries) GetGroupByID(ctx context.Context, id string) (*GroupWithPlayer
 	}
 
 	g.Name = fmt.Sprintf("%s's Group", g.Owner)
+	g.SetSlots()
 	return &g, nil
 }
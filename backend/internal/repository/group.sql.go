@@ -7,10 +7,47 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addGroupMember = `-- name: AddGroupMember :exec
+INSERT INTO GroupMembers (
+    group_id,
+    player_id,
+    leader,
+    role,
+    session_id
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    NULLIF($5::TEXT, '')
+)
+`
+
+type AddGroupMemberParams struct {
+	GroupID   string `json:"group_id"`
+	PlayerID  int32  `json:"player_id"`
+	Leader    bool   `json:"leader"`
+	Role      string `json:"role"`
+	SessionID string `json:"session_id"`
+}
+
+func (q *Queries) AddGroupMember(ctx context.Context, arg AddGroupMemberParams) error {
+	_, err := q.db.Exec(ctx, addGroupMember,
+		arg.GroupID,
+		arg.PlayerID,
+		arg.Leader,
+		arg.Role,
+		arg.SessionID,
+	)
+	return err
+}
+
 const createGroup = `-- name: CreateGroup :one
 WITH 
 existing_membership AS (
@@ -75,7 +112,15 @@ new_group AS (
         strategists,
         platform,
         voice_chat,
-        mic
+        mic,
+        request_to_join,
+        community_id,
+        scheduled,
+        starts_at,
+        duration_minutes,
+        description,
+        language,
+        tags
     )
     SELECT
         $3,
@@ -87,7 +132,15 @@ new_group AS (
         $15,
         $4,
         $16,
-        $17
+        $17,
+        $18,
+        $19,
+        $20::TIMESTAMPTZ IS NOT NULL,
+        $20,
+        $21,
+        $22,
+        $23,
+        $24
     WHERE 
         NOT EXISTS (SELECT 1 FROM existing_membership) AND
         ($1 = '' OR NOT EXISTS (SELECT 1 FROM Groups WHERE id = $1))
@@ -108,12 +161,14 @@ new_membership AS (
     INSERT INTO GroupMembers (
         group_id,
         player_id,
-        leader
+        leader,
+        role
     )
     SELECT 
         fg.group_id,
         fp.player_id,
-        true
+        true,
+        LOWER($5)
     FROM final_group fg, final_player fp
     WHERE NOT EXISTS (SELECT 1 FROM existing_membership)
     RETURNING group_id::text, player_id::integer
@@ -128,23 +183,30 @@ LIMIT 1
 `
 
 type CreateGroupParams struct {
-	GroupID        interface{} `json:"group_id"`
-	PlayerID       interface{} `json:"player_id"`
-	Owner          string      `json:"owner"`
-	Platform       string      `json:"platform"`
-	Role           string      `json:"role"`
-	RankVal        int32       `json:"rank_val"`
-	Characters     []string    `json:"characters"`
-	VoiceChat      bool        `json:"voice_chat"`
-	Mic            bool        `json:"mic"`
-	Region         string      `json:"region"`
-	Gamemode       string      `json:"gamemode"`
-	Open           bool        `json:"open"`
-	Vanguards      int32       `json:"vanguards"`
-	Duelists       int32       `json:"duelists"`
-	Strategists    int32       `json:"strategists"`
-	GroupVoiceChat pgtype.Bool `json:"group_voice_chat"`
-	GroupMic       pgtype.Bool `json:"group_mic"`
+	GroupID         interface{}        `json:"group_id"`
+	PlayerID        interface{}        `json:"player_id"`
+	Owner           string             `json:"owner"`
+	Platform        string             `json:"platform"`
+	Role            string             `json:"role"`
+	RankVal         int32              `json:"rank_val"`
+	Characters      []string           `json:"characters"`
+	VoiceChat       bool               `json:"voice_chat"`
+	Mic             bool               `json:"mic"`
+	Region          string             `json:"region"`
+	Gamemode        string             `json:"gamemode"`
+	Open            bool               `json:"open"`
+	Vanguards       int32              `json:"vanguards"`
+	Duelists        int32              `json:"duelists"`
+	Strategists     int32              `json:"strategists"`
+	GroupVoiceChat  pgtype.Bool        `json:"group_voice_chat"`
+	GroupMic        pgtype.Bool        `json:"group_mic"`
+	RequestToJoin   bool               `json:"request_to_join"`
+	CommunityID     int32              `json:"community_id"`
+	StartsAt        pgtype.Timestamptz `json:"starts_at"`
+	DurationMinutes pgtype.Int4        `json:"duration_minutes"`
+	Description     string             `json:"description"`
+	Language        string             `json:"language"`
+	Tags            []string           `json:"tags"`
 }
 
 type CreateGroupRow struct {
@@ -179,8 +241,397 @@ func (q *Queries) CreateGroup(ctx context.Context, arg CreateGroupParams) (Creat
 		arg.Strategists,
 		arg.GroupVoiceChat,
 		arg.GroupMic,
+		arg.RequestToJoin,
+		arg.CommunityID,
+		arg.StartsAt,
+		arg.DurationMinutes,
+		arg.Description,
+		arg.Language,
+		arg.Tags,
 	)
 	var i CreateGroupRow
 	err := row.Scan(&i.GroupID, &i.PlayerID)
 	return i, err
 }
+
+const deleteGroup = `-- name: DeleteGroup :execrows
+DELETE FROM Groups
+WHERE id = $1
+`
+
+func (q *Queries) DeleteGroup(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteGroup, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteGroupMembers = `-- name: DeleteGroupMembers :many
+DELETE FROM GroupMembers
+WHERE group_id = $1
+RETURNING player_id
+`
+
+func (q *Queries) DeleteGroupMembers(ctx context.Context, groupID string) ([]int32, error) {
+	rows, err := q.db.Query(ctx, deleteGroupMembers, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var player_id int32
+		if err := rows.Scan(&player_id); err != nil {
+			return nil, err
+		}
+		items = append(items, player_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDueGroups = `-- name: GetDueGroups :many
+SELECT id::text
+FROM Groups
+WHERE scheduled
+AND starts_at <= NOW()
+ORDER BY starts_at
+`
+
+// Scheduled groups that are due to start
+func (q *Queries) GetDueGroups(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, getDueGroups)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGroupMemberSessions = `-- name: GetGroupMemberSessions :many
+SELECT player_id, session_id
+FROM GroupMembers
+WHERE group_id = $1
+ORDER BY player_id
+`
+
+type GetGroupMemberSessionsRow struct {
+	PlayerID  int32       `json:"player_id"`
+	SessionID pgtype.Text `json:"session_id"`
+}
+
+// The session that each member joined the group from, so that bans elsewhere can be checked against them
+func (q *Queries) GetGroupMemberSessions(ctx context.Context, groupID string) ([]GetGroupMemberSessionsRow, error) {
+	rows, err := q.db.Query(ctx, getGroupMemberSessions, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGroupMemberSessionsRow
+	for rows.Next() {
+		var i GetGroupMemberSessionsRow
+		if err := rows.Scan(&i.PlayerID, &i.SessionID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGroupPasscode = `-- name: GetGroupPasscode :one
+SELECT passcode
+FROM Groups
+WHERE id = $1
+`
+
+func (q *Queries) GetGroupPasscode(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRow(ctx, getGroupPasscode, id)
+	var passcode string
+	err := row.Scan(&passcode)
+	return passcode, err
+}
+
+const getIdleGroups = `-- name: GetIdleGroups :many
+SELECT id::text, last_active_at
+FROM Groups
+WHERE last_active_at < $1
+AND NOT scheduled
+ORDER BY last_active_at
+`
+
+type GetIdleGroupsRow struct {
+	ID           string    `json:"id"`
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+// Scheduled groups aren't idle, since nobody is expected to be active in them until they start
+func (q *Queries) GetIdleGroups(ctx context.Context, idleSince time.Time) ([]GetIdleGroupsRow, error) {
+	rows, err := q.db.Query(ctx, getIdleGroups, idleSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetIdleGroupsRow
+	for rows.Next() {
+		var i GetIdleGroupsRow
+		if err := rows.Scan(&i.ID, &i.LastActiveAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockGroup = `-- name: LockGroup :one
+SELECT id::text
+FROM Groups
+WHERE id = $1
+FOR UPDATE
+`
+
+// Locks the group until the end of the transaction, so that members can be added without racing other joins
+func (q *Queries) LockGroup(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRow(ctx, lockGroup, id)
+	var column_1 string
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const lockIdleGroup = `-- name: LockIdleGroup :one
+SELECT id::text
+FROM Groups
+WHERE id = $1
+AND last_active_at < $2
+AND NOT scheduled
+FOR UPDATE
+`
+
+type LockIdleGroupParams struct {
+	ID        string    `json:"id"`
+	IdleSince time.Time `json:"idle_since"`
+}
+
+// Locks the group until the end of the transaction, as long as it's still idle
+func (q *Queries) LockIdleGroup(ctx context.Context, arg LockIdleGroupParams) (string, error) {
+	row := q.db.QueryRow(ctx, lockIdleGroup, arg.ID, arg.IdleSince)
+	var column_1 string
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const moveGroupMembers = `-- name: MoveGroupMembers :many
+UPDATE GroupMembers
+SET
+    group_id = $1,
+    leader = false
+WHERE group_id = $2
+RETURNING player_id, session_id
+`
+
+type MoveGroupMembersParams struct {
+	TargetID string `json:"target_id"`
+	SourceID string `json:"source_id"`
+}
+
+type MoveGroupMembersRow struct {
+	PlayerID  int32       `json:"player_id"`
+	SessionID pgtype.Text `json:"session_id"`
+}
+
+// Moves every member of the source group into the target group, where none of them lead
+func (q *Queries) MoveGroupMembers(ctx context.Context, arg MoveGroupMembersParams) ([]MoveGroupMembersRow, error) {
+	rows, err := q.db.Query(ctx, moveGroupMembers, arg.TargetID, arg.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MoveGroupMembersRow
+	for rows.Next() {
+		var i MoveGroupMembersRow
+		if err := rows.Scan(&i.PlayerID, &i.SessionID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateGroupPasscode = `-- name: RotateGroupPasscode :one
+UPDATE Groups
+SET
+    passcode = generate_passcode(),
+    updated_at = NOW(),
+    last_active_at = NOW()
+WHERE id = $1
+RETURNING passcode
+`
+
+func (q *Queries) RotateGroupPasscode(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRow(ctx, rotateGroupPasscode, id)
+	var passcode string
+	err := row.Scan(&passcode)
+	return passcode, err
+}
+
+const setGroupLeader = `-- name: SetGroupLeader :exec
+UPDATE GroupMembers
+SET leader = (player_id = $1)
+WHERE group_id = $2
+`
+
+type SetGroupLeaderParams struct {
+	PlayerID int32  `json:"player_id"`
+	GroupID  string `json:"group_id"`
+}
+
+// Makes the player the group's only leader
+func (q *Queries) SetGroupLeader(ctx context.Context, arg SetGroupLeaderParams) error {
+	_, err := q.db.Exec(ctx, setGroupLeader, arg.PlayerID, arg.GroupID)
+	return err
+}
+
+const setGroupOwner = `-- name: SetGroupOwner :exec
+UPDATE Groups
+SET
+    owner = $1,
+    updated_at = NOW(),
+    last_active_at = NOW()
+WHERE id = $2
+`
+
+type SetGroupOwnerParams struct {
+	Owner pgtype.Text `json:"owner"`
+	ID    string      `json:"id"`
+}
+
+func (q *Queries) SetGroupOwner(ctx context.Context, arg SetGroupOwnerParams) error {
+	_, err := q.db.Exec(ctx, setGroupOwner, arg.Owner, arg.ID)
+	return err
+}
+
+const setMemberRole = `-- name: SetMemberRole :exec
+UPDATE GroupMembers
+SET role = $1
+WHERE group_id = $2
+AND player_id = $3
+`
+
+type SetMemberRoleParams struct {
+	Role     string `json:"role"`
+	GroupID  string `json:"group_id"`
+	PlayerID int32  `json:"player_id"`
+}
+
+// Moves the member to another role in the group
+func (q *Queries) SetMemberRole(ctx context.Context, arg SetMemberRoleParams) error {
+	_, err := q.db.Exec(ctx, setMemberRole, arg.Role, arg.GroupID, arg.PlayerID)
+	return err
+}
+
+const startGroup = `-- name: StartGroup :exec
+UPDATE Groups
+SET
+    scheduled = false,
+    updated_at = NOW(),
+    last_active_at = NOW()
+WHERE id = $1
+`
+
+// Makes the scheduled group live, as if it had just been active
+func (q *Queries) StartGroup(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, startGroup, id)
+	return err
+}
+
+const touchGroup = `-- name: TouchGroup :exec
+UPDATE Groups
+SET last_active_at = NOW()
+WHERE id = $1
+`
+
+// Marks the group as active, e.g. when members join, leave or chat
+func (q *Queries) TouchGroup(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, touchGroup, id)
+	return err
+}
+
+const updateGroup = `-- name: UpdateGroup :exec
+UPDATE Groups
+SET
+    region = $1,
+    gamemode = $2,
+    open = $3,
+    vanguards = $4,
+    duelists = $5,
+    strategists = $6,
+    platform = $7,
+    voice_chat = $8,
+    mic = $9,
+    request_to_join = $10,
+    description = $11,
+    language = $12,
+    tags = $13,
+    updated_at = NOW(),
+    last_active_at = NOW()
+WHERE id = $14
+`
+
+type UpdateGroupParams struct {
+	Region        string      `json:"region"`
+	Gamemode      string      `json:"gamemode"`
+	Open          bool        `json:"open"`
+	Vanguards     int32       `json:"vanguards"`
+	Duelists      int32       `json:"duelists"`
+	Strategists   int32       `json:"strategists"`
+	Platform      string      `json:"platform"`
+	VoiceChat     pgtype.Bool `json:"voice_chat"`
+	Mic           pgtype.Bool `json:"mic"`
+	RequestToJoin bool        `json:"request_to_join"`
+	Description   string      `json:"description"`
+	Language      string      `json:"language"`
+	Tags          []string    `json:"tags"`
+	ID            string      `json:"id"`
+}
+
+func (q *Queries) UpdateGroup(ctx context.Context, arg UpdateGroupParams) error {
+	_, err := q.db.Exec(ctx, updateGroup,
+		arg.Region,
+		arg.Gamemode,
+		arg.Open,
+		arg.Vanguards,
+		arg.Duelists,
+		arg.Strategists,
+		arg.Platform,
+		arg.VoiceChat,
+		arg.Mic,
+		arg.RequestToJoin,
+		arg.Description,
+		arg.Language,
+		arg.Tags,
+		arg.ID,
+	)
+	return err
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "time"
+import (
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/types"
+)
 
 type GroupDTO struct {
 	ID            string         `json:"id"`
@@ -10,22 +14,64 @@ type GroupDTO struct {
 	Region        string         `json:"region"`
 	Gamemode      string         `json:"gamemode"`
 	Open          bool           `json:"open"`
+	RequestToJoin bool           `json:"requestToJoin"`
 	Passcode      string         `json:"passcode"`
 	RoleQueue     *RoleQueue     `json:"roleQueue"`
 	GroupSettings *GroupSettings `json:"groupSettings"`
 	LastActiveAt  time.Time      `json:"lastActiveAt"`
+
+	// Scheduled groups go live at StartsAt, and are played for DurationMinutes
+	Scheduled       bool       `json:"scheduled"`
+	StartsAt        *time.Time `json:"startsAt,omitempty"`
+	DurationMinutes *int32     `json:"durationMinutes,omitempty"`
+
+	Description string   `json:"description"`
+	Language    string   `json:"language,omitempty"`
+	Tags        []string `json:"tags"`
+
+	// The ranks that the group's community allows, if it limits them
+	MinRank *int32 `json:"-"`
+	MaxRank *int32 `json:"-"`
+}
+
+// AllowsRank is whether the group's community lets players of the rank in.
+func (g *GroupDTO) AllowsRank(rankVal int) bool {
+	return (g.MinRank == nil || rankVal >= int(*g.MinRank)) && (g.MaxRank == nil || rankVal <= int(*g.MaxRank))
 }
 
 type GroupWithPlayers struct {
 	GroupDTO
 
 	// Computed fields
-	Name string `json:"name"`
-	Size int    `json:"size"`
+	Name           string `json:"name"`
+	Size           int    `json:"size"`
+	SlotsRemaining int    `json:"slotsRemaining"`
+	Full           bool   `json:"full"`
+	// Rsvps is how many players have RSVP'd to the group, which hold slots until it starts
+	Rsvps int `json:"rsvps"`
 
 	Players []PlayerInGroup `json:"players"`
 }
 
+// Capacity returns how many players fit into the group: its role queue, or
+// the gamemode's team size if it has none, whichever is smaller.
+func (g *GroupWithPlayers) Capacity() int {
+	capacity := types.TeamSize(g.Gamemode)
+	if rq := g.RoleQueue; rq != nil {
+		if total := rq.Vanguards + rq.Duelists + rq.Strategists; total > 0 && total < capacity {
+			capacity = total
+		}
+	}
+	return capacity
+}
+
+// SetSlots computes how many slots are left in the group, which needs to be
+// redone whenever its members, RSVPs or settings change.
+func (g *GroupWithPlayers) SetSlots() {
+	g.SlotsRemaining = max(g.Capacity()-g.Size-g.Rsvps, 0)
+	g.Full = g.SlotsRemaining == 0
+}
+
 type RoleQueue struct {
 	Vanguards   int `json:"vanguards"`
 	Duelists    int `json:"duelists"`
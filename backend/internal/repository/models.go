@@ -11,35 +11,139 @@ import (
 )
 
 type Community struct {
-	ID          int32  `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Link        string `json:"link"`
+	ID          int32       `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Link        string      `json:"link"`
+	MinRank     pgtype.Int4 `json:"min_rank"`
+	MaxRank     pgtype.Int4 `json:"max_rank"`
+	Regions     []string    `json:"regions"`
+	InviteOnly  bool        `json:"invite_only"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type Communitymoderator struct {
+	CommunityID int32     `json:"community_id"`
+	PlayerID    int32     `json:"player_id"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type Group struct {
-	ID           string      `json:"id"`
-	CommunityID  int32       `json:"community_id"`
-	Owner        pgtype.Text `json:"owner"`
-	Region       string      `json:"region"`
-	Gamemode     string      `json:"gamemode"`
-	Open         bool        `json:"open"`
-	Passcode     string      `json:"passcode"`
-	Vanguards    int32       `json:"vanguards"`
-	Duelists     int32       `json:"duelists"`
-	Strategists  int32       `json:"strategists"`
-	Platform     string      `json:"platform"`
-	VoiceChat    pgtype.Bool `json:"voice_chat"`
-	Mic          pgtype.Bool `json:"mic"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	LastActiveAt time.Time   `json:"last_active_at"`
+	ID              string             `json:"id"`
+	CommunityID     int32              `json:"community_id"`
+	Owner           pgtype.Text        `json:"owner"`
+	Region          string             `json:"region"`
+	Gamemode        string             `json:"gamemode"`
+	Open            bool               `json:"open"`
+	Passcode        string             `json:"passcode"`
+	Vanguards       int32              `json:"vanguards"`
+	Duelists        int32              `json:"duelists"`
+	Strategists     int32              `json:"strategists"`
+	Platform        string             `json:"platform"`
+	VoiceChat       pgtype.Bool        `json:"voice_chat"`
+	Mic             pgtype.Bool        `json:"mic"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	LastActiveAt    time.Time          `json:"last_active_at"`
+	RequestToJoin   bool               `json:"request_to_join"`
+	Scheduled       bool               `json:"scheduled"`
+	StartsAt        pgtype.Timestamptz `json:"starts_at"`
+	DurationMinutes pgtype.Int4        `json:"duration_minutes"`
+	Description     string             `json:"description"`
+	Language        string             `json:"language"`
+	Tags            []string           `json:"tags"`
+}
+
+type Groupban struct {
+	GroupID   string      `json:"group_id"`
+	PlayerID  int32       `json:"player_id"`
+	SessionID pgtype.Text `json:"session_id"`
+	BannedBy  int32       `json:"banned_by"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type Groupinvite struct {
+	ID        int32              `json:"id"`
+	GroupID   string             `json:"group_id"`
+	Token     string             `json:"token"`
+	CreatedBy int32              `json:"created_by"`
+	MaxUses   int32              `json:"max_uses"`
+	Uses      int32              `json:"uses"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type Groupinviteredemption struct {
+	InviteID   int32     `json:"invite_id"`
+	PlayerID   int32     `json:"player_id"`
+	RedeemedAt time.Time `json:"redeemed_at"`
 }
 
 type Groupmember struct {
-	GroupID  string `json:"group_id"`
-	PlayerID int32  `json:"player_id"`
-	Leader   bool   `json:"leader"`
+	GroupID   string      `json:"group_id"`
+	PlayerID  int32       `json:"player_id"`
+	Leader    bool        `json:"leader"`
+	Role      string      `json:"role"`
+	SessionID pgtype.Text `json:"session_id"`
+}
+
+type Grouprsvp struct {
+	ID        int32       `json:"id"`
+	GroupID   string      `json:"group_id"`
+	PlayerID  int32       `json:"player_id"`
+	Role      string      `json:"role"`
+	SessionID pgtype.Text `json:"session_id"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type Groupwaitlist struct {
+	ID             int32              `json:"id"`
+	GroupID        string             `json:"group_id"`
+	PlayerID       int32              `json:"player_id"`
+	Role           pgtype.Text        `json:"role"`
+	Roles          []string           `json:"roles"`
+	SessionID      pgtype.Text        `json:"session_id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	OfferedRole    pgtype.Text        `json:"offered_role"`
+	OfferExpiresAt pgtype.Timestamptz `json:"offer_expires_at"`
+}
+
+type Joinrequest struct {
+	ID         int32              `json:"id"`
+	GroupID    string             `json:"group_id"`
+	PlayerID   int32              `json:"player_id"`
+	Roles      []string           `json:"roles"`
+	SessionID  pgtype.Text        `json:"session_id"`
+	Status     string             `json:"status"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type Mergeproposal struct {
+	ID         int32              `json:"id"`
+	SourceID   string             `json:"source_id"`
+	TargetID   string             `json:"target_id"`
+	ProposedBy int32              `json:"proposed_by"`
+	Status     string             `json:"status"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type Party struct {
+	ID        string    `json:"id"`
+	LeaderID  int32     `json:"leader_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Partymember struct {
+	PartyID   string      `json:"party_id"`
+	PlayerID  int32       `json:"player_id"`
+	Roles     []string    `json:"roles"`
+	JoinedAt  time.Time   `json:"joined_at"`
+	SessionID pgtype.Text `json:"session_id"`
 }
 
 type Player struct {
@@ -9,6 +9,20 @@ import (
 	"context"
 )
 
+const getPlayerGroup = `-- name: GetPlayerGroup :one
+SELECT group_id::text
+FROM GroupMembers
+WHERE player_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetPlayerGroup(ctx context.Context, playerID int32) (string, error) {
+	row := q.db.QueryRow(ctx, getPlayerGroup, playerID)
+	var group_id string
+	err := row.Scan(&group_id)
+	return group_id, err
+}
+
 const joinGroup = `-- name: JoinGroup :one
 WITH 
 player_check AS (
@@ -17,6 +31,13 @@ player_check AS (
     LIMIT 1
 ),
 
+ban_check AS (
+    SELECT 1 FROM GroupBans b
+    WHERE b.group_id = $1
+    AND (b.player_id = $3 OR b.session_id = NULLIF($19::TEXT, ''))
+    LIMIT 1
+),
+
 group_members_base AS (
     SELECT 
         gm.group_id,
@@ -24,7 +45,7 @@ group_members_base AS (
         p.name,
         gm.leader,
         p.platform,
-        LOWER(p.role) as role,
+        gm.role,
         p.rank as rank_val,
         p.characters,
         p.voice_chat,
@@ -36,15 +57,70 @@ group_members_base AS (
 group_details AS (
     SELECT 
         group_id,
+        COUNT(*) as member_count,
         COUNT(CASE WHEN role = 'vanguard' THEN 1 END) as curr_vanguards,
         COUNT(CASE WHEN role = 'duelist' THEN 1 END) as curr_duelists,
         COUNT(CASE WHEN role = 'strategist' THEN 1 END) as curr_strategists,
-        MIN(rank_val) as min_rank,
-        MAX(rank_val) as max_rank
+        array_agg(rank_val) as ranks
     FROM group_members_base
     GROUP BY group_id
 ),
 
+held_offers AS (
+    SELECT
+        COUNT(*) as held_count,
+        COUNT(CASE WHEN w.offered_role = 'vanguard' THEN 1 END) as held_vanguards,
+        COUNT(CASE WHEN w.offered_role = 'duelist' THEN 1 END) as held_duelists,
+        COUNT(CASE WHEN w.offered_role = 'strategist' THEN 1 END) as held_strategists
+    FROM GroupWaitlist w
+    WHERE w.group_id = $1
+    AND w.offer_expires_at > NOW()
+    AND w.player_id <> $3
+),
+
+seat AS (
+    SELECT pref.role
+    FROM Groups g
+    JOIN group_details gd ON gd.group_id = g.id
+    CROSS JOIN held_offers ho
+    CROSS JOIN unnest($17::text[]) WITH ORDINALITY AS pref(role, ord)
+    WHERE g.id = $1
+    AND (
+        (g.vanguards + g.duelists + g.strategists = 0)
+        OR (pref.role = 'vanguard' AND gd.curr_vanguards + ho.held_vanguards < g.vanguards)
+        OR (pref.role = 'duelist' AND gd.curr_duelists + ho.held_duelists < g.duelists)
+        OR (pref.role = 'strategist' AND gd.curr_strategists + ho.held_strategists < g.strategists)
+    )
+    ORDER BY pref.ord
+    LIMIT 1
+),
+
+full_check AS (
+    SELECT 1
+    FROM Groups g
+    JOIN group_details gd ON gd.group_id = g.id
+    CROSS JOIN held_offers ho
+    WHERE g.id = $1
+    AND gd.member_count + ho.held_count >= LEAST(
+        $20::integer,
+        CASE
+            WHEN g.vanguards + g.duelists + g.strategists = 0 THEN $20::integer
+            ELSE g.vanguards + g.duelists + g.strategists
+        END
+    )
+),
+
+valid_invite AS (
+    SELECT i.id
+    FROM GroupInvites i
+    WHERE i.token = $18
+    AND i.group_id = $1
+    AND i.revoked_at IS NULL
+    AND i.expires_at > NOW()
+    AND i.uses < i.max_uses
+    FOR UPDATE
+),
+
 valid_group AS (
     SELECT g.id
     FROM Groups g, group_details gd
@@ -53,30 +129,29 @@ valid_group AS (
     AND g.region = $5
     -- Platform check
     AND g.platform = $6
-    -- Role queue check (only if enabled)
-    AND (
-        (g.vanguards + g.duelists + g.strategists = 0)
-        OR
-        (
-            -- Can fill at least one role
-            ($7 = 'vanguard' AND gd.curr_vanguards < g.vanguards)
-            OR ($7 = 'duelist' AND gd.curr_duelists < g.duelists)
-            OR ($7 = 'strategist' AND gd.curr_strategists < g.strategists)
-        )
+    -- Role queue check, the player must fit into one of their preferred roles
+    AND EXISTS (SELECT 1 FROM seat)
+    -- Rank check, every member must be within the ranks allowed by the gamemode's rank policy
+    AND gd.ranks <@ $16::integer[]
+    -- Community rank check, the player must be within the ranks that the group's community allows
+    AND NOT EXISTS (
+        SELECT 1 FROM Community c
+        WHERE c.id = g.community_id
+        AND ($8::integer < c.min_rank OR $8::integer > c.max_rank)
     )
-    -- Rank check
-    AND (
-        -- Allow Bronze-Gold players to group with each other
-        $8 BETWEEN 0 AND 22 AND gd.min_rank BETWEEN 0 AND 22
-        OR (
-            ABS(gd.min_rank - $8) <= 10
-            AND ABS(gd.max_rank - $8) <= 10
-        )
-    )
-    -- If group is not open, check if passcode is correct
+    -- Scheduled groups are RSVP'd to until they start
+    AND NOT g.scheduled
+    -- Banned players are turned away
+    AND NOT EXISTS (SELECT 1 FROM ban_check)
+    AND NOT EXISTS (SELECT 1 FROM full_check)
+    -- Voice chat and mic
+    AND (NOT g.voice_chat OR $11)
+    AND (NOT g.mic OR $12)
+    -- If group is not open, check if passcode or invite is correct
     AND (
         g.open 
         OR (NOT g.open AND g.passcode = $2)
+        OR EXISTS (SELECT 1 FROM valid_invite)
     )
     LIMIT 1
 ),
@@ -116,7 +191,9 @@ group_member_creation AS (
     INSERT INTO GroupMembers (
         group_id,
         player_id,
-        leader
+        leader,
+        role,
+        session_id
     )
     SELECT 
         $1,
@@ -124,23 +201,45 @@ group_member_creation AS (
             (SELECT id FROM player_creation),
             $3
         ),
-        false
+        false,
+        (SELECT role FROM seat),
+        NULLIF($19::TEXT, '')
     WHERE EXISTS (SELECT 1 FROM valid_group)
     AND NOT EXISTS (SELECT 1 FROM player_check)
     RETURNING player_id
+),
+
+invite_redemption AS (
+    UPDATE GroupInvites
+    SET uses = uses + 1
+    WHERE id = (SELECT id FROM valid_invite)
+    AND EXISTS (SELECT 1 FROM group_member_creation)
+    RETURNING id
+),
+
+redemption_creation AS (
+    INSERT INTO GroupInviteRedemptions (invite_id, player_id)
+    SELECT ir.id, gmc.player_id
+    FROM invite_redemption ir, group_member_creation gmc
+    ON CONFLICT (invite_id, player_id) DO UPDATE SET redeemed_at = NOW()
+    RETURNING invite_id
 )
 
 SELECT 
     CASE
         WHEN EXISTS (SELECT 1 FROM player_check) THEN '400a'
         WHEN NOT EXISTS (SELECT 1 FROM Groups g WHERE g.id = $1) THEN '404'
+        WHEN EXISTS (SELECT 1 FROM ban_check) THEN '403b'
         WHEN EXISTS (SELECT 1 FROM group_member_creation) THEN '200'
+        WHEN EXISTS (SELECT 1 FROM Groups g WHERE g.id = $1 AND g.scheduled) THEN '400s'
         WHEN EXISTS (
             SELECT 1 FROM Groups g 
             WHERE g.id = $1
             AND NOT g.open 
             AND g.passcode != $2
+            AND NOT EXISTS (SELECT 1 FROM valid_invite)
         ) THEN '403'
+        WHEN EXISTS (SELECT 1 FROM full_check) THEN '400f'
         WHEN NOT EXISTS (SELECT 1 FROM valid_group) THEN '400e'
         ELSE '500'
     END as status,
@@ -151,21 +250,26 @@ SELECT
 `
 
 type JoinGroupParams struct {
-	GroupID     string      `json:"group_id"`
-	Passcode    string      `json:"passcode"`
-	PlayerID    int32       `json:"player_id"`
-	Gamemode    string      `json:"gamemode"`
-	Region      string      `json:"region"`
-	Platform    string      `json:"platform"`
-	Role        interface{} `json:"role"`
-	RankVal     interface{} `json:"rank_val"`
-	Name        string      `json:"name"`
-	Characters  []string    `json:"characters"`
-	VoiceChat   bool        `json:"voice_chat"`
-	Mic         bool        `json:"mic"`
-	Vanguards   int32       `json:"vanguards"`
-	Duelists    int32       `json:"duelists"`
-	Strategists int32       `json:"strategists"`
+	GroupID      string      `json:"group_id"`
+	Passcode     string      `json:"passcode"`
+	PlayerID     int32       `json:"player_id"`
+	Gamemode     string      `json:"gamemode"`
+	Region       string      `json:"region"`
+	Platform     string      `json:"platform"`
+	Role         interface{} `json:"role"`
+	RankVal      interface{} `json:"rank_val"`
+	Name         string      `json:"name"`
+	Characters   []string    `json:"characters"`
+	VoiceChat    bool        `json:"voice_chat"`
+	Mic          bool        `json:"mic"`
+	Vanguards    int32       `json:"vanguards"`
+	Duelists     int32       `json:"duelists"`
+	Strategists  int32       `json:"strategists"`
+	AllowedRanks []int32     `json:"allowed_ranks"`
+	Roles        []string    `json:"roles"`
+	Invite       string      `json:"invite"`
+	SessionID    string      `json:"session_id"`
+	TeamSize     int32       `json:"team_size"`
 }
 
 type JoinGroupRow struct {
@@ -174,9 +278,14 @@ type JoinGroupRow struct {
 }
 
 // First check if player is already in a group
+// Banned players can't rejoin, even as a new player from the same session
+// Pick the first of the player's preferred roles that has an open slot
+// Groups can't grow past their role queue, or the gamemode's team size without one
+// An invite lets the player in without the passcode, as long as it has uses left
 // Check all requirements in a single query
 // Insert player if they don't exist and group is valid
 // Create group membership if everything valid
+// Use up the invite that the player joined with, and remember who redeemed it
 // Return status code
 func (q *Queries) JoinGroup(ctx context.Context, arg JoinGroupParams) (JoinGroupRow, error) {
 	row := q.db.QueryRow(ctx, joinGroup,
@@ -195,6 +304,11 @@ func (q *Queries) JoinGroup(ctx context.Context, arg JoinGroupParams) (JoinGroup
 		arg.Vanguards,
 		arg.Duelists,
 		arg.Strategists,
+		arg.AllowedRanks,
+		arg.Roles,
+		arg.Invite,
+		arg.SessionID,
+		arg.TeamSize,
 	)
 	var i JoinGroupRow
 	err := row.Scan(&i.Status, &i.PlayerID)
@@ -266,6 +380,19 @@ remove_member AS (
     AND EXISTS (SELECT 1 FROM group_check)
     RETURNING group_id
 ),
+ban_creation AS (
+    -- Ban the player from rejoining if asked, unless the group is going away
+    INSERT INTO GroupBans (group_id, player_id, session_id, banned_by)
+    SELECT gc.group_id, gc.player_id, gc.session_id, $4::INTEGER
+    FROM group_check gc
+    WHERE $3::BOOLEAN
+    AND NOT EXISTS (SELECT 1 FROM is_last_member WHERE is_last)
+    ON CONFLICT (group_id, player_id) DO UPDATE SET
+        session_id = EXCLUDED.session_id,
+        banned_by = EXCLUDED.banned_by,
+        created_at = NOW()
+    RETURNING player_id
+),
 delete_empty_group AS (
     -- Delete group if this was the last member
     DELETE FROM Groups g
@@ -291,6 +418,8 @@ SELECT
 type RemovePlayerParams struct {
 	GroupID  string `json:"group_id"`
 	PlayerID int32  `json:"player_id"`
+	Ban      bool   `json:"ban"`
+	BannedBy int32  `json:"banned_by"`
 }
 
 type RemovePlayerRow struct {
@@ -299,8 +428,104 @@ type RemovePlayerRow struct {
 }
 
 func (q *Queries) RemovePlayer(ctx context.Context, arg RemovePlayerParams) (RemovePlayerRow, error) {
-	row := q.db.QueryRow(ctx, removePlayer, arg.GroupID, arg.PlayerID)
+	row := q.db.QueryRow(ctx, removePlayer,
+		arg.GroupID,
+		arg.PlayerID,
+		arg.Ban,
+		arg.BannedBy,
+	)
 	var i RemovePlayerRow
 	err := row.Scan(&i.Status, &i.NewLeaderID)
 	return i, err
 }
+
+const setPlayerCharacters = `-- name: SetPlayerCharacters :exec
+UPDATE Players
+SET characters = $1
+WHERE id = $2
+`
+
+type SetPlayerCharactersParams struct {
+	Characters []string `json:"characters"`
+	ID         int32    `json:"id"`
+}
+
+func (q *Queries) SetPlayerCharacters(ctx context.Context, arg SetPlayerCharactersParams) error {
+	_, err := q.db.Exec(ctx, setPlayerCharacters, arg.Characters, arg.ID)
+	return err
+}
+
+const upsertPlayer = `-- name: UpsertPlayer :one
+INSERT INTO Players (
+    id,
+    name,
+    platform,
+    role,
+    rank,
+    characters,
+    voice_chat,
+    mic,
+    vanguards,
+    duelists,
+    strategists
+)
+VALUES (
+    COALESCE(NULLIF($1::integer, 0), nextval(pg_get_serial_sequence('players', 'id'))),
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11
+)
+ON CONFLICT (id) DO UPDATE SET
+    name = EXCLUDED.name,
+    platform = EXCLUDED.platform,
+    role = EXCLUDED.role,
+    rank = EXCLUDED.rank,
+    characters = EXCLUDED.characters,
+    voice_chat = EXCLUDED.voice_chat,
+    mic = EXCLUDED.mic,
+    vanguards = EXCLUDED.vanguards,
+    duelists = EXCLUDED.duelists,
+    strategists = EXCLUDED.strategists
+RETURNING id
+`
+
+type UpsertPlayerParams struct {
+	ID          int32    `json:"id"`
+	Name        string   `json:"name"`
+	Platform    string   `json:"platform"`
+	Role        string   `json:"role"`
+	Rank        int32    `json:"rank"`
+	Characters  []string `json:"characters"`
+	VoiceChat   bool     `json:"voice_chat"`
+	Mic         bool     `json:"mic"`
+	Vanguards   int32    `json:"vanguards"`
+	Duelists    int32    `json:"duelists"`
+	Strategists int32    `json:"strategists"`
+}
+
+// Creates the player if they don't exist yet, otherwise refreshes their profile
+func (q *Queries) UpsertPlayer(ctx context.Context, arg UpsertPlayerParams) (int32, error) {
+	row := q.db.QueryRow(ctx, upsertPlayer,
+		arg.ID,
+		arg.Name,
+		arg.Platform,
+		arg.Role,
+		arg.Rank,
+		arg.Characters,
+		arg.VoiceChat,
+		arg.Mic,
+		arg.Vanguards,
+		arg.Duelists,
+		arg.Strategists,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
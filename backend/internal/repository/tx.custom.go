@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// ExecTx runs fn in a transaction, which is committed if fn succeeds and rolled back otherwise.
+func (q *Queries) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+	db, ok := q.db.(txBeginner)
+	if !ok {
+		return fmt.Errorf("unable to begin transaction")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			log.Error(ctx, fmt.Sprintf("unable to roll back transaction: %v", rbErr))
+		}
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jcserv/rivalslfg/internal/repository"
 	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/store"
 	_http "github.com/jcserv/rivalslfg/internal/transport/http"
 	v1 "github.com/jcserv/rivalslfg/internal/transport/http/v1"
 	"github.com/jcserv/rivalslfg/internal/transport/ws"
@@ -20,8 +21,13 @@ import (
 )
 
 type Service struct {
-	api *_http.API
-	cfg *Configuration
+	api       *_http.API
+	cfg       *Configuration
+	matcher   *services.Matcher
+	reaper    *services.Reaper
+	sweeper   *services.WaitlistSweeper
+	scheduler *services.GroupScheduler
+	ws        *ws.Server
 }
 
 func NewService() (*Service, error) {
@@ -43,18 +49,39 @@ func NewService() (*Service, error) {
 		return nil, err
 	}
 
-	// client, err := s.ConnectCache(context.Background())
-	// if err != nil {
-	// 	return nil, err
-	// }
+	client, err := s.ConnectCache(context.Background())
+	if err != nil {
+		return nil, err
+	}
 
 	repo := repository.New(conn)
-	// store := store.New(client)
+	store := store.New(client)
+
+	s.ws = ws.NewServer([]string{os.Getenv("ORIGIN_ALLOWED")})
+
+	groupService := services.NewGroup(repo)
+	groupService.SetNotifier(s.ws.GroupNotifier())
+	groupService.SetStaleAfter(cfg.GroupStaleAfter)
+	s.ws.SetActivityRecorder(groupService)
+	playerService := services.NewPlayer(repo)
+	playerService.SetGroups(groupService)
+	partyService := services.NewParty(repo)
+	communityService := services.NewCommunity(repo)
+	communityService.SetAdmins(cfg.CommunityAdmins)
+	s.matcher = services.NewMatcher(groupService, playerService, partyService, store)
+	s.matcher.SetNotifier(s.ws.QueueNotifier())
+	s.reaper = services.NewReaper(groupService, cfg.GroupIdleTTL, cfg.GroupIdleWarning)
+	s.reaper.SetNotifier(s.ws.GroupNotifier())
+	s.sweeper = services.NewWaitlistSweeper(groupService)
+	s.scheduler = services.NewGroupScheduler(groupService)
 
 	s.api = _http.NewAPI(
 		&v1.Dependencies{
-			GroupService:  services.NewGroup(repo),
-			PlayerService: services.NewPlayer(repo),
+			GroupService:     groupService,
+			PlayerService:    playerService,
+			PartyService:     partyService,
+			CommunityService: communityService,
+			Matcher:          s.matcher,
 		},
 	)
 	return s, nil
@@ -71,6 +98,25 @@ func (s *Service) Run() error {
 		s.StartHTTP(ctx)
 	}(ctx)
 	wg.Add(1)
+	go func(ctx context.Context) {
+		defer wg.Done()
+		s.matcher.Run(ctx)
+	}(ctx)
+	wg.Add(1)
+	go func(ctx context.Context) {
+		defer wg.Done()
+		s.reaper.Run(ctx)
+	}(ctx)
+	wg.Add(1)
+	go func(ctx context.Context) {
+		defer wg.Done()
+		s.sweeper.Run(ctx)
+	}(ctx)
+	wg.Add(1)
+	go func(ctx context.Context) {
+		defer wg.Done()
+		s.scheduler.Run(ctx)
+	}(ctx)
 
 	wg.Wait()
 	return nil
@@ -122,17 +168,16 @@ func (s *Service) ConnectCache(ctx context.Context) (*redis.Client, error) {
 func (s *Service) StartHTTP(ctx context.Context) error {
 	log.Info(ctx, fmt.Sprintf("Starting HTTP server on port %s", s.cfg.HTTPPort))
 
-	wsServer := ws.NewServer([]string{os.Getenv("ORIGIN_ALLOWED")})
-	wsServer.Start(ctx)
+	s.ws.Start(ctx)
 
 	mainMux := http.NewServeMux()
 	r := s.api.RegisterRoutes()
 	mainMux.Handle("/", r)
-	wsServer.RegisterHandlers(mainMux)
+	s.ws.RegisterHandlers(mainMux)
 
 	headers := handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization"})
 	origins := handlers.AllowedOrigins([]string{os.Getenv("ORIGIN_ALLOWED")})
-	methods := handlers.AllowedMethods([]string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions})
+	methods := handlers.AllowedMethods([]string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions})
 
 	exposedHeaders := handlers.ExposedHeaders([]string{"X-Requested-With", "Content-Type", "X-Total-Count", "X-Token"})
 
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+)
+
+// BanDTO is a player that was removed from the group and can't rejoin it.
+type BanDTO struct {
+	PlayerID  int32     `json:"playerId"`
+	Name      string    `json:"name"`
+	BannedBy  int32     `json:"bannedBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GetBans returns the players that are banned from the group, most recent first. Only the owner can see them.
+func (s *Group) GetBans(ctx context.Context, groupID string, ownerID int32) ([]BanDTO, error) {
+	var rows []repository.GetGroupBansRow
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := lockOwnedGroup(ctx, q, groupID, ownerID); err != nil {
+			return err
+		}
+
+		var err error
+		rows, err = q.GetGroupBans(ctx, groupID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bans := make([]BanDTO, 0, len(rows))
+	for _, row := range rows {
+		bans = append(bans, BanDTO{
+			PlayerID:  row.PlayerID,
+			Name:      row.Name,
+			BannedBy:  row.BannedBy,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return bans, nil
+}
+
+// LiftBan lets the player join the group again. Only the owner can lift bans.
+func (s *Group) LiftBan(ctx context.Context, groupID string, ownerID, playerID int32) error {
+	return s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := lockOwnedGroup(ctx, q, groupID, ownerID); err != nil {
+			return err
+		}
+
+		lifted, err := q.DeleteGroupBan(ctx, repository.DeleteGroupBanParams{
+			GroupID:  groupID,
+			PlayerID: playerID,
+		})
+		if err != nil {
+			return err
+		}
+		if lifted == 0 {
+			return NewError(http.StatusNotFound, "Ban not found.", nil)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,430 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// DefaultCommunityID is the community that groups are created in, unless they're given another one.
+const DefaultCommunityID = 1
+
+// CommunityRules limit the groups that can be created in a community, and the
+// players that can join them. Ranks are rank IDs, and are unbounded if left
+// out. Groups can be in any region if none are listed.
+type CommunityRules struct {
+	MinRank    string   `json:"minRank,omitempty"`
+	MaxRank    string   `json:"maxRank,omitempty"`
+	Regions    []string `json:"regions"`
+	InviteOnly bool     `json:"inviteOnly"`
+}
+
+// Validate checks that the rules only use known ranks and regions, and that the rank range isn't empty.
+func (r CommunityRules) Validate() error {
+	for _, rank := range []string{r.MinRank, r.MaxRank} {
+		if rank != "" && !types.IsValidRankID(rank) {
+			return fmt.Errorf("rank %s is invalid", rank)
+		}
+	}
+	if r.MinRank != "" && r.MaxRank != "" && types.RankIDToRankVal[r.MinRank] > types.RankIDToRankVal[r.MaxRank] {
+		return fmt.Errorf("minRank must not be above maxRank")
+	}
+	for _, region := range r.Regions {
+		if err := types.ValidateRegion(region); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateGroup checks that a group in the community follows the rules.
+func (r CommunityRules) ValidateGroup(region string, open bool) error {
+	if len(r.Regions) > 0 && !slices.Contains(r.Regions, region) {
+		return NewError(http.StatusBadRequest, fmt.Sprintf("Groups in this community must be in one of: %s.", strings.Join(r.Regions, ", ")), nil)
+	}
+	if r.InviteOnly && open {
+		return NewError(http.StatusBadRequest, "Groups in this community must be private.", nil)
+	}
+	return nil
+}
+
+// AllowsRank returns whether players of the rank can be in the community's groups.
+func (r CommunityRules) AllowsRank(rankVal int) bool {
+	if r.MinRank != "" && rankVal < types.RankIDToRankVal[r.MinRank] {
+		return false
+	}
+	if r.MaxRank != "" && rankVal > types.RankIDToRankVal[r.MaxRank] {
+		return false
+	}
+	return true
+}
+
+func toCommunityRules(row repository.Community) CommunityRules {
+	rules := CommunityRules{
+		Regions:    row.Regions,
+		InviteOnly: row.InviteOnly,
+	}
+	if rules.Regions == nil {
+		rules.Regions = []string{}
+	}
+	if row.MinRank.Valid {
+		rules.MinRank = types.RankValToRankID[int(row.MinRank.Int32)]
+	}
+	if row.MaxRank.Valid {
+		rules.MaxRank = types.RankValToRankID[int(row.MaxRank.Int32)]
+	}
+	return rules
+}
+
+// rankVal is the rank's value, or NULL if it's unbounded.
+func rankVal(rankID string) pgtype.Int4 {
+	if rankID == "" {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(types.RankIDToRankVal[rankID]), Valid: true}
+}
+
+// CommunityDTO is a community along with its rules. Moderators are only included when a single community is fetched.
+type CommunityDTO struct {
+	ID          int32                   `json:"id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Link        string                  `json:"link"`
+	Rules       CommunityRules          `json:"rules"`
+	Moderators  []CommunityModeratorDTO `json:"moderators,omitempty"`
+	CreatedAt   time.Time               `json:"createdAt"`
+}
+
+// CommunityModeratorDTO is a player that can manage a community and the groups in it.
+type CommunityModeratorDTO struct {
+	PlayerID  int32     `json:"playerId"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func toCommunityDTO(row repository.Community) CommunityDTO {
+	return CommunityDTO{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description,
+		Link:        row.Link,
+		Rules:       toCommunityRules(row),
+		CreatedAt:   row.CreatedAt,
+	}
+}
+
+type CreateCommunityParams struct {
+	Name        string
+	Description string
+	Link        string
+	Rules       CommunityRules
+	// CreatedBy becomes the community's first moderator
+	CreatedBy int32
+}
+
+// UpdateCommunityParams changes a community. Fields that are left out keep their current value.
+type UpdateCommunityParams struct {
+	ID          int32
+	ModeratorID int32
+
+	Name        *string
+	Description *string
+	Link        *string
+	Rules       *CommunityRules
+}
+
+type Community struct {
+	repo   *repository.Queries
+	admins types.Set[int32]
+}
+
+func NewCommunity(repo *repository.Queries) *Community {
+	return &Community{
+		repo:   repo,
+		admins: types.NewSet[int32](),
+	}
+}
+
+// SetAdmins sets the players who can create communities, on top of the default community's moderators.
+func (s *Community) SetAdmins(playerIDs []int32) {
+	s.admins = types.NewSet(playerIDs...)
+}
+
+// CreateCommunity creates the community, with the player who created it as its
+// only moderator. Only admins and the default community's moderators can create
+// communities. Nobody moderates anything on a fresh deploy, so the admins are
+// how the first communities get made.
+func (s *Community) CreateCommunity(ctx context.Context, arg CreateCommunityParams) (*CommunityDTO, error) {
+	if arg.Rules.Regions == nil {
+		arg.Rules.Regions = []string{}
+	}
+
+	var community CommunityDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if !s.admins.Contains(arg.CreatedBy) {
+			if err := requireModerator(ctx, q, DefaultCommunityID, arg.CreatedBy); err != nil {
+				return err
+			}
+		}
+
+		row, err := q.CreateCommunity(ctx, repository.CreateCommunityParams{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Link:        arg.Link,
+			MinRank:     rankVal(arg.Rules.MinRank),
+			MaxRank:     rankVal(arg.Rules.MaxRank),
+			Regions:     arg.Rules.Regions,
+			InviteOnly:  arg.Rules.InviteOnly,
+		})
+		if err != nil {
+			return err
+		}
+
+		added, err := q.AddCommunityModerator(ctx, repository.AddCommunityModeratorParams{
+			CommunityID: row.ID,
+			PlayerID:    arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+		if added == 0 {
+			return NewError(http.StatusNotFound, "Player not found.", nil)
+		}
+
+		community = toCommunityDTO(row)
+		community.Moderators, err = getCommunityModerators(ctx, q, row.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &community, nil
+}
+
+// GetCommunities returns every community, oldest first.
+func (s *Community) GetCommunities(ctx context.Context) ([]CommunityDTO, error) {
+	rows, err := s.repo.GetCommunities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	communities := make([]CommunityDTO, 0, len(rows))
+	for _, row := range rows {
+		communities = append(communities, toCommunityDTO(row))
+	}
+	return communities, nil
+}
+
+// GetCommunity returns the community along with its moderators.
+func (s *Community) GetCommunity(ctx context.Context, id int32) (*CommunityDTO, error) {
+	row, err := getCommunity(ctx, s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	community := toCommunityDTO(row)
+	community.Moderators, err = getCommunityModerators(ctx, s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+	return &community, nil
+}
+
+// UpdateCommunity changes the community. Only its moderators can change it,
+// and groups that were created under the old rules are left as they are.
+func (s *Community) UpdateCommunity(ctx context.Context, arg UpdateCommunityParams) (*CommunityDTO, error) {
+	var community CommunityDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		row, err := getCommunity(ctx, q, arg.ID)
+		if err != nil {
+			return err
+		}
+		if err := requireModerator(ctx, q, arg.ID, arg.ModeratorID); err != nil {
+			return err
+		}
+
+		community = toCommunityDTO(row)
+		if arg.Name != nil {
+			community.Name = *arg.Name
+		}
+		if arg.Description != nil {
+			community.Description = *arg.Description
+		}
+		if arg.Link != nil {
+			community.Link = *arg.Link
+		}
+		if arg.Rules != nil {
+			community.Rules = *arg.Rules
+			if community.Rules.Regions == nil {
+				community.Rules.Regions = []string{}
+			}
+		}
+
+		if err := q.UpdateCommunity(ctx, repository.UpdateCommunityParams{
+			ID:          arg.ID,
+			Name:        community.Name,
+			Description: community.Description,
+			Link:        community.Link,
+			MinRank:     rankVal(community.Rules.MinRank),
+			MaxRank:     rankVal(community.Rules.MaxRank),
+			Regions:     community.Rules.Regions,
+			InviteOnly:  community.Rules.InviteOnly,
+		}); err != nil {
+			return err
+		}
+
+		community.Moderators, err = getCommunityModerators(ctx, q, arg.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &community, nil
+}
+
+// DeleteCommunity deletes the community, once it has no groups left. Only its
+// moderators can delete it, and the default community is always kept.
+func (s *Community) DeleteCommunity(ctx context.Context, id, moderatorID int32) error {
+	if id == DefaultCommunityID {
+		return NewError(http.StatusBadRequest, "The default community can't be deleted.", nil)
+	}
+	return s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := getCommunity(ctx, q, id); err != nil {
+			return err
+		}
+		if err := requireModerator(ctx, q, id, moderatorID); err != nil {
+			return err
+		}
+
+		deleted, err := q.DeleteCommunity(ctx, id)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return NewError(http.StatusBadRequest, "Community still has groups.", nil)
+		}
+		return nil
+	})
+}
+
+// AddModerator makes the player a moderator of the community. Only its moderators can add others.
+func (s *Community) AddModerator(ctx context.Context, id, moderatorID, playerID int32) error {
+	return s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := getCommunity(ctx, q, id); err != nil {
+			return err
+		}
+		if err := requireModerator(ctx, q, id, moderatorID); err != nil {
+			return err
+		}
+
+		isModerator, err := q.IsCommunityModerator(ctx, repository.IsCommunityModeratorParams{
+			CommunityID: id,
+			PlayerID:    playerID,
+		})
+		if err != nil {
+			return err
+		}
+		if isModerator {
+			return nil
+		}
+
+		added, err := q.AddCommunityModerator(ctx, repository.AddCommunityModeratorParams{
+			CommunityID: id,
+			PlayerID:    playerID,
+		})
+		if err != nil {
+			return err
+		}
+		if added == 0 {
+			return NewError(http.StatusNotFound, "Player not found.", nil)
+		}
+		return nil
+	})
+}
+
+// RemoveModerator stops the player from moderating the community. Only its
+// moderators can remove others, or themselves, as long as one is left.
+func (s *Community) RemoveModerator(ctx context.Context, id, moderatorID, playerID int32) error {
+	return s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := getCommunity(ctx, q, id); err != nil {
+			return err
+		}
+		if err := requireModerator(ctx, q, id, moderatorID); err != nil {
+			return err
+		}
+
+		isModerator, err := q.IsCommunityModerator(ctx, repository.IsCommunityModeratorParams{
+			CommunityID: id,
+			PlayerID:    playerID,
+		})
+		if err != nil {
+			return err
+		}
+		if !isModerator {
+			return NewError(http.StatusNotFound, "Moderator not found.", nil)
+		}
+
+		removed, err := q.RemoveCommunityModerator(ctx, repository.RemoveCommunityModeratorParams{
+			CommunityID: id,
+			PlayerID:    playerID,
+		})
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return NewError(http.StatusBadRequest, "Communities need at least one moderator.", nil)
+		}
+		return nil
+	})
+}
+
+func getCommunity(ctx context.Context, q *repository.Queries, id int32) (repository.Community, error) {
+	row, err := q.GetCommunity(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.Community{}, NewError(http.StatusNotFound, "Community not found.", nil)
+		}
+		return repository.Community{}, err
+	}
+	return row, nil
+}
+
+func getCommunityModerators(ctx context.Context, q *repository.Queries, id int32) ([]CommunityModeratorDTO, error) {
+	rows, err := q.GetCommunityModerators(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	moderators := make([]CommunityModeratorDTO, 0, len(rows))
+	for _, row := range rows {
+		moderators = append(moderators, CommunityModeratorDTO{
+			PlayerID:  row.PlayerID,
+			Name:      row.Name,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return moderators, nil
+}
+
+// requireModerator fails unless the player moderates the community.
+func requireModerator(ctx context.Context, q *repository.Queries, id, playerID int32) error {
+	isModerator, err := q.IsCommunityModerator(ctx, repository.IsCommunityModeratorParams{
+		CommunityID: id,
+		PlayerID:    playerID,
+	})
+	if err != nil {
+		return err
+	}
+	if !isModerator {
+		return NewError(http.StatusForbidden, "Only the community's moderators can do that.", nil)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommunityRules_Validate(t *testing.T) {
+	t.Run("Should allow rules without any limits", func(t *testing.T) {
+		assert.NoError(t, services.CommunityRules{}.Validate())
+	})
+
+	t.Run("Should validate ranks", func(t *testing.T) {
+		assert.Error(t, services.CommunityRules{MinRank: "x1"}.Validate())
+		assert.Error(t, services.CommunityRules{MaxRank: "x1"}.Validate())
+	})
+
+	t.Run("Should not allow an empty rank range", func(t *testing.T) {
+		assert.NoError(t, services.CommunityRules{MinRank: "p3", MaxRank: "p3"}.Validate())
+		assert.Error(t, services.CommunityRules{MinRank: "d3", MaxRank: "p3"}.Validate())
+	})
+
+	t.Run("Should validate regions", func(t *testing.T) {
+		assert.NoError(t, services.CommunityRules{Regions: []string{"na", "eu"}}.Validate())
+		assert.Error(t, services.CommunityRules{Regions: []string{"na", "moon"}}.Validate())
+	})
+}
+
+func TestCommunityRules_ValidateGroup(t *testing.T) {
+	t.Run("Should allow any group if there are no rules", func(t *testing.T) {
+		assert.NoError(t, services.CommunityRules{}.ValidateGroup("sa", true))
+	})
+
+	t.Run("Should only allow groups in the community's regions", func(t *testing.T) {
+		rules := services.CommunityRules{Regions: []string{"na", "eu"}}
+		assert.NoError(t, rules.ValidateGroup("eu", true))
+		assert.ErrorContains(t, rules.ValidateGroup("ap", true), "Groups in this community must be in one of: na, eu.")
+	})
+
+	t.Run("Should only allow private groups if the community is invite-only", func(t *testing.T) {
+		rules := services.CommunityRules{InviteOnly: true}
+		assert.NoError(t, rules.ValidateGroup("na", false))
+		assert.ErrorContains(t, rules.ValidateGroup("na", true), "Groups in this community must be private.")
+	})
+}
+
+func TestCommunityRules_AllowsRank(t *testing.T) {
+	t.Run("Should allow any rank if the range is unbounded", func(t *testing.T) {
+		assert.True(t, services.CommunityRules{}.AllowsRank(0))
+		assert.True(t, services.CommunityRules{}.AllowsRank(80))
+	})
+
+	t.Run("Should only allow ranks within the range", func(t *testing.T) {
+		rules := services.CommunityRules{MinRank: "g3", MaxRank: "p1"}
+		assert.False(t, rules.AllowsRank(12))
+		assert.True(t, rules.AllowsRank(20))
+		assert.True(t, rules.AllowsRank(32))
+		assert.False(t, rules.AllowsRank(40))
+	})
+}
+
+// communityDB is an in-memory stand-in for the tables that creating a community touches.
+type communityDB struct {
+	players     map[int32]string
+	communities []repository.Community
+	moderators  map[int32][]int32
+}
+
+func newCommunityDB(players map[int32]string) *communityDB {
+	return &communityDB{
+		players:    players,
+		moderators: make(map[int32][]int32),
+	}
+}
+
+func (db *communityDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return &communityTx{db: db}, nil
+}
+
+func (db *communityDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	switch {
+	case strings.Contains(sql, "name: AddCommunityModerator"):
+		communityID, playerID := args[0].(int32), args[1].(int32)
+		if _, ok := db.players[playerID]; !ok {
+			return pgconn.NewCommandTag("INSERT 0 0"), nil
+		}
+		db.moderators[communityID] = append(db.moderators[communityID], playerID)
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+	}
+	return pgconn.CommandTag{}, fmt.Errorf("unexpected query: %s", sql)
+}
+
+func (db *communityDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	switch {
+	case strings.Contains(sql, "name: GetCommunityModerators"):
+		rows := &fakeRows{}
+		for _, playerID := range db.moderators[args[0].(int32)] {
+			rows.values = append(rows.values, []any{playerID, db.players[playerID], time.Time{}})
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("unexpected query: %s", sql)
+}
+
+func (db *communityDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	switch {
+	case strings.Contains(sql, "name: IsCommunityModerator"):
+		communityID, playerID := args[0].(int32), args[1].(int32)
+		for _, moderator := range db.moderators[communityID] {
+			if moderator == playerID {
+				return fakeRow{true}
+			}
+		}
+		return fakeRow{false}
+	case strings.Contains(sql, "name: CreateCommunity"):
+		id := int32(len(db.communities) + 2)
+		values := append([]any{id}, args...)
+		values = append(values, time.Time{})
+		return fakeRow(values)
+	}
+	return fakeRow{fmt.Errorf("unexpected query: %s", sql)}
+}
+
+type communityTx struct {
+	pgx.Tx
+	db *communityDB
+}
+
+func (tx *communityTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return tx.db.Exec(ctx, sql, args...)
+}
+
+func (tx *communityTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return tx.db.Query(ctx, sql, args...)
+}
+
+func (tx *communityTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return tx.db.QueryRow(ctx, sql, args...)
+}
+
+func (tx *communityTx) Commit(ctx context.Context) error   { return nil }
+func (tx *communityTx) Rollback(ctx context.Context) error { return nil }
+
+// fakeRow scans its values into the destinations in order, or returns its error.
+type fakeRow []any
+
+func (r fakeRow) Scan(dest ...any) error {
+	if len(r) == 1 {
+		if err, ok := r[0].(error); ok {
+			return err
+		}
+	}
+	for i, value := range r {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+type fakeRows struct {
+	pgx.Rows
+	values [][]any
+	next   int
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.values)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	return fakeRow(r.values[r.next-1]).Scan(dest...)
+}
+
+func (r *fakeRows) Close()     {}
+func (r *fakeRows) Err() error { return nil }
+
+func TestCommunity_CreateCommunity(t *testing.T) {
+	ctx := context.Background()
+	players := map[int32]string{1: "imphungky", 2: "dunkel"}
+
+	t.Run("Should let an admin create the first community", func(t *testing.T) {
+		db := newCommunityDB(players)
+		s := services.NewCommunity(repository.New(db))
+		s.SetAdmins([]int32{1})
+
+		community, err := s.CreateCommunity(ctx, services.CreateCommunityParams{Name: "Grandmasters", CreatedBy: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "Grandmasters", community.Name)
+		assert.Equal(t, []services.CommunityModeratorDTO{{PlayerID: 1, Name: "imphungky"}}, community.Moderators)
+	})
+
+	t.Run("Should refuse players who are neither admins nor moderators", func(t *testing.T) {
+		db := newCommunityDB(players)
+		s := services.NewCommunity(repository.New(db))
+		s.SetAdmins([]int32{1})
+
+		_, err := s.CreateCommunity(ctx, services.CreateCommunityParams{Name: "Grandmasters", CreatedBy: 2})
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, err.(services.Error).Code())
+	})
+
+	t.Run("Should let the default community's moderators create communities", func(t *testing.T) {
+		db := newCommunityDB(players)
+		db.moderators[services.DefaultCommunityID] = []int32{2}
+		s := services.NewCommunity(repository.New(db))
+
+		community, err := s.CreateCommunity(ctx, services.CreateCommunityParams{Name: "Grandmasters", CreatedBy: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []services.CommunityModeratorDTO{{PlayerID: 2, Name: "dunkel"}}, community.Moderators)
+	})
+}
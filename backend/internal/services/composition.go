@@ -0,0 +1,106 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+const (
+	// How many groups are scored when sorting by composition, since the sort can't be paginated by the query.
+	maxCompositionCandidates = 250
+
+	roleBalanceWeight = 2.0
+	overlapWeight     = 1.0
+	teamUpWeight      = 0.5
+)
+
+// CompositionScore rates how well a player would round out the group, higher
+// being better. The player is rewarded for filling a role that the group is
+// short on, penalized for how much of their character pool members already
+// play, and rewarded for each team-up that their pool makes possible with the
+// members' heroes.
+func CompositionScore(group *repository.GroupWithPlayers, roles []string, characters []string) float64 {
+	groupHeroes := types.NewSet[string]()
+	for _, player := range group.Players {
+		groupHeroes.Add(player.Characters...)
+	}
+	pool := types.NewSet(characters...)
+
+	score := roleBalanceWeight * roleBalance(group, roles)
+	if len(pool) > 0 {
+		overlap := float64(len(pool.Intersection(groupHeroes))) / float64(len(pool))
+		score -= overlapWeight * overlap
+	}
+
+	combined := groupHeroes.Union(pool)
+	for _, teamUp := range types.TeamUps {
+		// Team-ups that either side can activate on their own aren't down to the composition
+		if teamUp.ActiveWith(combined) && !teamUp.ActiveWith(groupHeroes) && !teamUp.ActiveWith(pool) {
+			score += teamUpWeight
+		}
+	}
+	return score
+}
+
+// roleBalance is the share of the player's role that the group still needs,
+// from 0 if it's covered to 1 if nobody plays it yet. Groups without a role
+// queue are measured against a standard 2-2-2 team.
+func roleBalance(group *repository.GroupWithPlayers, roles []string) float64 {
+	role := SeatRole(group, roles)
+	if role == "" {
+		return 0
+	}
+
+	target := formedGroupRoleQueue
+	if rq := group.RoleQueue; rq != nil && rq.Vanguards+rq.Duelists+rq.Strategists > 0 {
+		target = *rq
+	}
+	needed := map[string]int{
+		"vanguard":   target.Vanguards,
+		"duelist":    target.Duelists,
+		"strategist": target.Strategists,
+	}[role]
+	if needed == 0 {
+		return 0
+	}
+
+	playing := 0
+	for _, player := range group.Players {
+		if strings.ToLower(player.Role) == role {
+			playing++
+		}
+	}
+	return float64(max(needed-playing, 0)) / float64(needed)
+}
+
+// sortByComposition orders the groups by their composition score for the player, best first unless ascending.
+// Groups with the same score keep their order.
+func sortByComposition(groups []repository.GroupWithPlayers, roles []string, characters []string, ascending bool) {
+	scores := make(map[string]float64, len(groups))
+	for i := range groups {
+		scores[groups[i].ID] = CompositionScore(&groups[i], roles, characters)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if ascending {
+			return scores[groups[i].ID] < scores[groups[j].ID]
+		}
+		return scores[groups[i].ID] > scores[groups[j].ID]
+	})
+}
+
+// PageByComposition sorts the candidates by their composition score for the
+// player, and returns the requested page of them. Only the candidates are
+// sorted, so the total returned is how many of them there are rather than how
+// many groups matched, and pages past the candidates are empty.
+func PageByComposition(candidates []repository.GroupWithPlayers, roles []string, characters []string, ascending bool, limit, offset int) ([]repository.GroupWithPlayers, int32) {
+	sortByComposition(candidates, roles, characters, ascending)
+
+	page := candidates[min(offset, len(candidates)):]
+	if limit > 0 && len(page) > limit {
+		page = page[:limit]
+	}
+	return page, int32(len(candidates))
+}
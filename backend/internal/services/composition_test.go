@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func groupWithHeroes(members ...repository.PlayerInGroup) *repository.GroupWithPlayers {
+	group := groupOfSize("AAAA", len(members))
+	group.Players = members
+	return &group
+}
+
+func TestCompositionScore(t *testing.T) {
+	t.Run("Should prefer groups that need the player's role", func(t *testing.T) {
+		needsVanguard := groupWithHeroes(
+			repository.PlayerInGroup{Role: "duelist", Characters: []string{"Hela"}},
+		)
+		hasVanguard := groupWithHeroes(
+			repository.PlayerInGroup{Role: "vanguard", Characters: []string{"Thor"}},
+		)
+
+		roles := []string{"vanguard"}
+		assert.Greater(t,
+			services.CompositionScore(needsVanguard, roles, []string{"Magneto"}),
+			services.CompositionScore(hasVanguard, roles, []string{"Magneto"}),
+		)
+	})
+
+	t.Run("Should penalize characters that members already play", func(t *testing.T) {
+		group := groupWithHeroes(
+			repository.PlayerInGroup{Role: "duelist", Characters: []string{"Hela", "Storm"}},
+		)
+
+		roles := []string{"duelist"}
+		assert.Greater(t,
+			services.CompositionScore(group, roles, []string{"Black Panther"}),
+			services.CompositionScore(group, roles, []string{"Storm"}),
+		)
+	})
+
+	t.Run("Should reward team-ups that the player makes possible", func(t *testing.T) {
+		group := groupWithHeroes(
+			repository.PlayerInGroup{Role: "vanguard", Characters: []string{"Hulk"}},
+		)
+
+		roles := []string{"vanguard"}
+		assert.Greater(t,
+			services.CompositionScore(group, roles, []string{"Doctor Strange"}),
+			services.CompositionScore(group, roles, []string{"Magneto"}),
+		)
+	})
+
+	t.Run("Should not reward team-ups that the group already has", func(t *testing.T) {
+		group := groupWithHeroes(
+			repository.PlayerInGroup{Role: "vanguard", Characters: []string{"Hulk", "Iron Man"}},
+		)
+
+		roles := []string{"vanguard"}
+		assert.Equal(t,
+			services.CompositionScore(group, roles, []string{"Doctor Strange"}),
+			services.CompositionScore(group, roles, []string{"Magneto"}),
+		)
+	})
+}
+
+func TestPageByComposition(t *testing.T) {
+	candidates := func() []repository.GroupWithPlayers {
+		return []repository.GroupWithPlayers{groupOfSize("AAAA", 1), groupOfSize("BBBB", 2), groupOfSize("CCCC", 3)}
+	}
+
+	t.Run("Should return the page along with how many candidates there are", func(t *testing.T) {
+		page, total := services.PageByComposition(candidates(), []string{"duelist"}, nil, false, 2, 2)
+		assert.Len(t, page, 1)
+		assert.Equal(t, int32(3), total)
+	})
+	t.Run("Should return an empty page past the candidates", func(t *testing.T) {
+		page, total := services.PageByComposition(candidates(), []string{"duelist"}, nil, false, 2, 4)
+		assert.Empty(t, page)
+		assert.Equal(t, int32(3), total)
+	})
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+const (
+	RequirementGamemode  = "gamemode"
+	RequirementRegion    = "region"
+	RequirementPlatform  = "platform"
+	RequirementRole      = "role"
+	RequirementRank      = "rank"
+	RequirementVoiceChat = "voiceChat"
+	RequirementMic       = "mic"
+)
+
+// Requirement is a single check that a player must pass to join a group.
+type Requirement struct {
+	Name     string `json:"name"`
+	Met      bool   `json:"met"`
+	Expected any    `json:"expected"`
+	Actual   any    `json:"actual"`
+}
+
+type Eligibility struct {
+	GroupID      string        `json:"groupId"`
+	Eligible     bool          `json:"eligible"`
+	Requirements []Requirement `json:"requirements"`
+}
+
+// Unmet returns the requirements that the player does not meet.
+func (e *Eligibility) Unmet() []Requirement {
+	unmet := make([]Requirement, 0)
+	for _, requirement := range e.Requirements {
+		if !requirement.Met {
+			unmet = append(unmet, requirement)
+		}
+	}
+	return unmet
+}
+
+func (s *Group) GetEligibility(ctx context.Context, groupID string, player repository.JoinGroupParams) (*Eligibility, error) {
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, nil
+	}
+	return CheckEligibility(group, player), nil
+}
+
+// CheckEligibility evaluates each of the group's requirements against the
+// player, mirroring the checks that JoinGroup makes. Passcodes are not
+// considered a requirement, since they're checked separately.
+func CheckEligibility(group *repository.GroupWithPlayers, player repository.JoinGroupParams) *Eligibility {
+	rankVal, _ := player.RankVal.(int32)
+
+	settings := repository.GroupSettings{}
+	if group.GroupSettings != nil {
+		settings = *group.GroupSettings
+	}
+
+	requirements := []Requirement{
+		{
+			Name:     RequirementGamemode,
+			Met:      group.Gamemode == player.Gamemode,
+			Expected: group.Gamemode,
+			Actual:   player.Gamemode,
+		},
+		{
+			Name:     RequirementRegion,
+			Met:      group.Region == player.Region,
+			Expected: group.Region,
+			Actual:   player.Region,
+		},
+		{
+			Name:     RequirementPlatform,
+			Met:      settings.Platform == player.Platform,
+			Expected: settings.Platform,
+			Actual:   player.Platform,
+		},
+		checkRole(group, rolePreferences(player)),
+		checkRank(group, int(rankVal), player.AllowedRanks),
+		{
+			Name:     RequirementVoiceChat,
+			Met:      !settings.VoiceChat || player.VoiceChat,
+			Expected: settings.VoiceChat,
+			Actual:   player.VoiceChat,
+		},
+		{
+			Name:     RequirementMic,
+			Met:      !settings.Mic || player.Mic,
+			Expected: settings.Mic,
+			Actual:   player.Mic,
+		},
+	}
+
+	eligible := true
+	for _, requirement := range requirements {
+		eligible = eligible && requirement.Met
+	}
+	return &Eligibility{
+		GroupID:      group.ID,
+		Eligible:     eligible,
+		Requirements: requirements,
+	}
+}
+
+// checkRole expects one of the roles that still have open slots, or any role
+// if the group has no role queue. It's met if any of the player's preferred roles is open.
+func checkRole(group *repository.GroupWithPlayers, roles []string) Requirement {
+	requirement := Requirement{
+		Name:   RequirementRole,
+		Actual: roles,
+	}
+
+	rq := group.RoleQueue
+	if rq == nil || rq.Vanguards+rq.Duelists+rq.Strategists == 0 {
+		requirement.Met = len(roles) > 0
+		requirement.Expected = []string{"vanguard", "duelist", "strategist"}
+		return requirement
+	}
+
+	open := openRoles(group)
+	expected := make([]string, 0, len(open))
+	for _, r := range []string{"vanguard", "duelist", "strategist"} {
+		if open[r] > 0 {
+			expected = append(expected, r)
+		}
+	}
+	requirement.Expected = expected
+	for _, role := range roles {
+		requirement.Met = requirement.Met || open[role] > 0
+	}
+	return requirement
+}
+
+// SeatRole returns the first of the preferred roles that has an open slot, mirroring how JoinGroup seats players.
+func SeatRole(group *repository.GroupWithPlayers, roles []string) string {
+	if len(roles) == 0 {
+		return ""
+	}
+
+	rq := group.RoleQueue
+	if rq == nil || rq.Vanguards+rq.Duelists+rq.Strategists == 0 {
+		return roles[0]
+	}
+
+	open := openRoles(group)
+	for _, role := range roles {
+		if open[role] > 0 {
+			return role
+		}
+	}
+	return ""
+}
+
+// openRoles returns how many slots of each role are still open in the group's role queue.
+func openRoles(group *repository.GroupWithPlayers) map[string]int {
+	open := map[string]int{}
+	if rq := group.RoleQueue; rq != nil {
+		open["vanguard"] = rq.Vanguards
+		open["duelist"] = rq.Duelists
+		open["strategist"] = rq.Strategists
+	}
+	for _, player := range group.Players {
+		open[strings.ToLower(player.Role)]--
+	}
+	return open
+}
+
+// checkRank expects one of the ranks that every member can group with under
+// the gamemode's rank policy. Players that were given the ranks they can group
+// with, such as queued players whose constraints have relaxed, instead need
+// every member's rank to be one of them. Either way, the player's rank has to
+// be one that the group's community allows.
+func checkRank(group *repository.GroupWithPlayers, rankVal int, allowedRanks []int32) Requirement {
+	policy := types.RankPolicyFor(group.Gamemode)
+
+	memberRanks := make([]int, 0, len(group.Players))
+	for _, player := range group.Players {
+		memberRanks = append(memberRanks, types.RankIDToRankVal[player.Rank])
+	}
+
+	met := policy.CanJoin(rankVal, memberRanks)
+	if allowedRanks != nil {
+		met = true
+		for _, memberRank := range memberRanks {
+			if !slices.Contains(allowedRanks, int32(memberRank)) {
+				met = false
+			}
+		}
+	}
+
+	met = met && group.AllowsRank(rankVal)
+
+	expected := make([]string, 0)
+	for _, val := range types.RankVals() {
+		if policy.CanJoin(val, memberRanks) && group.AllowsRank(val) {
+			expected = append(expected, types.RankValToRankID[val])
+		}
+	}
+
+	return Requirement{
+		Name:     RequirementRank,
+		Met:      met,
+		Expected: expected,
+		Actual:   types.RankValToRankID[rankVal],
+	}
+}
+
+// newRequirementsError is returned when a join fails on the group's requirements, listing the ones that were not met.
+func newRequirementsError(eligibility *Eligibility) Error {
+	return NewDetailedError(http.StatusBadRequest, "Group requirements not met.", map[string]any{
+		"requirements": eligibility.Unmet(),
+	}, nil)
+}
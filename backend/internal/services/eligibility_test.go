@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func eligibilityGroup() *repository.GroupWithPlayers {
+	return &repository.GroupWithPlayers{
+		GroupDTO: repository.GroupDTO{
+			ID:        "AAAA",
+			Region:    "na",
+			Gamemode:  "competitive",
+			Open:      true,
+			RoleQueue: &repository.RoleQueue{Vanguards: 1, Duelists: 2, Strategists: 2},
+			GroupSettings: &repository.GroupSettings{
+				Platform:  "pc",
+				VoiceChat: true,
+				Mic:       true,
+			},
+		},
+		Players: []repository.PlayerInGroup{
+			{ID: 1, Role: "vanguard", Rank: "p3"},
+			{ID: 2, Role: "duelist", Rank: "p1"},
+		},
+	}
+}
+
+func requirement(eligibility *services.Eligibility, name string) services.Requirement {
+	for _, r := range eligibility.Requirements {
+		if r.Name == name {
+			return r
+		}
+	}
+	return services.Requirement{}
+}
+
+func TestCheckEligibility(t *testing.T) {
+	t.Run("Should be eligible if every requirement is met", func(t *testing.T) {
+		player := queuedPlayer()
+		player.Role = "strategist"
+		player.RankVal = int32(31)
+
+		eligibility := services.CheckEligibility(eligibilityGroup(), player)
+		assert.True(t, eligibility.Eligible)
+		assert.Len(t, eligibility.Requirements, 7)
+		assert.Empty(t, eligibility.Unmet())
+	})
+
+	t.Run("Should list open roles if the player's role is full", func(t *testing.T) {
+		eligibility := services.CheckEligibility(eligibilityGroup(), queuedPlayer())
+		assert.False(t, eligibility.Eligible)
+		assert.Equal(t, services.Requirement{
+			Name:     services.RequirementRole,
+			Met:      false,
+			Expected: []string{"duelist", "strategist"},
+			Actual:   []string{"vanguard"},
+		}, requirement(eligibility, services.RequirementRole))
+	})
+
+	t.Run("Should meet the role requirement if any preferred role is open", func(t *testing.T) {
+		player := queuedPlayer()
+		player.Roles = []string{"vanguard", "strategist"}
+		player.RankVal = int32(31)
+
+		eligibility := services.CheckEligibility(eligibilityGroup(), player)
+		assert.True(t, eligibility.Eligible)
+		assert.Equal(t, []string{"vanguard", "strategist"}, requirement(eligibility, services.RequirementRole).Actual)
+	})
+
+	t.Run("Should list the ranks that every member can group with", func(t *testing.T) {
+		player := queuedPlayer()
+		player.Role = "duelist"
+		player.RankVal = int32(50)
+
+		eligibility := services.CheckEligibility(eligibilityGroup(), player)
+		assert.Equal(t, services.Requirement{
+			Name:     services.RequirementRank,
+			Met:      false,
+			Expected: []string{"g1", "p3", "p2", "p1", "d3"},
+			Actual:   "gm3",
+		}, requirement(eligibility, services.RequirementRank))
+	})
+
+	t.Run("Should check members against the player's allowed ranks if given", func(t *testing.T) {
+		player := queuedPlayer()
+		player.Role = "duelist"
+		player.RankVal = int32(50)
+
+		player.AllowedRanks = []int32{30, 31, 32, 40, 50}
+		assert.True(t, requirement(services.CheckEligibility(eligibilityGroup(), player), services.RequirementRank).Met)
+
+		player.AllowedRanks = []int32{40, 50}
+		assert.False(t, requirement(services.CheckEligibility(eligibilityGroup(), player), services.RequirementRank).Met)
+	})
+
+	t.Run("Should leave out ranks that the group's community doesn't allow", func(t *testing.T) {
+		group := eligibilityGroup()
+		maxRank := int32(31)
+		group.MaxRank = &maxRank
+		player := queuedPlayer()
+		player.Role = "duelist"
+		player.RankVal = int32(32)
+
+		assert.Equal(t, services.Requirement{
+			Name:     services.RequirementRank,
+			Met:      false,
+			Expected: []string{"g1", "p3", "p2"},
+			Actual:   "p1",
+		}, requirement(services.CheckEligibility(group, player), services.RequirementRank))
+	})
+
+	t.Run("Should report every unmet requirement", func(t *testing.T) {
+		player := queuedPlayer()
+		player.Role = "duelist"
+		player.RankVal = int32(31)
+		player.Region = "eu"
+		player.Platform = "co"
+		player.Mic = false
+
+		eligibility := services.CheckEligibility(eligibilityGroup(), player)
+		names := make([]string, 0)
+		for _, r := range eligibility.Unmet() {
+			names = append(names, r.Name)
+		}
+		assert.Equal(t, []string{services.RequirementRegion, services.RequirementPlatform, services.RequirementMic}, names)
+	})
+}
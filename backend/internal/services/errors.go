@@ -74,3 +74,31 @@ func (b baseError) Code() int {
 func (b baseError) Message() string {
 	return b.message
 }
+
+// DetailedError is an Error that carries structured details for the client,
+// such as which requirements were not met.
+type DetailedError interface {
+	Error
+
+	Details() map[string]any
+}
+
+func NewDetailedError(code int, message string, details map[string]any, origErr error) DetailedError {
+	var errs []error
+	if origErr != nil {
+		errs = append(errs, origErr)
+	}
+	return &detailedError{
+		baseError: *newBaseError(code, message, errs),
+		details:   details,
+	}
+}
+
+type detailedError struct {
+	baseError
+	details map[string]any
+}
+
+func (d detailedError) Details() map[string]any {
+	return d.details
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+const (
+	// The fewest queued players that are worth forming a new group for.
+	minFormedGroupSize = 2
+)
+
+// Groups formed from the queue use a standard 2-2-2 role queue, so that they can keep filling up through matchmaking.
+var formedGroupRoleQueue = repository.RoleQueue{
+	Vanguards:   2,
+	Duelists:    2,
+	Strategists: 2,
+}
+
+// FormGroups creates new groups out of queued players that could not be placed
+// into an existing group. Players are bucketed by region, gamemode and
+// platform, and then greedily grouped oldest first as long as their current
+// constraints allow each other and they fit into the role queue. The longest waiting player of each
+// group becomes its leader. Parties form groups of their own, which keep
+// filling up through matchmaking. Players that can't be placed stay queued.
+func (m *Matcher) FormGroups(ctx context.Context) {
+	entries := m.pending()
+
+	placed := make(map[int32]*MatchResult)
+	players := make([]*QueueEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.PartyID == "" {
+			players = append(players, entry)
+			continue
+		}
+
+		result, err := m.formParty(ctx, entry)
+		if err != nil {
+			log.Error(ctx, fmt.Sprintf("unable to form group for party %s: %v", entry.PartyID, err))
+		}
+		placed[entry.PlayerID] = result
+	}
+
+	for _, bucket := range bucketByRequirements(players) {
+		for len(bucket) >= minFormedGroupSize {
+			party, rest := pickParty(bucket)
+			if len(party) < minFormedGroupSize {
+				// Nobody can group with the oldest player right now
+				bucket = bucket[1:]
+				continue
+			}
+			bucket = rest
+
+			results, err := m.form(ctx, party)
+			if err != nil {
+				log.Error(ctx, fmt.Sprintf("unable to form group for player %d: %v", party[0].PlayerID, err))
+			}
+			for _, result := range results {
+				placed[result.PlayerID] = result
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		m.finish(entry.PlayerID, placed[entry.PlayerID])
+	}
+}
+
+// form creates a group led by the first player of the party, and seats
+// everyone else in it. The group requires voice chat if anyone in the party
+// does.
+func (m *Matcher) form(ctx context.Context, party []*QueueEntry) ([]*MatchResult, error) {
+	created, err := m.createGroup(ctx, party[0].Player, requiresVoiceChat(party))
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*MatchResult{
+		{
+			GroupID:  created.GroupID,
+			PlayerID: created.PlayerID,
+			Leader:   true,
+		},
+	}
+	for _, entry := range party[1:] {
+		player := withConstraints(entry.Player, entry.Constraints)
+		player.GroupID = created.GroupID
+		playerID, err := m.players.JoinGroup(ctx, player)
+		if err != nil {
+			log.Debug(ctx, fmt.Sprintf("unable to seat player %d in formed group %s: %v", player.PlayerID, created.GroupID, err))
+			continue
+		}
+		results = append(results, &MatchResult{
+			GroupID:  created.GroupID,
+			PlayerID: playerID,
+		})
+	}
+	return results, nil
+}
+
+// formParty creates a group led by the party's leader, and brings the rest of
+// the party into it. Nothing is formed unless every member could be seated,
+// in which case the party stays queued.
+func (m *Matcher) formParty(ctx context.Context, entry *QueueEntry) (*MatchResult, error) {
+	party, err := m.parties.GetParty(ctx, entry.PartyID)
+	if err != nil {
+		return nil, err
+	}
+	if party == nil || party.LeaderID != entry.PlayerID || party.Size() < minFormedGroupSize {
+		return nil, nil
+	}
+
+	// The leader is described by the info they queued with. The rest of the
+	// party is held to the leader's constraints, except for the rank spread,
+	// since joining as a party checks members against the gamemode's policy.
+	members := []*QueueEntry{entry}
+	for _, player := range party.players(entry.Player.Gamemode, entry.Player.Region)[1:] {
+		constraints := entry.Constraints
+		constraints.MaxRankSpread = types.RankPolicyFor(player.Gamemode).MaxSpread
+		members = append(members, &QueueEntry{PlayerID: player.PlayerID, Player: player, Constraints: constraints})
+	}
+	if len(bucketByRequirements(members)) != 1 {
+		return nil, nil
+	}
+	seated, _ := pickParty(members)
+	if len(seated) < len(members) {
+		return nil, nil
+	}
+
+	created, err := m.createGroup(ctx, seated[0].Player, requiresVoiceChat(seated))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MatchResult{
+		GroupID:  created.GroupID,
+		PlayerID: created.PlayerID,
+		Leader:   true,
+		PartyID:  party.ID,
+	}
+	// The leader can still bring the party in themselves if this fails
+	if _, err := m.parties.JoinGroup(ctx, JoinGroupAsPartyParams{
+		GroupID:  created.GroupID,
+		PartyID:  party.ID,
+		LeaderID: party.LeaderID,
+	}); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// createGroup creates an open group with the formed group role queue, led by the player.
+func (m *Matcher) createGroup(ctx context.Context, leader repository.JoinGroupParams, voiceChat bool) (repository.CreateGroupRow, error) {
+	role := leader.Roles[0]
+	rankVal, _ := leader.RankVal.(int32)
+
+	return m.groups.CreateGroup(ctx, repository.CreateGroupParams{
+		GroupID:        "",
+		PlayerID:       leader.PlayerID,
+		Owner:          leader.Name,
+		Platform:       leader.Platform,
+		Role:           role,
+		RankVal:        rankVal,
+		Characters:     leader.Characters,
+		VoiceChat:      leader.VoiceChat,
+		Mic:            leader.Mic,
+		Region:         leader.Region,
+		Gamemode:       leader.Gamemode,
+		Open:           true,
+		Vanguards:      int32(formedGroupRoleQueue.Vanguards),
+		Duelists:       int32(formedGroupRoleQueue.Duelists),
+		Strategists:    int32(formedGroupRoleQueue.Strategists),
+		GroupVoiceChat: pgtype.Bool{Bool: voiceChat, Valid: true},
+		GroupMic:       pgtype.Bool{Bool: false, Valid: true},
+	})
+}
+
+// bucketByRequirements splits entries into buckets that could share a group, keeping them oldest first.
+func bucketByRequirements(entries []*QueueEntry) [][]*QueueEntry {
+	keys := make([]string, 0)
+	buckets := make(map[string][]*QueueEntry)
+	for _, entry := range entries {
+		player := entry.Player
+		key := strings.Join([]string{player.Region, player.Gamemode, player.Platform}, ":")
+		if _, ok := buckets[key]; !ok {
+			keys = append(keys, key)
+		}
+		buckets[key] = append(buckets[key], entry)
+	}
+	sort.Strings(keys)
+
+	result := make([][]*QueueEntry, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, buckets[key])
+	}
+	return result
+}
+
+// pickParty greedily builds a party around the first entry of the bucket,
+// and returns it along with the entries that were left out. Players are only
+// grouped if each of their constraints allows the other. Each player is seated
+// in the first of their preferred roles that is still open, and their
+// preferences are narrowed down to that role so that joining seats them in it.
+func pickParty(bucket []*QueueEntry) ([]*QueueEntry, []*QueueEntry) {
+	capacity := formedGroupRoleQueue.Vanguards + formedGroupRoleQueue.Duelists + formedGroupRoleQueue.Strategists
+	slots := map[string]int{
+		"vanguard":   formedGroupRoleQueue.Vanguards,
+		"duelist":    formedGroupRoleQueue.Duelists,
+		"strategist": formedGroupRoleQueue.Strategists,
+	}
+
+	party := make([]*QueueEntry, 0, capacity)
+	rest := make([]*QueueEntry, 0, len(bucket))
+	for _, entry := range bucket {
+		seat := ""
+		for _, role := range rolePreferences(entry.Player) {
+			if slots[role] > 0 {
+				seat = role
+				break
+			}
+		}
+
+		if len(party) >= capacity || seat == "" || !canGroup(entry, party) {
+			rest = append(rest, entry)
+			continue
+		}
+
+		slots[seat]--
+		seated := *entry
+		seated.Player.Roles = []string{seat}
+		party = append(party, &seated)
+	}
+	return party, rest
+}
+
+// canGroup reports whether the entry's constraints and those of every member of the party allow each other.
+func canGroup(entry *QueueEntry, party []*QueueEntry) bool {
+	gamemode := entry.Player.Gamemode
+	rankVal, _ := entry.Player.RankVal.(int32)
+	policy := entry.Constraints.RankPolicy(gamemode)
+
+	voiceChat := entry.Constraints.RequireVoiceChat || requiresVoiceChat(party)
+	if voiceChat && !entry.Player.VoiceChat {
+		return false
+	}
+	for _, member := range party {
+		memberRank, _ := member.Player.RankVal.(int32)
+		if !policy.Compatible(int(rankVal), int(memberRank)) ||
+			!member.Constraints.RankPolicy(gamemode).Compatible(int(memberRank), int(rankVal)) {
+			return false
+		}
+		if voiceChat && !member.Player.VoiceChat {
+			return false
+		}
+	}
+	return true
+}
+
+// requiresVoiceChat reports whether anyone in the party only groups with players that use voice chat.
+func requiresVoiceChat(party []*QueueEntry) bool {
+	for _, entry := range party {
+		if entry.Constraints.RequireVoiceChat {
+			return true
+		}
+	}
+	return false
+}
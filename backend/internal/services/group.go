@@ -2,12 +2,38 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
 )
 
 type Group struct {
-	repo *repository.Queries
+	repo     *repository.Queries
+	notifier GroupNotifier
+	// Groups that haven't been active for this long are hidden from GetGroups, unless it's 0.
+	staleAfter time.Duration
+}
+
+// GroupNotifier is told about changes to groups that their connected members need to know about.
+type GroupNotifier interface {
+	GroupUpdated(ctx context.Context, group *repository.GroupWithPlayers)
+	GroupIdle(ctx context.Context, groupID string, expiresAt time.Time)
+	PasscodeRotated(ctx context.Context, groupID string)
+	PlayerPromoted(ctx context.Context, groupID string, previousOwnerID, ownerID int32, sessionID string)
+	JoinRequested(ctx context.Context, ownerID int32, request *JoinRequestDTO)
+	JoinRequestResolved(ctx context.Context, ownerID int32, request *JoinRequestDTO)
+	WaitlistOffered(ctx context.Context, entry *WaitlistEntryDTO)
+	GroupDeleted(ctx context.Context, groupID string)
+	GroupStarted(ctx context.Context, groupID string, rsvps []RsvpDTO)
+	MergeProposed(ctx context.Context, merge *MergeDTO)
+	MergeResolved(ctx context.Context, merge *MergeDTO)
 }
 
 func NewGroup(repo *repository.Queries) *Group {
@@ -16,7 +42,42 @@ func NewGroup(repo *repository.Queries) *Group {
 	}
 }
 
+// SetNotifier sets where group changes are pushed to.
+func (s *Group) SetNotifier(notifier GroupNotifier) {
+	s.notifier = notifier
+}
+
+// SetStaleAfter hides groups from GetGroups once they've been idle for the given time.
+func (s *Group) SetStaleAfter(staleAfter time.Duration) {
+	s.staleAfter = staleAfter
+}
+
+// CreateGroup creates the group in its community, or the default community if
+// it isn't given one, as long as the group follows the community's rules.
 func (s *Group) CreateGroup(ctx context.Context, arg repository.CreateGroupParams) (repository.CreateGroupRow, error) {
+	if arg.CommunityID == 0 {
+		arg.CommunityID = DefaultCommunityID
+	}
+	community, err := s.repo.GetCommunity(ctx, arg.CommunityID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.CreateGroupRow{}, NewError(http.StatusBadRequest, "Community not found.", nil)
+		}
+		return repository.CreateGroupRow{}, err
+	}
+	rules := toCommunityRules(community)
+	if err := rules.ValidateGroup(arg.Region, arg.Open); err != nil {
+		return repository.CreateGroupRow{}, err
+	}
+	if !rules.AllowsRank(int(arg.RankVal)) {
+		return repository.CreateGroupRow{}, NewError(http.StatusBadRequest, "Player's rank is not allowed in this community.", nil)
+	}
+
+	// Tags can't be null, so groups created without any get none
+	if arg.Tags == nil {
+		arg.Tags = []string{}
+	}
+
 	result, err := s.repo.CreateGroup(ctx, arg)
 	if err != nil {
 		return repository.CreateGroupRow{}, err
@@ -25,6 +86,16 @@ func (s *Group) CreateGroup(ctx context.Context, arg repository.CreateGroupParam
 }
 
 func (s *Group) GetGroups(ctx context.Context, arg repository.GetGroupsParams) ([]repository.GroupWithPlayers, int32, error) {
+	if arg.RankVal != nil && arg.AllowedRanks == nil {
+		arg.AllowedRanks = types.AllowedRanksByGamemode(int(*arg.RankVal))
+	}
+	if s.staleAfter > 0 && arg.ActiveSince == nil {
+		activeSince := time.Now().Add(-s.staleAfter)
+		arg.ActiveSince = &activeSince
+	}
+	if arg.CompositionSort != "" {
+		return s.getGroupsByComposition(ctx, arg)
+	}
 	result, err := s.repo.GetGroups(ctx, arg)
 	if err != nil {
 		return nil, 0, err
@@ -32,6 +103,25 @@ func (s *Group) GetGroups(ctx context.Context, arg repository.GetGroupsParams) (
 	return result.Groups, result.TotalCount, nil
 }
 
+// getGroupsByComposition scores the first maxCompositionCandidates groups, and
+// paginates them once sorted. The total is capped at the number of candidates,
+// since groups past them can't be paged to.
+func (s *Group) getGroupsByComposition(ctx context.Context, arg repository.GetGroupsParams) ([]repository.GroupWithPlayers, int32, error) {
+	limit, offset := arg.Limit, arg.Offset
+	arg.Limit, arg.Offset = maxCompositionCandidates, 0
+	result, err := s.repo.GetGroups(ctx, arg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	role := ""
+	if arg.Role != nil {
+		role = *arg.Role
+	}
+	groups, total := PageByComposition(result.Groups, types.RolePreferences(role, arg.Roles), arg.Characters, arg.CompositionSort == "asc", limit, offset)
+	return groups, total, nil
+}
+
 func (s *Group) GetGroupByID(ctx context.Context, id string, isGroupOwner bool) (*repository.GroupWithPlayers, error) {
 	group, err := s.repo.GetGroupByID(ctx, id)
 	if err != nil {
@@ -48,3 +138,567 @@ func (s *Group) GetGroupByID(ctx context.Context, id string, isGroupOwner bool)
 
 	return group, nil
 }
+
+// GetPasscode returns the passcode that players need to join the group while it's private. Only the owner can see it.
+func (s *Group) GetPasscode(ctx context.Context, groupID string, ownerID int32) (string, error) {
+	var passcode string
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := lockOwnedGroup(ctx, q, groupID, ownerID); err != nil {
+			return err
+		}
+
+		var err error
+		passcode, err = q.GetGroupPasscode(ctx, groupID)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return passcode, nil
+}
+
+// RotatePasscode replaces the group's passcode with a new one, so that
+// players who knew the old one can no longer join with it. Invites that
+// haven't been revoked yet are revoked along with it if revokeInvites is set.
+// Connected members are told that it changed, but not what it changed to.
+// Only the owner can rotate it.
+func (s *Group) RotatePasscode(ctx context.Context, groupID string, ownerID int32, revokeInvites bool) (string, error) {
+	var passcode string
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := lockOwnedGroup(ctx, q, groupID, ownerID); err != nil {
+			return err
+		}
+
+		var err error
+		passcode, err = q.RotateGroupPasscode(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if !revokeInvites {
+			return nil
+		}
+		return q.RevokeGroupInvites(ctx, groupID)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if s.notifier != nil {
+		s.notifier.PasscodeRotated(ctx, groupID)
+	}
+	return passcode, nil
+}
+
+// PromoteMember hands ownership of the group to one of its members. The
+// previous owner stays in the group as a regular member.
+func (s *Group) PromoteMember(ctx context.Context, groupID string, ownerID, playerID int32) error {
+	var sessionID string
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := q.LockGroup(ctx, groupID); err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusNotFound, "Group not found.", nil)
+			}
+			return err
+		}
+
+		group, err := q.GetGroupByID(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return NewError(http.StatusNotFound, "Group not found.", nil)
+		}
+		// Tokens outlive ownership, so the owner is checked against the group rather than trusted
+		if group.OwnerID != ownerID {
+			return NewError(http.StatusForbidden, "Only the group owner can promote members.", nil)
+		}
+		if playerID == ownerID {
+			return NewError(http.StatusBadRequest, "Player already owns the group.", nil)
+		}
+
+		var member *repository.PlayerInGroup
+		for i := range group.Players {
+			if int32(group.Players[i].ID) == playerID {
+				member = &group.Players[i]
+			}
+		}
+		if member == nil {
+			return NewError(http.StatusNotFound, "Player not found.", nil)
+		}
+
+		// The new owner's token is reissued with the session they joined from
+		sessions, err := q.GetGroupMemberSessions(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if session.PlayerID == playerID {
+				sessionID = session.SessionID.String
+			}
+		}
+
+		if err := q.SetGroupLeader(ctx, repository.SetGroupLeaderParams{
+			GroupID:  groupID,
+			PlayerID: playerID,
+		}); err != nil {
+			return err
+		}
+		return q.SetGroupOwner(ctx, repository.SetGroupOwnerParams{
+			ID:    groupID,
+			Owner: pgtype.Text{String: member.Name, Valid: true},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notifier != nil {
+		s.notifier.PlayerPromoted(ctx, groupID, ownerID, playerID, sessionID)
+	}
+	return nil
+}
+
+// UpdateMemberParams changes a member's role or character pool. Whatever is left out stays the same.
+type UpdateMemberParams struct {
+	GroupID  string
+	PlayerID int32
+
+	Role       *string
+	Characters *[]string
+}
+
+// UpdateMember moves the member to another role, or changes their character
+// pool, without them having to leave and rejoin. The group is locked while the
+// role queue is checked, so that two members can't both take its last slot.
+// Slots being held for waitlisted players or players who RSVP'd can't be taken.
+func (s *Group) UpdateMember(ctx context.Context, arg UpdateMemberParams) (*repository.GroupWithPlayers, error) {
+	var group *repository.GroupWithPlayers
+	var roleChanged bool
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		var err error
+		group, err = lockGroup(ctx, q, arg.GroupID)
+		if err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(group.Players, func(p repository.PlayerInGroup) bool {
+			return int32(p.ID) == arg.PlayerID
+		})
+		if i < 0 {
+			return NewError(http.StatusNotFound, "Player not found.", nil)
+		}
+
+		roleChanged = arg.Role != nil && !strings.EqualFold(*arg.Role, group.Players[i].Role)
+		if roleChanged {
+			others := *group
+			others.Players = slices.Delete(slices.Clone(group.Players), i, i+1)
+			others.Size--
+
+			waitlist, err := getWaitlist(ctx, q, group.ID)
+			if err != nil {
+				return err
+			}
+			held := HoldOffers(&others, waitlist, time.Now(), 0)
+			if group.Scheduled {
+				rsvps, err := getRsvps(ctx, q, group.ID)
+				if err != nil {
+					return err
+				}
+				held = HoldRsvps(held, rsvps, 0)
+			}
+			if SeatRole(held, []string{*arg.Role}) == "" {
+				return NewError(http.StatusBadRequest, fmt.Sprintf("Group has no open %s slots.", *arg.Role), nil)
+			}
+
+			if err := q.SetMemberRole(ctx, repository.SetMemberRoleParams{
+				GroupID:  group.ID,
+				PlayerID: arg.PlayerID,
+				Role:     *arg.Role,
+			}); err != nil {
+				return err
+			}
+			group.Players[i].Role = *arg.Role
+		}
+
+		if arg.Characters != nil {
+			if err := q.SetPlayerCharacters(ctx, repository.SetPlayerCharactersParams{
+				ID:         arg.PlayerID,
+				Characters: *arg.Characters,
+			}); err != nil {
+				return err
+			}
+			group.Players[i].Characters = *arg.Characters
+		}
+		return q.TouchGroup(ctx, group.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.GroupUpdated(ctx, group)
+	}
+	// The slot in the member's old role goes to the next player on the waitlist
+	if roleChanged {
+		s.offerOpenSlots(ctx, group.ID)
+	}
+	return group, nil
+}
+
+// DeleteGroup removes every membership along with the group in one
+// transaction, and lets connected members know once it's gone. Only the owner
+// can delete it.
+func (s *Group) DeleteGroup(ctx context.Context, groupID string, ownerID int32) error {
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := lockOwnedGroup(ctx, q, groupID, ownerID); err != nil {
+			return err
+		}
+		return deleteGroup(ctx, q, groupID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notifier != nil {
+		s.notifier.GroupDeleted(ctx, groupID)
+	}
+	return nil
+}
+
+// DeleteCommunityGroup deletes a group in the community on behalf of one of the
+// community's moderators, and lets connected members know once it's gone.
+func (s *Group) DeleteCommunityGroup(ctx context.Context, communityID int32, groupID string, moderatorID int32) error {
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		group, err := lockGroup(ctx, q, groupID)
+		if err != nil {
+			return err
+		}
+		if group.CommunityID != communityID {
+			return NewError(http.StatusNotFound, "Group not found.", nil)
+		}
+		if err := requireModerator(ctx, q, communityID, moderatorID); err != nil {
+			return err
+		}
+		return deleteGroup(ctx, q, groupID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notifier != nil {
+		s.notifier.GroupDeleted(ctx, groupID)
+	}
+	return nil
+}
+
+// RecordActivity marks the group as active, pushing back when it's considered idle.
+func (s *Group) RecordActivity(ctx context.Context, groupID string) error {
+	return s.repo.TouchGroup(ctx, groupID)
+}
+
+// GetIdleGroups returns the groups that haven't been active since idleSince, longest idle first.
+func (s *Group) GetIdleGroups(ctx context.Context, idleSince time.Time) ([]repository.GetIdleGroupsRow, error) {
+	return s.repo.GetIdleGroups(ctx, idleSince)
+}
+
+// DeleteIdleGroup deletes the group like DeleteGroup, but only if it still
+// hasn't been active since idleSince. It returns whether the group was deleted.
+func (s *Group) DeleteIdleGroup(ctx context.Context, groupID string, idleSince time.Time) (bool, error) {
+	deleted := false
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := q.LockIdleGroup(ctx, repository.LockIdleGroupParams{
+			ID:        groupID,
+			IdleSince: idleSince,
+		}); err != nil {
+			if err == pgx.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+		deleted = true
+		return deleteGroup(ctx, q, groupID)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if deleted && s.notifier != nil {
+		s.notifier.GroupDeleted(ctx, groupID)
+	}
+	return deleted, nil
+}
+
+// deleteGroup removes the group's memberships and then the group. The group must be locked.
+func deleteGroup(ctx context.Context, q *repository.Queries, groupID string) error {
+	if _, err := q.DeleteGroupMembers(ctx, groupID); err != nil {
+		return err
+	}
+	_, err := q.DeleteGroup(ctx, groupID)
+	return err
+}
+
+// UpdateGroupParams are the settings to change. Settings that are nil are left as they are.
+type UpdateGroupParams struct {
+	GroupID string
+	OwnerID int32
+
+	Region        *string
+	Gamemode      *string
+	Open          *bool
+	RequestToJoin *bool
+	Vanguards     *int
+	Duelists      *int
+	Strategists   *int
+	Platform      *string
+	VoiceChat     *bool
+	Mic           *bool
+
+	Description *string
+	Language    *string
+	Tags        *[]string
+}
+
+// Apply changes the group's settings in place.
+func (p UpdateGroupParams) Apply(group *repository.GroupWithPlayers) {
+	if p.Region != nil {
+		group.Region = *p.Region
+	}
+	if p.Gamemode != nil {
+		group.Gamemode = *p.Gamemode
+	}
+	if p.Open != nil {
+		group.Open = *p.Open
+		// Open groups can't take join requests, so opening one stops it from taking them
+		if group.Open && p.RequestToJoin == nil {
+			group.RequestToJoin = false
+		}
+	}
+	if p.RequestToJoin != nil {
+		group.RequestToJoin = *p.RequestToJoin
+	}
+	if p.Description != nil {
+		group.Description = *p.Description
+	}
+	if p.Language != nil {
+		group.Language = *p.Language
+	}
+	if p.Tags != nil {
+		group.Tags = *p.Tags
+	}
+
+	// The role queue and settings are copied rather than changed, since they may be shared with other copies of the group
+	roleQueue := repository.RoleQueue{}
+	if group.RoleQueue != nil {
+		roleQueue = *group.RoleQueue
+	}
+	if p.Vanguards != nil {
+		roleQueue.Vanguards = *p.Vanguards
+	}
+	if p.Duelists != nil {
+		roleQueue.Duelists = *p.Duelists
+	}
+	if p.Strategists != nil {
+		roleQueue.Strategists = *p.Strategists
+	}
+	group.RoleQueue = &roleQueue
+
+	settings := repository.GroupSettings{}
+	if group.GroupSettings != nil {
+		settings = *group.GroupSettings
+	}
+	if p.Platform != nil {
+		settings.Platform = *p.Platform
+	}
+	if p.VoiceChat != nil {
+		settings.VoiceChat = *p.VoiceChat
+	}
+	if p.Mic != nil {
+		settings.Mic = *p.Mic
+	}
+	group.GroupSettings = &settings
+	group.SetSlots()
+}
+
+// Requirements returns the names of the requirements that the change could stop members from meeting.
+func (p UpdateGroupParams) Requirements() types.Set[string] {
+	requirements := types.NewSet[string]()
+	if p.Region != nil {
+		requirements.Add(RequirementRegion)
+	}
+	if p.Gamemode != nil {
+		requirements.Add(RequirementGamemode, RequirementRank)
+	}
+	if p.Vanguards != nil || p.Duelists != nil || p.Strategists != nil {
+		requirements.Add(RequirementRole)
+	}
+	if p.Platform != nil {
+		requirements.Add(RequirementPlatform)
+	}
+	if p.VoiceChat != nil {
+		requirements.Add(RequirementVoiceChat)
+	}
+	if p.Mic != nil {
+		requirements.Add(RequirementMic)
+	}
+	return requirements
+}
+
+// validateCommunityRules checks that the group's region and visibility still
+// follow its community's rules. Members' ranks were checked when they joined.
+func validateCommunityRules(ctx context.Context, q *repository.Queries, group *repository.GroupWithPlayers) error {
+	community, err := q.GetCommunity(ctx, group.CommunityID)
+	if err != nil {
+		return err
+	}
+	return toCommunityRules(community).ValidateGroup(group.Region, group.Open)
+}
+
+// ValidateAccess checks that the group doesn't take join requests while anyone can join it.
+func ValidateAccess(group *repository.GroupWithPlayers) error {
+	if group.Open && group.RequestToJoin {
+		return NewError(http.StatusBadRequest, "Open groups can't take join requests.", nil)
+	}
+	return nil
+}
+
+// ValidateCapacity checks that the group's role queue and members fit into a team of its gamemode.
+func ValidateCapacity(group *repository.GroupWithPlayers) error {
+	rq := repository.RoleQueue{}
+	if group.RoleQueue != nil {
+		rq = *group.RoleQueue
+	}
+	if err := types.ValidateTeamSize(group.Gamemode, rq.Vanguards, rq.Duelists, rq.Strategists); err != nil {
+		return NewError(http.StatusBadRequest, err.Error(), nil)
+	}
+	if OpenSlots(*group) < 0 {
+		return NewError(http.StatusBadRequest, "Group has more members than fit in the team.", nil)
+	}
+	return nil
+}
+
+// ValidateMembers checks that each member still meets the given requirements,
+// alongside the other members and in the role that they're seated in. Other
+// requirements are skipped, so that members who were let in under relaxed
+// constraints don't block unrelated changes.
+func ValidateMembers(group *repository.GroupWithPlayers, requirements types.Set[string]) error {
+	if conflicts := memberConflicts(group, group.Players, requirements); len(conflicts) > 0 {
+		return NewDetailedError(http.StatusBadRequest, "Group settings exclude current members.", map[string]any{
+			"members": conflicts,
+		}, nil)
+	}
+	return nil
+}
+
+// memberConflicts lists which of the members don't meet the given requirements
+// alongside the rest of the group, along with the ones they don't meet.
+func memberConflicts(group *repository.GroupWithPlayers, members []repository.PlayerInGroup, requirements types.Set[string]) []map[string]any {
+	conflicts := make([]map[string]any, 0)
+	for _, member := range members {
+		others := *group
+		others.Players = slices.DeleteFunc(slices.Clone(group.Players), func(p repository.PlayerInGroup) bool {
+			return p.ID == member.ID
+		})
+
+		unmet := make([]Requirement, 0)
+		for _, requirement := range CheckEligibility(&others, memberAsPlayer(group, member)).Unmet() {
+			if requirements.Contains(requirement.Name) {
+				unmet = append(unmet, requirement)
+			}
+		}
+		if len(unmet) > 0 {
+			conflicts = append(conflicts, map[string]any{
+				"playerId":     member.ID,
+				"requirements": unmet,
+			})
+		}
+	}
+	return conflicts
+}
+
+// memberAsPlayer describes the member as a player joining the group in the role they're seated in.
+func memberAsPlayer(group *repository.GroupWithPlayers, member repository.PlayerInGroup) repository.JoinGroupParams {
+	role := strings.ToLower(member.Role)
+	return repository.JoinGroupParams{
+		GroupID:   group.ID,
+		PlayerID:  int32(member.ID),
+		Gamemode:  group.Gamemode,
+		Region:    group.Region,
+		Platform:  member.Platform,
+		Role:      role,
+		Roles:     []string{role},
+		RankVal:   int32(types.RankIDToRankVal[member.Rank]),
+		VoiceChat: member.VoiceChat,
+		Mic:       member.Mic,
+	}
+}
+
+// UpdateGroup changes the group's settings, as long as every current member
+// still meets the requirements that changed. Only the owner can update it.
+// Connected members are sent the new settings.
+func (s *Group) UpdateGroup(ctx context.Context, arg UpdateGroupParams) (*repository.GroupWithPlayers, error) {
+	var group *repository.GroupWithPlayers
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := q.LockGroup(ctx, arg.GroupID); err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusNotFound, "Group not found.", nil)
+			}
+			return err
+		}
+
+		var err error
+		group, err = q.GetGroupByID(ctx, arg.GroupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return NewError(http.StatusNotFound, "Group not found.", nil)
+		}
+		// Tokens outlive ownership, so the owner is checked against the group rather than trusted
+		if group.OwnerID != arg.OwnerID {
+			return NewError(http.StatusForbidden, "Only the group owner can update the group.", nil)
+		}
+
+		arg.Apply(group)
+		if err := ValidateAccess(group); err != nil {
+			return err
+		}
+		if arg.Region != nil || arg.Open != nil {
+			if err := validateCommunityRules(ctx, q, group); err != nil {
+				return err
+			}
+		}
+		if err := ValidateCapacity(group); err != nil {
+			return err
+		}
+		if err := ValidateMembers(group, arg.Requirements()); err != nil {
+			return err
+		}
+
+		return q.UpdateGroup(ctx, repository.UpdateGroupParams{
+			ID:            group.ID,
+			Region:        group.Region,
+			Gamemode:      group.Gamemode,
+			Open:          group.Open,
+			RequestToJoin: group.RequestToJoin,
+			Vanguards:     int32(group.RoleQueue.Vanguards),
+			Duelists:      int32(group.RoleQueue.Duelists),
+			Strategists:   int32(group.RoleQueue.Strategists),
+			Platform:      group.GroupSettings.Platform,
+			VoiceChat:     pgtype.Bool{Bool: group.GroupSettings.VoiceChat, Valid: true},
+			Mic:           pgtype.Bool{Bool: group.GroupSettings.Mic, Valid: true},
+			Description:   group.Description,
+			Language:      group.Language,
+			Tags:          group.Tags,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.GroupUpdated(ctx, group)
+	}
+	// Growing the role queue opens up slots for the waitlist
+	s.offerOpenSlots(ctx, group.ID)
+	return group, nil
+}
@@ -0,0 +1,201 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func memberedGroup() *repository.GroupWithPlayers {
+	return &repository.GroupWithPlayers{
+		GroupDTO: repository.GroupDTO{
+			ID:        "AAAA",
+			Region:    "na",
+			Gamemode:  "quickplay",
+			Open:      true,
+			RoleQueue: &repository.RoleQueue{Vanguards: 2, Duelists: 2, Strategists: 2},
+			GroupSettings: &repository.GroupSettings{
+				Platform: "pc",
+			},
+		},
+		Size: 3,
+		Players: []repository.PlayerInGroup{
+			{ID: 1, Platform: "pc", Role: "vanguard", Rank: "b3", VoiceChat: true, Mic: true},
+			{ID: 2, Platform: "pc", Role: "duelist", Rank: "gm1", VoiceChat: true},
+			{ID: 3, Platform: "pc", Role: "duelist", Rank: "gm1", VoiceChat: true},
+		},
+	}
+}
+
+func conflictingPlayers(t *testing.T, err error) []int {
+	t.Helper()
+	detailedErr, ok := err.(services.DetailedError)
+	if !assert.True(t, ok) {
+		return nil
+	}
+	ids := []int{}
+	for _, conflict := range detailedErr.Details()["members"].([]map[string]any) {
+		ids = append(ids, conflict["playerId"].(int))
+	}
+	return ids
+}
+
+func TestUpdateGroupParams_Apply(t *testing.T) {
+	t.Run("Should only change the settings that are given", func(t *testing.T) {
+		group := memberedGroup()
+		roleQueue := group.RoleQueue
+		region, duelists, voiceChat := "eu", 3, true
+
+		services.UpdateGroupParams{
+			Region:    &region,
+			Duelists:  &duelists,
+			VoiceChat: &voiceChat,
+		}.Apply(group)
+
+		assert.Equal(t, "eu", group.Region)
+		assert.Equal(t, "quickplay", group.Gamemode)
+		assert.Equal(t, repository.RoleQueue{Vanguards: 2, Duelists: 3, Strategists: 2}, *group.RoleQueue)
+		assert.Equal(t, repository.GroupSettings{Platform: "pc", VoiceChat: true}, *group.GroupSettings)
+		assert.Equal(t, 2, roleQueue.Duelists, "the original role queue should be left alone")
+	})
+
+	t.Run("Should change the description, language and tags", func(t *testing.T) {
+		group := memberedGroup()
+		description, language, tags := "Chill games", "fr", []string{"chill"}
+
+		services.UpdateGroupParams{
+			Description: &description,
+			Language:    &language,
+			Tags:        &tags,
+		}.Apply(group)
+
+		assert.Equal(t, "Chill games", group.Description)
+		assert.Equal(t, "fr", group.Language)
+		assert.Equal(t, []string{"chill"}, group.Tags)
+		assert.Equal(t, "quickplay", group.Gamemode)
+	})
+
+	t.Run("Should stop taking join requests once the group is opened", func(t *testing.T) {
+		group := memberedGroup()
+		group.RequestToJoin = true
+		open := true
+
+		services.UpdateGroupParams{Open: &open}.Apply(group)
+
+		assert.True(t, group.Open)
+		assert.False(t, group.RequestToJoin)
+	})
+}
+
+func TestValidateAccess(t *testing.T) {
+	validate := func(open, requestToJoin bool, arg services.UpdateGroupParams) error {
+		group := memberedGroup()
+		group.Open, group.RequestToJoin = open, requestToJoin
+		arg.Apply(group)
+		return services.ValidateAccess(group)
+	}
+	yes, no := true, false
+
+	t.Run("Should allow opening a group that took join requests", func(t *testing.T) {
+		assert.NoError(t, validate(false, true, services.UpdateGroupParams{Open: &yes}))
+	})
+	t.Run("Should allow taking join requests once the group is private", func(t *testing.T) {
+		assert.NoError(t, validate(true, false, services.UpdateGroupParams{Open: &no, RequestToJoin: &yes}))
+	})
+	t.Run("Should refuse taking join requests while the group is open", func(t *testing.T) {
+		assert.Error(t, validate(true, false, services.UpdateGroupParams{RequestToJoin: &yes}))
+		assert.Error(t, validate(false, false, services.UpdateGroupParams{Open: &yes, RequestToJoin: &yes}))
+	})
+}
+
+func TestValidateMembers(t *testing.T) {
+	validate := func(arg services.UpdateGroupParams) error {
+		group := memberedGroup()
+		arg.Apply(group)
+		return services.ValidateMembers(group, arg.Requirements())
+	}
+
+	t.Run("Should allow changes that every member still meets", func(t *testing.T) {
+		duelists, voiceChat := 3, true
+		assert.NoError(t, validate(services.UpdateGroupParams{Duelists: &duelists, VoiceChat: &voiceChat}))
+	})
+	t.Run("Should refuse shrinking a role below the members seated in it", func(t *testing.T) {
+		duelists := 1
+		assert.Equal(t, []int{2, 3}, conflictingPlayers(t, validate(services.UpdateGroupParams{Duelists: &duelists})))
+	})
+	t.Run("Should refuse removing a role that members are seated in", func(t *testing.T) {
+		vanguards := 0
+		assert.Equal(t, []int{1}, conflictingPlayers(t, validate(services.UpdateGroupParams{Vanguards: &vanguards})))
+	})
+	t.Run("Should allow dropping the role queue", func(t *testing.T) {
+		none := 0
+		assert.NoError(t, validate(services.UpdateGroupParams{Vanguards: &none, Duelists: &none, Strategists: &none}))
+	})
+	t.Run("Should refuse requiring a mic that members don't have", func(t *testing.T) {
+		mic := true
+		assert.Equal(t, []int{2, 3}, conflictingPlayers(t, validate(services.UpdateGroupParams{Mic: &mic})))
+	})
+	t.Run("Should refuse a platform that members aren't on", func(t *testing.T) {
+		platform := "co"
+		assert.Equal(t, []int{1, 2, 3}, conflictingPlayers(t, validate(services.UpdateGroupParams{Platform: &platform})))
+	})
+	t.Run("Should refuse a gamemode that the members' ranks can't group in", func(t *testing.T) {
+		gamemode := "competitive"
+		assert.Equal(t, []int{1, 2, 3}, conflictingPlayers(t, validate(services.UpdateGroupParams{Gamemode: &gamemode})))
+	})
+	t.Run("Should ignore requirements that didn't change", func(t *testing.T) {
+		group := memberedGroup()
+		group.Gamemode = "competitive"
+		open := false
+		arg := services.UpdateGroupParams{Open: &open}
+		arg.Apply(group)
+		assert.NoError(t, services.ValidateMembers(group, arg.Requirements()))
+	})
+}
+
+func TestValidateCapacity(t *testing.T) {
+	validate := func(arg services.UpdateGroupParams) error {
+		group := memberedGroup()
+		arg.Apply(group)
+		return services.ValidateCapacity(group)
+	}
+
+	t.Run("Should allow a role queue that fits into a team", func(t *testing.T) {
+		duelists := 1
+		assert.NoError(t, validate(services.UpdateGroupParams{Duelists: &duelists}))
+	})
+	t.Run("Should refuse a role queue with more slots than a team has", func(t *testing.T) {
+		duelists := 3
+		assert.Error(t, validate(services.UpdateGroupParams{Duelists: &duelists}))
+	})
+	t.Run("Should refuse a role queue with fewer slots than there are members", func(t *testing.T) {
+		none := 0
+		vanguards := 1
+		assert.Error(t, validate(services.UpdateGroupParams{Vanguards: &vanguards, Duelists: &none, Strategists: &none}))
+	})
+}
+
+func TestOpenSlots(t *testing.T) {
+	t.Run("Should count the slots left in the role queue", func(t *testing.T) {
+		assert.Equal(t, 3, services.OpenSlots(*memberedGroup()))
+	})
+	t.Run("Should fall back to the gamemode's team size without a role queue", func(t *testing.T) {
+		group := memberedGroup()
+		group.RoleQueue = &repository.RoleQueue{}
+		assert.Equal(t, 3, services.OpenSlots(*group))
+	})
+	t.Run("Should cap role queues that are larger than a team", func(t *testing.T) {
+		group := memberedGroup()
+		group.RoleQueue = &repository.RoleQueue{Vanguards: 6, Duelists: 6, Strategists: 6}
+		assert.Equal(t, 3, services.OpenSlots(*group))
+	})
+	t.Run("Should mark the group as full once no slots are left", func(t *testing.T) {
+		group := memberedGroup()
+		group.RoleQueue = &repository.RoleQueue{Vanguards: 1, Duelists: 2}
+		group.SetSlots()
+		assert.Equal(t, 0, group.SlotsRemaining)
+		assert.True(t, group.Full)
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/jcserv/rivalslfg/internal/repository"
 )
@@ -10,9 +11,71 @@ type IGroup interface {
 	CreateGroup(ctx context.Context, arg repository.CreateGroupParams) (repository.CreateGroupRow, error)
 	GetGroups(ctx context.Context, arg repository.GetGroupsParams) ([]repository.GroupWithPlayers, int32, error)
 	GetGroupByID(ctx context.Context, id string, isGroupOwner bool) (*repository.GroupWithPlayers, error)
+	GetEligibility(ctx context.Context, groupID string, player repository.JoinGroupParams) (*Eligibility, error)
+	UpdateGroup(ctx context.Context, arg UpdateGroupParams) (*repository.GroupWithPlayers, error)
+	GetPasscode(ctx context.Context, groupID string, ownerID int32) (string, error)
+	RotatePasscode(ctx context.Context, groupID string, ownerID int32, revokeInvites bool) (string, error)
+	CreateInvite(ctx context.Context, arg CreateInviteParams) (*InviteDTO, error)
+	GetInvites(ctx context.Context, groupID string, ownerID int32) ([]InviteDTO, error)
+	RevokeInvite(ctx context.Context, groupID string, inviteID, ownerID int32) error
+	GetBans(ctx context.Context, groupID string, ownerID int32) ([]BanDTO, error)
+	LiftBan(ctx context.Context, groupID string, ownerID, playerID int32) error
+	RequestToJoin(ctx context.Context, arg repository.JoinGroupParams) (*JoinRequestDTO, error)
+	GetJoinRequests(ctx context.Context, groupID string) ([]JoinRequestDTO, error)
+	GetJoinRequest(ctx context.Context, groupID string, requestID int32) (*JoinRequestDTO, error)
+	CancelJoinRequest(ctx context.Context, groupID string, requestID, playerID int32) error
+	ResolveJoinRequest(ctx context.Context, groupID string, requestID, ownerID int32, approve bool) (*JoinRequestDTO, error)
+	JoinWaitlist(ctx context.Context, arg JoinWaitlistParams) (*WaitlistEntryDTO, error)
+	GetWaitlist(ctx context.Context, groupID string) ([]WaitlistEntryDTO, error)
+	LeaveWaitlist(ctx context.Context, groupID string, playerID int32) error
+	AcceptWaitlistOffer(ctx context.Context, groupID string, playerID int32) (*WaitlistEntryDTO, error)
+	OfferOpenSlots(ctx context.Context, groupID string) error
+	GetLapsedOfferGroups(ctx context.Context) ([]string, error)
+	Rsvp(ctx context.Context, player repository.JoinGroupParams) (*RsvpDTO, error)
+	GetRsvps(ctx context.Context, groupID string) ([]RsvpDTO, error)
+	CancelRsvp(ctx context.Context, groupID string, playerID int32) error
+	GetDueGroups(ctx context.Context) ([]string, error)
+	StartGroup(ctx context.Context, groupID string) error
+	PromoteMember(ctx context.Context, groupID string, ownerID, playerID int32) error
+	UpdateMember(ctx context.Context, arg UpdateMemberParams) (*repository.GroupWithPlayers, error)
+	ProposeMerge(ctx context.Context, arg ProposeMergeParams) (*MergeDTO, error)
+	GetMergeProposals(ctx context.Context, groupID string) ([]MergeDTO, error)
+	ResolveMerge(ctx context.Context, groupID string, mergeID, ownerID int32, accept bool) (*MergeDTO, error)
+	DeleteGroup(ctx context.Context, groupID string, ownerID int32) error
+	DeleteCommunityGroup(ctx context.Context, communityID int32, groupID string, moderatorID int32) error
+	RecordActivity(ctx context.Context, groupID string) error
+	GetIdleGroups(ctx context.Context, idleSince time.Time) ([]repository.GetIdleGroupsRow, error)
+	DeleteIdleGroup(ctx context.Context, groupID string, idleSince time.Time) (bool, error)
 }
 
 type IPlayer interface {
 	JoinGroup(ctx context.Context, arg repository.JoinGroupParams) (int32, error)
 	RemovePlayer(ctx context.Context, arg repository.RemovePlayerParams) (string, error)
+	GetPlayerGroup(ctx context.Context, playerID int32) (string, error)
+	UpsertPlayer(ctx context.Context, arg repository.UpsertPlayerParams) (int32, error)
+}
+
+type IParty interface {
+	CreateParty(ctx context.Context, player repository.JoinGroupParams) (string, int32, error)
+	JoinParty(ctx context.Context, partyID string, player repository.JoinGroupParams) (int32, error)
+	LeaveParty(ctx context.Context, partyID string, playerID int32) error
+	GetParty(ctx context.Context, partyID string) (*PartyDTO, error)
+	JoinGroup(ctx context.Context, arg JoinGroupAsPartyParams) ([]int32, error)
+}
+
+type ICommunity interface {
+	CreateCommunity(ctx context.Context, arg CreateCommunityParams) (*CommunityDTO, error)
+	GetCommunities(ctx context.Context) ([]CommunityDTO, error)
+	GetCommunity(ctx context.Context, id int32) (*CommunityDTO, error)
+	UpdateCommunity(ctx context.Context, arg UpdateCommunityParams) (*CommunityDTO, error)
+	DeleteCommunity(ctx context.Context, id, moderatorID int32) error
+	AddModerator(ctx context.Context, id, moderatorID, playerID int32) error
+	RemoveModerator(ctx context.Context, id, moderatorID, playerID int32) error
+}
+
+type IMatcher interface {
+	Enqueue(ctx context.Context, arg repository.JoinGroupParams, relaxation RelaxationSchedule) (*QueueEntry, error)
+	EnqueueParty(ctx context.Context, partyID string, arg repository.JoinGroupParams, relaxation RelaxationSchedule) (*QueueEntry, error)
+	Dequeue(ctx context.Context, playerID int32) error
+	Status(ctx context.Context, playerID int32) (*QueueStatus, error)
 }
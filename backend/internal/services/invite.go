@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+)
+
+const (
+	// DefaultInviteTTL is how long invites last when the owner doesn't say.
+	DefaultInviteTTL = 24 * time.Hour
+	// MaxInviteTTL is the longest that an invite can last.
+	MaxInviteTTL = 7 * 24 * time.Hour
+	// MaxInviteUses is the most players that can join with a single invite.
+	MaxInviteUses = 100
+)
+
+// CreateInviteParams describe an invite to a group.
+type CreateInviteParams struct {
+	GroupID   string
+	CreatedBy int32
+	MaxUses   int
+	ExpiresIn time.Duration
+}
+
+// InviteRedemption is a player that joined the group with an invite.
+type InviteRedemption struct {
+	PlayerID   int32     `json:"playerId"`
+	Name       string    `json:"name"`
+	RedeemedAt time.Time `json:"redeemedAt"`
+}
+
+// InviteDTO is an invite to a group, along with who joined with it.
+type InviteDTO struct {
+	ID          int32              `json:"id"`
+	Token       string             `json:"token"`
+	CreatedBy   int32              `json:"createdBy"`
+	MaxUses     int32              `json:"maxUses"`
+	Uses        int32              `json:"uses"`
+	ExpiresAt   time.Time          `json:"expiresAt"`
+	RevokedAt   *time.Time         `json:"revokedAt,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	Redemptions []InviteRedemption `json:"redemptions"`
+}
+
+func toInviteDTO(invite repository.Groupinvite) InviteDTO {
+	dto := InviteDTO{
+		ID:          invite.ID,
+		Token:       invite.Token,
+		CreatedBy:   invite.CreatedBy,
+		MaxUses:     invite.MaxUses,
+		Uses:        invite.Uses,
+		ExpiresAt:   invite.ExpiresAt,
+		CreatedAt:   invite.CreatedAt,
+		Redemptions: []InviteRedemption{},
+	}
+	if invite.RevokedAt.Valid {
+		dto.RevokedAt = &invite.RevokedAt.Time
+	}
+	return dto
+}
+
+// CreateInvite mints an invite that lets players join the group without the
+// passcode, until it's used up, expires or is revoked. Only the owner can create them.
+func (s *Group) CreateInvite(ctx context.Context, arg CreateInviteParams) (*InviteDTO, error) {
+	token, err := generateInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var invite repository.Groupinvite
+	err = s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := lockOwnedGroup(ctx, q, arg.GroupID, arg.CreatedBy); err != nil {
+			return err
+		}
+
+		var err error
+		invite, err = q.CreateGroupInvite(ctx, repository.CreateGroupInviteParams{
+			GroupID:   arg.GroupID,
+			Token:     token,
+			CreatedBy: arg.CreatedBy,
+			MaxUses:   int32(arg.MaxUses),
+			ExpiresAt: time.Now().Add(arg.ExpiresIn),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dto := toInviteDTO(invite)
+	return &dto, nil
+}
+
+// GetInvites returns every invite to the group, newest first, including the
+// ones that can no longer be redeemed. Only the owner can see them.
+func (s *Group) GetInvites(ctx context.Context, groupID string, ownerID int32) ([]InviteDTO, error) {
+	var invites []repository.Groupinvite
+	var redemptions []repository.GetGroupInviteRedemptionsRow
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := lockOwnedGroup(ctx, q, groupID, ownerID); err != nil {
+			return err
+		}
+
+		var err error
+		invites, err = q.GetGroupInvites(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		redemptions, err = q.GetGroupInviteRedemptions(ctx, groupID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]InviteDTO, 0, len(invites))
+	byID := make(map[int32]int, len(invites))
+	for _, invite := range invites {
+		byID[invite.ID] = len(result)
+		result = append(result, toInviteDTO(invite))
+	}
+	for _, redemption := range redemptions {
+		i, ok := byID[redemption.InviteID]
+		if !ok {
+			continue
+		}
+		result[i].Redemptions = append(result[i].Redemptions, InviteRedemption{
+			PlayerID:   redemption.PlayerID,
+			Name:       redemption.Name,
+			RedeemedAt: redemption.RedeemedAt,
+		})
+	}
+	return result, nil
+}
+
+// RevokeInvite stops the invite from being redeemed. Players that already
+// joined with it stay in the group. Only the owner can revoke it.
+func (s *Group) RevokeInvite(ctx context.Context, groupID string, inviteID, ownerID int32) error {
+	return s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := lockOwnedGroup(ctx, q, groupID, ownerID); err != nil {
+			return err
+		}
+
+		revoked, err := q.RevokeGroupInvite(ctx, repository.RevokeGroupInviteParams{
+			ID:      inviteID,
+			GroupID: groupID,
+		})
+		if err != nil {
+			return err
+		}
+		if revoked == 0 {
+			return NewError(http.StatusNotFound, "Invite not found.", nil)
+		}
+		return nil
+	})
+}
+
+// generateInviteToken returns a random, URL safe token that's infeasible to guess.
+func generateInviteToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/store"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+const (
+	// MatchInterval is how often players who are still queued are retried.
+	MatchInterval = 5 * time.Second
+
+	groupLockTTL   = 10 * time.Second
+	matchRetention = 10 * time.Minute
+	maxCandidates  = 50
+)
+
+type MatchResult struct {
+	GroupID  string `json:"groupId"`
+	PlayerID int32  `json:"playerId"`
+	// Whether the player leads the group, which is the case for groups formed from the queue.
+	Leader bool `json:"leader"`
+	// Set if the player was placed along with their party.
+	PartyID string `json:"partyId,omitempty"`
+}
+
+type QueueEntry struct {
+	PlayerID int32                      `json:"playerId"`
+	Player   repository.JoinGroupParams `json:"-"`
+	QueuedAt time.Time                  `json:"queuedAt"`
+
+	// Parties are queued under their leader, and only ever placed as a whole.
+	PartyID   string `json:"partyId,omitempty"`
+	PartySize int    `json:"partySize,omitempty"`
+
+	// Relaxation loosens the player's constraints the longer they wait.
+	Relaxation RelaxationSchedule `json:"-"`
+	// The constraints the player is currently matched with, and when they'll relax next.
+	Constraints      Constraints `json:"constraints"`
+	NextRelaxationAt *time.Time  `json:"nextRelaxationAt,omitempty"`
+
+	// Set once the player has been placed into a group, until they collect it.
+	Match     *MatchResult `json:"match,omitempty"`
+	MatchedAt time.Time    `json:"-"`
+
+	matching bool
+}
+
+// snapshot copies the entry, with its constraints as of now.
+func (e *QueueEntry) snapshot(now time.Time) *QueueEntry {
+	entry := *e
+	constraints, untilNext := e.Relaxation.At(now.Sub(e.QueuedAt))
+	entry.Constraints = constraints
+	entry.NextRelaxationAt = nil
+	if untilNext > 0 {
+		next := now.Add(untilNext)
+		entry.NextRelaxationAt = &next
+	}
+	return &entry
+}
+
+// MatcherGroups is the part of the group service that the matcher finds and forms groups with.
+type MatcherGroups interface {
+	GetGroups(ctx context.Context, arg repository.GetGroupsParams) ([]repository.GroupWithPlayers, int32, error)
+	CreateGroup(ctx context.Context, arg repository.CreateGroupParams) (repository.CreateGroupRow, error)
+}
+
+// MatcherPlayers is the part of the player service that the matcher seats players with.
+type MatcherPlayers interface {
+	JoinGroup(ctx context.Context, arg repository.JoinGroupParams) (int32, error)
+	UpsertPlayer(ctx context.Context, arg repository.UpsertPlayerParams) (int32, error)
+}
+
+// Matcher places queued players into open groups. Candidate groups are locked
+// through the store before joining, so that concurrent matches don't race each
+// other into the same slot.
+type Matcher struct {
+	sync.Mutex
+	groups  MatcherGroups
+	players MatcherPlayers
+	parties IParty
+	store   store.Store
+	queue   map[int32]*QueueEntry
+	now     func() time.Time
+
+	throughput *Throughput
+	notifier   QueueNotifier
+}
+
+func NewMatcher(groups MatcherGroups, players MatcherPlayers, parties IParty, store store.Store) *Matcher {
+	return &Matcher{
+		groups:  groups,
+		players: players,
+		parties: parties,
+		store:   store,
+		queue:   make(map[int32]*QueueEntry),
+		now:     time.Now,
+
+		throughput: NewThroughput(ThroughputWindow),
+	}
+}
+
+// SetClock replaces the clock used to timestamp queue entries, so that the matcher can run on simulated time.
+func (m *Matcher) SetClock(now func() time.Time) {
+	m.Lock()
+	defer m.Unlock()
+	m.now = now
+}
+
+// Enqueue adds the player to the queue and attempts to match them right away.
+// Enqueueing again while queued refreshes the player's info, and returns their
+// match if they were placed into a group in the meantime. Players without a
+// relaxation schedule get DefaultRelaxationSchedule.
+func (m *Matcher) Enqueue(ctx context.Context, arg repository.JoinGroupParams, relaxation RelaxationSchedule) (*QueueEntry, error) {
+	if entry := m.collect(arg.PlayerID); entry != nil {
+		return entry, nil
+	}
+
+	playerID, err := m.players.UpsertPlayer(ctx, toUpsertPlayerParams(arg))
+	if err != nil {
+		return nil, err
+	}
+	arg.PlayerID = playerID
+	return m.enqueue(ctx, arg, nil, relaxation)
+}
+
+// EnqueueParty queues the party under its leader, who is the only one that
+// can queue it. Enqueueing follows the same rules as for a single player, but
+// the party is only placed into groups that have room for all of its members.
+func (m *Matcher) EnqueueParty(ctx context.Context, partyID string, arg repository.JoinGroupParams, relaxation RelaxationSchedule) (*QueueEntry, error) {
+	if entry := m.collect(arg.PlayerID); entry != nil {
+		return entry, nil
+	}
+
+	party, err := m.parties.GetParty(ctx, partyID)
+	if err != nil {
+		return nil, err
+	}
+	if party == nil {
+		return nil, NewError(http.StatusNotFound, "Party not found.", nil)
+	}
+	if party.LeaderID != arg.PlayerID {
+		return nil, NewError(http.StatusForbidden, "Only the party leader can queue the party.", nil)
+	}
+	for _, member := range party.Members {
+		if member.GroupID != "" {
+			return nil, NewError(http.StatusBadRequest, fmt.Sprintf("%s is already in a group.", member.Name), nil)
+		}
+	}
+
+	if _, err := m.players.UpsertPlayer(ctx, toUpsertPlayerParams(arg)); err != nil {
+		return nil, err
+	}
+	return m.enqueue(ctx, arg, party, relaxation)
+}
+
+func (m *Matcher) enqueue(ctx context.Context, arg repository.JoinGroupParams, party *PartyDTO, relaxation RelaxationSchedule) (*QueueEntry, error) {
+	if len(relaxation) == 0 {
+		relaxation = DefaultRelaxationSchedule
+	}
+
+	playerID := arg.PlayerID
+	arg.GroupID = ""
+	arg.Passcode = ""
+
+	m.Lock()
+	entry, ok := m.queue[playerID]
+	if !ok {
+		entry = &QueueEntry{
+			PlayerID: playerID,
+			QueuedAt: m.now(),
+		}
+		m.queue[playerID] = entry
+	}
+	entry.Player = arg
+	entry.Relaxation = relaxation
+	entry.PartyID, entry.PartySize = "", 0
+	if party != nil {
+		entry.PartyID, entry.PartySize = party.ID, party.Size()
+	}
+	if entry.matching {
+		// The background loop is already trying to place this player
+		defer m.Unlock()
+		return entry.snapshot(m.now()), nil
+	}
+	entry.matching = true
+	queued := entry.snapshot(m.now())
+	m.Unlock()
+
+	result, err := m.matchEntry(ctx, queued)
+	m.finish(playerID, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result != nil {
+		return m.collect(playerID), nil
+	}
+	return m.get(playerID), nil
+}
+
+func (m *Matcher) Dequeue(ctx context.Context, playerID int32) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, ok := m.queue[playerID]; !ok {
+		return NewError(http.StatusNotFound, "Player is not queued.", nil)
+	}
+	delete(m.queue, playerID)
+	return nil
+}
+
+// Run retries queued players every MatchInterval until the context is cancelled.
+func (m *Matcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(MatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.MatchQueued(ctx)
+		}
+	}
+}
+
+// MatchQueued makes a single matching pass over every queued player, oldest
+// first. Players that could not be placed into an existing group are then
+// formed into new groups where possible. Queued players are notified of their
+// status afterwards.
+func (m *Matcher) MatchQueued(ctx context.Context) {
+	for _, entry := range m.pending() {
+		result, err := m.matchEntry(ctx, entry)
+		if err != nil {
+			log.Error(ctx, fmt.Sprintf("unable to match player %d: %v", entry.PlayerID, err))
+		}
+		m.finish(entry.PlayerID, result)
+	}
+	m.FormGroups(ctx)
+	m.purge()
+	m.notify(ctx)
+}
+
+// matchEntry matches the entry's party if it has one, and its player otherwise.
+func (m *Matcher) matchEntry(ctx context.Context, entry *QueueEntry) (*MatchResult, error) {
+	if entry.PartyID != "" {
+		return m.matchParty(ctx, entry)
+	}
+	return m.match(ctx, entry)
+}
+
+// match finds the best open group for the player that satisfies their current
+// constraints and joins it, returning nil if no group could be joined.
+func (m *Matcher) match(ctx context.Context, entry *QueueEntry) (*MatchResult, error) {
+	player := withConstraints(entry.Player, entry.Constraints)
+	groups, _, err := m.groups.GetGroups(ctx, candidateParams(player))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range rankCandidates(groups, entry.Constraints) {
+		playerID, joined, err := m.tryJoin(ctx, group.ID, player)
+		if err != nil {
+			return nil, err
+		}
+		if joined {
+			return &MatchResult{
+				GroupID:  group.ID,
+				PlayerID: playerID,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// matchParty finds the best open group with room for the whole party and
+// joins it. Candidates are searched for with the leader's info, since every
+// member is checked against the group's requirements when joining anyway.
+func (m *Matcher) matchParty(ctx context.Context, entry *QueueEntry) (*MatchResult, error) {
+	groups, _, err := m.groups.GetGroups(ctx, candidateParams(withConstraints(entry.Player, entry.Constraints)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range rankCandidates(groups, entry.Constraints) {
+		if OpenSlots(group) < entry.PartySize {
+			continue
+		}
+		joined, err := m.tryJoinParty(ctx, group.ID, entry)
+		if err != nil {
+			return nil, err
+		}
+		if joined {
+			return &MatchResult{
+				GroupID:  group.ID,
+				PlayerID: entry.PlayerID,
+				PartyID:  entry.PartyID,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// tryJoin joins the group while holding its lock. Groups that are locked, or
+// that the player turns out to be ineligible for, are skipped.
+func (m *Matcher) tryJoin(ctx context.Context, groupID string, player repository.JoinGroupParams) (int32, bool, error) {
+	release, locked, err := m.lockGroup(ctx, groupID, player.PlayerID)
+	if err != nil || !locked {
+		return 0, false, err
+	}
+	defer release()
+
+	player.GroupID = groupID
+	playerID, err := m.players.JoinGroup(ctx, player)
+	if err != nil {
+		if serviceErr, ok := err.(Error); ok && serviceErr.Code() < http.StatusInternalServerError {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return playerID, true, nil
+}
+
+// tryJoinParty is tryJoin for a whole party.
+func (m *Matcher) tryJoinParty(ctx context.Context, groupID string, entry *QueueEntry) (bool, error) {
+	release, locked, err := m.lockGroup(ctx, groupID, entry.PlayerID)
+	if err != nil || !locked {
+		return false, err
+	}
+	defer release()
+
+	_, err = m.parties.JoinGroup(ctx, JoinGroupAsPartyParams{
+		GroupID:  groupID,
+		PartyID:  entry.PartyID,
+		LeaderID: entry.PlayerID,
+	})
+	if err != nil {
+		if serviceErr, ok := err.(Error); ok && serviceErr.Code() < http.StatusInternalServerError {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// lockGroup takes the group's lock on behalf of the player, and returns a
+// function that releases it. Nothing is returned if the group is already locked.
+// The lock holds a token unique to this attempt, so that a lock which expired
+// and was taken by someone else isn't released along with it.
+func (m *Matcher) lockGroup(ctx context.Context, groupID string, playerID int32) (func(), bool, error) {
+	key := groupLockKey(groupID)
+	token := fmt.Sprintf("%d:%s", playerID, uuid.NewString())
+	locked, err := m.store.SetNX(ctx, key, token, groupLockTTL)
+	if err != nil || !locked {
+		return nil, false, err
+	}
+	return func() {
+		if _, err := m.store.DeleteIfValue(ctx, key, token); err != nil {
+			log.Error(ctx, fmt.Sprintf("unable to release lock on group %s: %v", groupID, err))
+		}
+	}, true, nil
+}
+
+// pending marks every unmatched entry as being matched, and returns them oldest first.
+func (m *Matcher) pending() []*QueueEntry {
+	m.Lock()
+	defer m.Unlock()
+
+	entries := make([]*QueueEntry, 0, len(m.queue))
+	for _, entry := range m.queue {
+		if entry.Match == nil && !entry.matching {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].QueuedAt.Before(entries[j].QueuedAt)
+	})
+
+	snapshots := make([]*QueueEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.matching = true
+		snapshots = append(snapshots, entry.snapshot(m.now()))
+	}
+	return snapshots
+}
+
+func (m *Matcher) finish(playerID int32, result *MatchResult) {
+	m.Lock()
+	defer m.Unlock()
+
+	entry, ok := m.queue[playerID]
+	if !ok {
+		return
+	}
+	entry.matching = false
+	if result != nil {
+		entry.Match = result
+		entry.MatchedAt = m.now()
+		m.throughput.Record(throughputBucket(entry.Player), entry.MatchedAt)
+	}
+}
+
+// collect removes and returns the player's entry if they have been matched.
+func (m *Matcher) collect(playerID int32) *QueueEntry {
+	m.Lock()
+	defer m.Unlock()
+
+	entry, ok := m.queue[playerID]
+	if !ok || entry.Match == nil {
+		return nil
+	}
+	delete(m.queue, playerID)
+	return entry.snapshot(m.now())
+}
+
+func (m *Matcher) get(playerID int32) *QueueEntry {
+	m.Lock()
+	defer m.Unlock()
+
+	entry, ok := m.queue[playerID]
+	if !ok {
+		return nil
+	}
+	return entry.snapshot(m.now())
+}
+
+// purge drops matches that were never collected.
+func (m *Matcher) purge() {
+	m.Lock()
+	defer m.Unlock()
+
+	for playerID, entry := range m.queue {
+		if entry.Match != nil && m.now().Sub(entry.MatchedAt) > matchRetention {
+			delete(m.queue, playerID)
+		}
+	}
+}
+
+// rankCandidates orders groups with open slots that the constraints allow, so that those closest to completion come first.
+func rankCandidates(groups []repository.GroupWithPlayers, constraints Constraints) []repository.GroupWithPlayers {
+	candidates := make([]repository.GroupWithPlayers, 0, len(groups))
+	for _, group := range groups {
+		if OpenSlots(group) > 0 && constraints.Allows(group) {
+			candidates = append(candidates, group)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		si, sj := OpenSlots(candidates[i]), OpenSlots(candidates[j])
+		if si != sj {
+			return si < sj
+		}
+		return candidates[i].LastActiveAt.After(candidates[j].LastActiveAt)
+	})
+	return candidates
+}
+
+// OpenSlots returns how many more players fit into the group, which is negative if it has more members than fit.
+func OpenSlots(group repository.GroupWithPlayers) int {
+	return group.Capacity() - group.Size
+}
+
+// candidateParams searches for open groups that the player meets the requirements of.
+func candidateParams(player repository.JoinGroupParams) repository.GetGroupsParams {
+	role, _ := player.Role.(string)
+	rankVal, _ := player.RankVal.(int32)
+	return repository.GetGroupsParams{
+		RegionFilter:   player.Region,
+		GamemodeFilter: player.Gamemode,
+		OpenFilter:     "true",
+		SizeSort:       "desc",
+		Limit:          maxCandidates,
+		Platform:       &player.Platform,
+		Role:           &role,
+		Roles:          rolePreferences(player),
+		RankVal:        &rankVal,
+		VoiceChat:      &player.VoiceChat,
+		Mic:            &player.Mic,
+		AllowedRanks:   map[string][]int32{player.Gamemode: player.AllowedRanks},
+	}
+}
+
+// withConstraints limits the ranks that the player can group with to those their constraints allow.
+func withConstraints(player repository.JoinGroupParams, constraints Constraints) repository.JoinGroupParams {
+	rankVal, _ := player.RankVal.(int32)
+	player.AllowedRanks = constraints.RankPolicy(player.Gamemode).AllowedRanks(int(rankVal))
+	return player
+}
+
+func groupLockKey(groupID string) string {
+	return fmt.Sprintf("lock:group:%s", groupID)
+}
+
+func toUpsertPlayerParams(arg repository.JoinGroupParams) repository.UpsertPlayerParams {
+	role, _ := arg.Role.(string)
+	rankVal, _ := arg.RankVal.(int32)
+	return repository.UpsertPlayerParams{
+		ID:          arg.PlayerID,
+		Name:        arg.Name,
+		Platform:    arg.Platform,
+		Role:        role,
+		Rank:        rankVal,
+		Characters:  arg.Characters,
+		VoiceChat:   arg.VoiceChat,
+		Mic:         arg.Mic,
+		Vanguards:   arg.Vanguards,
+		Duelists:    arg.Duelists,
+		Strategists: arg.Strategists,
+	}
+}
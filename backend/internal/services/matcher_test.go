@@ -0,0 +1,583 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/store"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func queuedPlayer() repository.JoinGroupParams {
+	return repository.JoinGroupParams{
+		Name:       "imphungky",
+		Gamemode:   "competitive",
+		Region:     "na",
+		Platform:   "pc",
+		Role:       "vanguard",
+		RankVal:    int32(40),
+		Characters: []string{"Doctor Strange"},
+		VoiceChat:  true,
+		Mic:        true,
+	}
+}
+
+func groupOfSize(id string, size int) repository.GroupWithPlayers {
+	return repository.GroupWithPlayers{
+		GroupDTO: repository.GroupDTO{
+			ID:        id,
+			Region:    "na",
+			Gamemode:  "competitive",
+			Open:      true,
+			RoleQueue: &repository.RoleQueue{Vanguards: 2, Duelists: 2, Strategists: 2},
+		},
+		Size: size,
+	}
+}
+
+func joining(groupID string) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		return x.(repository.JoinGroupParams).GroupID == groupID
+	})
+}
+
+func TestMatcher_Enqueue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should join the group closest to completion", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+			groupOfSize("AAAA", 1),
+			groupOfSize("BBBB", 5),
+			groupOfSize("CCCC", 6),
+		}, int32(3), nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("BBBB")).Return(int32(7), nil)
+
+		entry, err := m.Enqueue(ctx, queuedPlayer(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, &services.MatchResult{GroupID: "BBBB", PlayerID: 7}, entry.Match)
+
+		// Collecting the match removes the player from the queue
+		assert.Error(t, m.Dequeue(ctx, 7))
+	})
+
+	t.Run("Should skip groups that are locked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		s := store.NewMemoryStore()
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), s)
+
+		locked, err := s.SetNX(ctx, "lock:group:BBBB", int32(8), 0)
+		assert.NoError(t, err)
+		assert.True(t, locked)
+
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+			groupOfSize("AAAA", 1),
+			groupOfSize("BBBB", 5),
+		}, int32(2), nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("AAAA")).Return(int32(7), nil)
+
+		entry, err := m.Enqueue(ctx, queuedPlayer(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", entry.Match.GroupID)
+
+		// The lock on the joined group is released afterwards
+		locked, err = s.SetNX(ctx, "lock:group:AAAA", int32(8), 0)
+		assert.NoError(t, err)
+		assert.True(t, locked)
+	})
+
+	t.Run("Should leave the lock alone if it expired and was taken by someone else", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		s := store.NewMemoryStore()
+		now := time.Now()
+		s.SetClock(func() time.Time { return now })
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), s)
+
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+			groupOfSize("AAAA", 1),
+		}, int32(1), nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("AAAA")).DoAndReturn(func(ctx context.Context, arg repository.JoinGroupParams) (int32, error) {
+			// The join outlasts the lock, which is then taken by another matcher
+			now = now.Add(time.Minute)
+			locked, err := s.SetNX(ctx, "lock:group:AAAA", "other", 0)
+			assert.NoError(t, err)
+			assert.True(t, locked)
+			return 7, nil
+		})
+
+		entry, err := m.Enqueue(ctx, queuedPlayer(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", entry.Match.GroupID)
+
+		value, err := s.Get(ctx, "lock:group:AAAA")
+		assert.NoError(t, err)
+		assert.Equal(t, "other", value)
+	})
+
+	t.Run("Should move on to the next group if requirements are not met", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+			groupOfSize("AAAA", 1),
+			groupOfSize("BBBB", 5),
+		}, int32(2), nil)
+		gomock.InOrder(
+			mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("BBBB")).Return(int32(0), services.NewError(http.StatusBadRequest, "Group requirements not met.", nil)),
+			mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("AAAA")).Return(int32(7), nil),
+		)
+
+		entry, err := m.Enqueue(ctx, queuedPlayer(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", entry.Match.GroupID)
+	})
+
+	t.Run("Should move on to the next group if its open slot is held for a waitlisted player", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+			groupOfSize("AAAA", 1),
+			groupOfSize("BBBB", 5),
+		}, int32(2), nil)
+		gomock.InOrder(
+			mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("BBBB")).Return(int32(0), services.NewError(http.StatusBadRequest, "Group is full.", nil)),
+			mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("AAAA")).Return(int32(7), nil),
+		)
+
+		entry, err := m.Enqueue(ctx, queuedPlayer(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", entry.Match.GroupID)
+	})
+
+	t.Run("Should keep the player queued until a group opens up", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		gomock.InOrder(
+			mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil),
+			mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+				groupOfSize("AAAA", 3),
+			}, int32(1), nil),
+		)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("AAAA")).Return(int32(7), nil)
+
+		entry, err := m.Enqueue(ctx, queuedPlayer(), nil)
+		assert.NoError(t, err)
+		assert.Nil(t, entry.Match)
+		assert.Equal(t, int32(7), entry.PlayerID)
+
+		m.MatchQueued(ctx)
+
+		player := queuedPlayer()
+		player.PlayerID = 7
+		entry, err = m.Enqueue(ctx, player, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, &services.MatchResult{GroupID: "AAAA", PlayerID: 7}, entry.Match)
+	})
+}
+
+func TestMatcher_Dequeue(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+	mockPlayerService := mocks.NewMockIPlayer(ctrl)
+	m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+	t.Run("Should return 404 if player is not queued", func(t *testing.T) {
+		err := m.Dequeue(ctx, 1)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, err.(services.Error).Code())
+	})
+
+	t.Run("Should remove a queued player", func(t *testing.T) {
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(1), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil)
+
+		_, err := m.Enqueue(ctx, queuedPlayer(), nil)
+		assert.NoError(t, err)
+		assert.NoError(t, m.Dequeue(ctx, 1))
+		assert.Error(t, m.Dequeue(ctx, 1))
+	})
+}
+
+func TestMatcher_FormGroups(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should form a group from compatible queued players", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil).AnyTimes()
+
+		queue := func(id int32, role string, rankVal int32) {
+			player := queuedPlayer()
+			player.Role = role
+			player.RankVal = rankVal
+			mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(id, nil)
+			_, err := m.Enqueue(ctx, player, nil)
+			assert.NoError(t, err)
+		}
+		queue(1, "vanguard", 40)
+		queue(2, "duelist", 42)
+		queue(3, "vanguard", 80) // Too far from the others' rank
+		queue(4, "strategist", 32)
+
+		mockGroupService.EXPECT().CreateGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.CreateGroupParams)
+			return arg.PlayerID == int32(1) && arg.Open && arg.Vanguards == 2
+		})).Return(repository.CreateGroupRow{GroupID: "AAAA", PlayerID: 1}, nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.JoinGroupParams)
+			return arg.GroupID == "AAAA" && arg.PlayerID == 2
+		})).Return(int32(2), nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.JoinGroupParams)
+			return arg.GroupID == "AAAA" && arg.PlayerID == 4
+		})).Return(int32(4), nil)
+
+		m.MatchQueued(ctx)
+
+		collect := func(id int32) *services.QueueEntry {
+			player := queuedPlayer()
+			player.PlayerID = id
+			entry, err := m.Enqueue(ctx, player, nil)
+			assert.NoError(t, err)
+			return entry
+		}
+		assert.Equal(t, &services.MatchResult{GroupID: "AAAA", PlayerID: 1, Leader: true}, collect(1).Match)
+		assert.Equal(t, &services.MatchResult{GroupID: "AAAA", PlayerID: 2}, collect(2).Match)
+		assert.Equal(t, &services.MatchResult{GroupID: "AAAA", PlayerID: 4}, collect(4).Match)
+
+		// The player that could not be placed is still queued
+		assert.NoError(t, m.Dequeue(ctx, 3))
+	})
+
+	t.Run("Should seat flex players in their first open preferred role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil).AnyTimes()
+
+		queue := func(id int32, roles ...string) {
+			player := queuedPlayer()
+			player.Roles = roles
+			mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(id, nil)
+			_, err := m.Enqueue(ctx, player, nil)
+			assert.NoError(t, err)
+		}
+		queue(1, "duelist")
+		queue(2, "duelist")
+		queue(3, "duelist", "strategist", "vanguard")
+
+		mockGroupService.EXPECT().CreateGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.CreateGroupParams)
+			return arg.PlayerID == int32(1) && arg.Role == "duelist"
+		})).Return(repository.CreateGroupRow{GroupID: "AAAA", PlayerID: 1}, nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.JoinGroupParams)
+			return arg.PlayerID == 2 && assert.ObjectsAreEqual([]string{"duelist"}, arg.Roles)
+		})).Return(int32(2), nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.JoinGroupParams)
+			return arg.PlayerID == 3 && assert.ObjectsAreEqual([]string{"strategist"}, arg.Roles)
+		})).Return(int32(3), nil)
+
+		m.MatchQueued(ctx)
+	})
+
+	t.Run("Should not form a group for a single player", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil).AnyTimes()
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(1), nil)
+		_, err := m.Enqueue(ctx, queuedPlayer(), nil)
+		assert.NoError(t, err)
+
+		m.MatchQueued(ctx)
+		assert.NoError(t, m.Dequeue(ctx, 1))
+	})
+}
+
+func partyOf(id string, leaderID int32, roles ...string) *services.PartyDTO {
+	party := &services.PartyDTO{ID: id, LeaderID: leaderID}
+	for i, role := range roles {
+		party.Members = append(party.Members, services.PartyMember{
+			ID:       leaderID + int32(i),
+			Name:     fmt.Sprintf("player%d", leaderID+int32(i)),
+			Leader:   i == 0,
+			Platform: "pc",
+			Roles:    []string{role},
+			Rank:     "d3",
+		})
+	}
+	return party
+}
+
+func joiningAsParty(groupID string) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		return x.(services.JoinGroupAsPartyParams).GroupID == groupID
+	})
+}
+
+func TestMatcher_EnqueueParty(t *testing.T) {
+	ctx := context.Background()
+
+	leader := func() repository.JoinGroupParams {
+		player := queuedPlayer()
+		player.PlayerID = 7
+		return player
+	}
+
+	t.Run("Should join the best group with room for the whole party", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		mockPartyService := mocks.NewMockIParty(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mockPartyService, store.NewMemoryStore())
+
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(partyOf("ABCD", 7, "vanguard", "duelist", "strategist"), nil)
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+			groupOfSize("AAAA", 1),
+			groupOfSize("BBBB", 4),
+			groupOfSize("CCCC", 3),
+		}, int32(3), nil)
+		mockPartyService.EXPECT().JoinGroup(gomock.Any(), services.JoinGroupAsPartyParams{
+			GroupID:  "CCCC",
+			PartyID:  "ABCD",
+			LeaderID: 7,
+		}).Return([]int32{7, 8, 9}, nil)
+
+		entry, err := m.EnqueueParty(ctx, "ABCD", leader(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, &services.MatchResult{GroupID: "CCCC", PlayerID: 7, PartyID: "ABCD"}, entry.Match)
+	})
+
+	t.Run("Should move on to the next group if a member does not meet the requirements", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		mockPartyService := mocks.NewMockIParty(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mockPartyService, store.NewMemoryStore())
+
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(partyOf("ABCD", 7, "vanguard", "duelist"), nil)
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+			groupOfSize("AAAA", 1),
+			groupOfSize("BBBB", 4),
+		}, int32(2), nil)
+		gomock.InOrder(
+			mockPartyService.EXPECT().JoinGroup(gomock.Any(), joiningAsParty("BBBB")).Return(nil, services.NewError(http.StatusBadRequest, "player8 does not meet the group requirements.", nil)),
+			mockPartyService.EXPECT().JoinGroup(gomock.Any(), joiningAsParty("AAAA")).Return([]int32{7, 8}, nil),
+		)
+
+		entry, err := m.EnqueueParty(ctx, "ABCD", leader(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", entry.Match.GroupID)
+	})
+
+	t.Run("Should return 403 if the player does not lead the party", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockPartyService := mocks.NewMockIParty(ctrl)
+		m := services.NewMatcher(mocks.NewMockIGroup(ctrl), mocks.NewMockIPlayer(ctrl), mockPartyService, store.NewMemoryStore())
+
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(partyOf("ABCD", 6, "vanguard", "duelist"), nil)
+
+		_, err := m.EnqueueParty(ctx, "ABCD", leader(), nil)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, err.(services.Error).Code())
+	})
+
+	t.Run("Should return 404 if the party does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockPartyService := mocks.NewMockIParty(ctrl)
+		m := services.NewMatcher(mocks.NewMockIGroup(ctrl), mocks.NewMockIPlayer(ctrl), mockPartyService, store.NewMemoryStore())
+
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(nil, nil)
+
+		_, err := m.EnqueueParty(ctx, "ABCD", leader(), nil)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, err.(services.Error).Code())
+	})
+
+	t.Run("Should form a group for a party that could not be placed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		mockPartyService := mocks.NewMockIParty(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mockPartyService, store.NewMemoryStore())
+
+		party := partyOf("ABCD", 7, "vanguard", "duelist", "duelist")
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(party, nil).Times(2)
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil).AnyTimes()
+
+		entry, err := m.EnqueueParty(ctx, "ABCD", leader(), nil)
+		assert.NoError(t, err)
+		assert.Nil(t, entry.Match)
+		assert.Equal(t, 3, entry.PartySize)
+
+		mockGroupService.EXPECT().CreateGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.CreateGroupParams)
+			return arg.PlayerID == int32(7) && arg.Role == "vanguard"
+		})).Return(repository.CreateGroupRow{GroupID: "AAAA", PlayerID: 7}, nil)
+		mockPartyService.EXPECT().JoinGroup(gomock.Any(), joiningAsParty("AAAA")).Return([]int32{8, 9}, nil)
+
+		m.MatchQueued(ctx)
+
+		entry, err = m.Enqueue(ctx, leader(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, &services.MatchResult{GroupID: "AAAA", PlayerID: 7, Leader: true, PartyID: "ABCD"}, entry.Match)
+	})
+
+	t.Run("Should not form a group if the party does not fit into the role queue", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		mockPartyService := mocks.NewMockIParty(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mockPartyService, store.NewMemoryStore())
+
+		party := partyOf("ABCD", 7, "duelist", "duelist", "duelist")
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(party, nil).Times(2)
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil).AnyTimes()
+
+		player := leader()
+		player.Role = "duelist"
+		_, err := m.EnqueueParty(ctx, "ABCD", player, nil)
+		assert.NoError(t, err)
+
+		m.MatchQueued(ctx)
+		assert.NoError(t, m.Dequeue(ctx, 7))
+	})
+}
+
+func TestMatcher_Relaxation(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	allowing := func(rankVal int32, allowed bool) gomock.Matcher {
+		return gomock.Cond(func(x any) bool {
+			ranks := x.(repository.GetGroupsParams).AllowedRanks["competitive"]
+			return slices.Contains(ranks, rankVal) == allowed
+		})
+	}
+
+	t.Run("Should widen the ranks searched for the longer the player waits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+		now := start
+		m.SetClock(func() time.Time { return now })
+
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		gomock.InOrder(
+			mockGroupService.EXPECT().GetGroups(gomock.Any(), allowing(52, false)).Return([]repository.GroupWithPlayers{}, int32(0), nil),
+			mockGroupService.EXPECT().GetGroups(gomock.Any(), allowing(52, true)).Return([]repository.GroupWithPlayers{}, int32(0), nil),
+		)
+
+		entry, err := m.Enqueue(ctx, queuedPlayer(), services.RelaxationSchedule{
+			{After: 0, Constraints: services.Constraints{MaxRankSpread: 10}},
+			{After: time.Minute, Constraints: services.Constraints{MaxRankSpread: 12}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, services.Constraints{MaxRankSpread: 10}, entry.Constraints)
+		assert.Equal(t, start.Add(time.Minute), *entry.NextRelaxationAt)
+
+		now = start.Add(90 * time.Second)
+		m.MatchQueued(ctx)
+	})
+
+	t.Run("Should skip groups without voice chat if the player requires it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		withVoiceChat := groupOfSize("AAAA", 1)
+		withVoiceChat.GroupSettings = &repository.GroupSettings{VoiceChat: true}
+
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{
+			withVoiceChat,
+			groupOfSize("BBBB", 5),
+		}, int32(2), nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("AAAA")).Return(int32(7), nil)
+
+		entry, err := m.Enqueue(ctx, queuedPlayer(), services.RelaxationSchedule{
+			{After: 0, Constraints: services.Constraints{MaxRankSpread: 10, RequireVoiceChat: true}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", entry.Match.GroupID)
+	})
+
+	t.Run("Should form groups that use voice chat if anyone in them requires it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockPlayerService := mocks.NewMockIPlayer(ctrl)
+		m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil).AnyTimes()
+
+		queue := func(id int32, voiceChat bool, relaxation services.RelaxationSchedule) {
+			player := queuedPlayer()
+			player.VoiceChat = voiceChat
+			mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(id, nil)
+			_, err := m.Enqueue(ctx, player, relaxation)
+			assert.NoError(t, err)
+		}
+		queue(1, true, services.RelaxationSchedule{
+			{After: 0, Constraints: services.Constraints{MaxRankSpread: 10, RequireVoiceChat: true}},
+		})
+		queue(2, false, nil) // Can't join a group that requires voice chat
+		queue(3, true, nil)
+
+		mockGroupService.EXPECT().CreateGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.CreateGroupParams)
+			return arg.PlayerID == int32(1) && arg.GroupVoiceChat.Bool
+		})).Return(repository.CreateGroupRow{GroupID: "AAAA", PlayerID: 1}, nil)
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			return x.(repository.JoinGroupParams).PlayerID == 3
+		})).Return(int32(3), nil)
+
+		m.MatchQueued(ctx)
+		assert.NoError(t, m.Dequeue(ctx, 2))
+	})
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// MergeProposalTTL is how long a proposed merge waits for the other group's owner before it expires.
+const MergeProposalTTL = 10 * time.Minute
+
+const (
+	MergePending  = "pending"
+	MergeAccepted = "accepted"
+	MergeDeclined = "declined"
+	MergeExpired  = "expired"
+)
+
+// MergeRequirements are what every member has to meet for two groups to merge.
+var MergeRequirements = types.NewSet(RequirementPlatform, RequirementRole, RequirementRank)
+
+// MergeDTO is a proposal to move the members of the source group into the target group.
+type MergeDTO struct {
+	ID         int32     `json:"id"`
+	SourceID   string    `json:"sourceId"`
+	TargetID   string    `json:"targetId"`
+	ProposedBy int32     `json:"proposedBy"`
+	Status     string    `json:"status"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	// TargetOwnerID is the owner of the target group, who's the one to accept the merge
+	TargetOwnerID int32 `json:"-"`
+	// Moved are the source group's members, once the merge is accepted
+	Moved []MovedMember `json:"-"`
+}
+
+// MovedMember is a player who was moved into the target group, along with the session their membership was recorded for.
+type MovedMember struct {
+	PlayerID  int32
+	SessionID string
+}
+
+func toMergeDTO(row repository.GetMergeProposalRow) MergeDTO {
+	return MergeDTO{
+		ID:         row.ID,
+		SourceID:   row.SourceID,
+		TargetID:   row.TargetID,
+		ProposedBy: row.ProposedBy,
+		Status:     row.Status,
+		ExpiresAt:  row.ExpiresAt,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+// ProposeMergeParams are the groups to merge, on behalf of the owner of the source group.
+type ProposeMergeParams struct {
+	SourceID string
+	TargetID string
+	OwnerID  int32
+}
+
+// MergeGroups returns the target group as it'd be with the source group's
+// members moved into it. Both groups have to be playing the same gamemode in
+// the same region, their members have to fit into one team, and every moved
+// member has to meet the target's platform, role queue and rank policy
+// alongside everyone else, in the role they're seated in. The target's own
+// members aren't checked again, since they may have been let in under relaxed
+// constraints.
+func MergeGroups(target, source *repository.GroupWithPlayers) (*repository.GroupWithPlayers, error) {
+	if target.Gamemode != source.Gamemode || target.Region != source.Region {
+		return nil, NewError(http.StatusBadRequest, "Groups have to be in the same gamemode and region to merge.", nil)
+	}
+
+	merged := *target
+	merged.Players = slices.Clone(target.Players)
+	for _, member := range source.Players {
+		member.Leader = false
+		merged.Players = append(merged.Players, member)
+	}
+	merged.Size += source.Size
+	merged.SetSlots()
+	if OpenSlots(merged) < 0 {
+		return nil, NewError(http.StatusBadRequest, "Groups have more members between them than fit in the team.", nil)
+	}
+
+	if conflicts := memberConflicts(&merged, source.Players, MergeRequirements); len(conflicts) > 0 {
+		return nil, NewDetailedError(http.StatusBadRequest, "Groups can't be merged without excluding members.", map[string]any{
+			"members": conflicts,
+		}, nil)
+	}
+	return &merged, nil
+}
+
+// ProposeMerge asks the owner of the target group to take in the members of
+// the source group. The groups are checked against each other when the merge
+// is proposed, so that the owner isn't asked about one that can't happen, and
+// again once it's accepted. The target's owner is told about it as soon as
+// it's made.
+func (s *Group) ProposeMerge(ctx context.Context, arg ProposeMergeParams) (*MergeDTO, error) {
+	if arg.SourceID == arg.TargetID {
+		return nil, NewError(http.StatusBadRequest, "Groups can't be merged with themselves.", nil)
+	}
+
+	var merge *MergeDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		source, target, err := lockGroups(ctx, q, arg.SourceID, arg.TargetID)
+		if err != nil {
+			return err
+		}
+		// Tokens outlive ownership, so the owner is checked against the group rather than trusted
+		if source.OwnerID != arg.OwnerID {
+			return NewError(http.StatusForbidden, "Only the group owner can propose merges.", nil)
+		}
+		if err := checkMerge(ctx, q, target, source); err != nil {
+			return err
+		}
+
+		if err := q.ExpireMergeProposals(ctx, source.ID); err != nil {
+			return err
+		}
+		created, err := q.CreateMergeProposal(ctx, repository.CreateMergeProposalParams{
+			SourceID:   source.ID,
+			TargetID:   target.ID,
+			ProposedBy: arg.OwnerID,
+			ExpiresAt:  time.Now().Add(MergeProposalTTL),
+		})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusBadRequest, "Merge has already been proposed.", nil)
+			}
+			return err
+		}
+
+		merge = &MergeDTO{
+			ID:            created.ID,
+			SourceID:      source.ID,
+			TargetID:      target.ID,
+			ProposedBy:    created.ProposedBy,
+			Status:        created.Status,
+			ExpiresAt:     created.ExpiresAt,
+			CreatedAt:     created.CreatedAt,
+			TargetOwnerID: target.OwnerID,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.MergeProposed(ctx, merge)
+	}
+	return merge, nil
+}
+
+// GetMergeProposals returns the pending merges that the group proposed or was sent, oldest first.
+func (s *Group) GetMergeProposals(ctx context.Context, groupID string) ([]MergeDTO, error) {
+	rows, err := s.repo.GetMergeProposals(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MergeDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toMergeDTO(repository.GetMergeProposalRow(row)))
+	}
+	return result, nil
+}
+
+// ResolveMerge accepts or declines a pending merge on behalf of the owner of
+// groupID. Only the target's owner can accept it, while either owner can
+// decline it. Accepting moves every member of the source group into the target
+// in one transaction, as long as the groups still fit together, and then
+// deletes the source group. The moved members are sent their tokens for the
+// target group.
+func (s *Group) ResolveMerge(ctx context.Context, groupID string, mergeID, ownerID int32, accept bool) (*MergeDTO, error) {
+	var merge MergeDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		var err error
+		merge, err = getPendingMerge(ctx, q, groupID, mergeID)
+		if err != nil {
+			return err
+		}
+
+		source, target, err := lockGroups(ctx, q, merge.SourceID, merge.TargetID)
+		if err != nil {
+			return err
+		}
+		merge.TargetOwnerID = target.OwnerID
+		// Tokens outlive ownership, so the owner is checked against the group rather than trusted
+		owner := target.OwnerID
+		if groupID == source.ID {
+			owner = source.OwnerID
+		}
+		if owner != ownerID {
+			return NewError(http.StatusForbidden, "Only the group owner can resolve merges.", nil)
+		}
+
+		merge.Status = MergeDeclined
+		if !accept {
+			return resolveMerge(ctx, q, merge)
+		}
+		if groupID != target.ID {
+			return NewError(http.StatusForbidden, "Only the owner of the group being merged into can accept the merge.", nil)
+		}
+
+		merge.Status = MergeAccepted
+		if err := checkMerge(ctx, q, target, source); err != nil {
+			return err
+		}
+		// The proposal goes along with the source group, so it's resolved before the group is deleted
+		if err := resolveMerge(ctx, q, merge); err != nil {
+			return err
+		}
+
+		moved, err := q.MoveGroupMembers(ctx, repository.MoveGroupMembersParams{
+			TargetID: target.ID,
+			SourceID: source.ID,
+		})
+		if err != nil {
+			return err
+		}
+		merge.Moved = make([]MovedMember, 0, len(moved))
+		for _, member := range moved {
+			merge.Moved = append(merge.Moved, MovedMember{
+				PlayerID:  member.PlayerID,
+				SessionID: member.SessionID.String,
+			})
+		}
+
+		if err := deleteGroup(ctx, q, source.ID); err != nil {
+			return err
+		}
+		return q.TouchGroup(ctx, target.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.MergeResolved(ctx, &merge)
+	}
+	// Whatever room the merged group has left is offered to its waitlist
+	if merge.Status == MergeAccepted {
+		s.offerOpenSlots(ctx, merge.TargetID)
+	}
+	return &merge, nil
+}
+
+// checkMerge checks that the source group's members can be moved into the
+// target group, keeping the slots held for the target's waitlisted players
+// free. Members who are banned from the target can't be brought in through a
+// merge. Scheduled groups can't be merged until they start. Both groups must be locked.
+func checkMerge(ctx context.Context, q *repository.Queries, target, source *repository.GroupWithPlayers) error {
+	if err := requireStarted(target); err != nil {
+		return err
+	}
+	if err := requireStarted(source); err != nil {
+		return err
+	}
+	if err := checkMergeBans(ctx, q, target, source); err != nil {
+		return err
+	}
+
+	waitlist, err := getWaitlist(ctx, q, target.ID)
+	if err != nil {
+		return err
+	}
+	_, err = MergeGroups(HoldOffers(target, waitlist, time.Now(), 0), source)
+	return err
+}
+
+// checkMergeBans refuses the merge if any of the source group's members are
+// banned from the target, by player or by the session they joined from.
+func checkMergeBans(ctx context.Context, q *repository.Queries, target, source *repository.GroupWithPlayers) error {
+	members, err := q.GetGroupMemberSessions(ctx, source.ID)
+	if err != nil {
+		return err
+	}
+	names := make(map[int32]string, len(source.Players))
+	for _, player := range source.Players {
+		names[int32(player.ID)] = player.Name
+	}
+
+	banned := make([]map[string]any, 0)
+	for _, member := range members {
+		isBanned, err := q.IsPlayerBanned(ctx, repository.IsPlayerBannedParams{
+			GroupID:   target.ID,
+			PlayerID:  member.PlayerID,
+			SessionID: member.SessionID.String,
+		})
+		if err != nil {
+			return err
+		}
+		if isBanned {
+			banned = append(banned, map[string]any{
+				"playerId": member.PlayerID,
+				"name":     names[member.PlayerID],
+			})
+		}
+	}
+	if len(banned) > 0 {
+		return NewDetailedError(http.StatusBadRequest, "Members of the group are banned from the group being merged into.", map[string]any{
+			"members": banned,
+		}, nil)
+	}
+	return nil
+}
+
+// lockGroups locks both groups, in the same order whichever of them is the
+// source, so that merges between the same groups can't deadlock each other.
+func lockGroups(ctx context.Context, q *repository.Queries, sourceID, targetID string) (*repository.GroupWithPlayers, *repository.GroupWithPlayers, error) {
+	firstID, secondID := sourceID, targetID
+	if secondID < firstID {
+		firstID, secondID = secondID, firstID
+	}
+
+	first, err := lockGroup(ctx, q, firstID)
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := lockGroup(ctx, q, secondID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if first.ID == sourceID {
+		return first, second, nil
+	}
+	return second, first, nil
+}
+
+// getPendingMerge returns the merge if it can still be resolved.
+func getPendingMerge(ctx context.Context, q *repository.Queries, groupID string, mergeID int32) (MergeDTO, error) {
+	row, err := q.GetMergeProposal(ctx, repository.GetMergeProposalParams{
+		ID:      mergeID,
+		GroupID: groupID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return MergeDTO{}, NewError(http.StatusNotFound, "Merge not found.", nil)
+		}
+		return MergeDTO{}, err
+	}
+	if row.Status != MergePending {
+		return MergeDTO{}, NewError(http.StatusBadRequest, "Merge is no longer pending.", nil)
+	}
+	return toMergeDTO(row), nil
+}
+
+// resolveMerge records the merge's new status, failing if it was resolved in the meantime.
+func resolveMerge(ctx context.Context, q *repository.Queries, merge MergeDTO) error {
+	resolved, err := q.ResolveMergeProposal(ctx, repository.ResolveMergeProposalParams{
+		ID:     merge.ID,
+		Status: merge.Status,
+	})
+	if err != nil {
+		return err
+	}
+	if resolved == 0 {
+		return NewError(http.StatusBadRequest, "Merge is no longer pending.", nil)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func targetGroup() *repository.GroupWithPlayers {
+	group := memberedGroup()
+	group.Players[0].Rank = "gm1"
+	return group
+}
+
+func sourceGroup() *repository.GroupWithPlayers {
+	group := memberedGroup()
+	group.ID = "BBBB"
+	group.Size = 2
+	group.Players = []repository.PlayerInGroup{
+		{ID: 4, Leader: true, Platform: "pc", Role: "strategist", Rank: "gm1"},
+		{ID: 5, Platform: "pc", Role: "vanguard", Rank: "gm1"},
+	}
+	return group
+}
+
+func TestMergeGroups(t *testing.T) {
+	t.Run("Should move the source's members into the target", func(t *testing.T) {
+		target := targetGroup()
+		merged, err := services.MergeGroups(target, sourceGroup())
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", merged.ID)
+		assert.Equal(t, 5, merged.Size)
+		assert.Equal(t, 1, merged.SlotsRemaining)
+		assert.Len(t, merged.Players, 5)
+		assert.False(t, merged.Players[3].Leader)
+		assert.Len(t, target.Players, 3)
+	})
+	t.Run("Should refuse groups in different regions", func(t *testing.T) {
+		source := sourceGroup()
+		source.Region = "eu"
+		_, err := services.MergeGroups(targetGroup(), source)
+		assert.ErrorContains(t, err, "same gamemode and region")
+	})
+	t.Run("Should refuse more members than fit in the team", func(t *testing.T) {
+		source := sourceGroup()
+		source.Size = 4
+		source.Players = append(source.Players,
+			repository.PlayerInGroup{ID: 6, Platform: "pc", Role: "strategist", Rank: "gm1"},
+			repository.PlayerInGroup{ID: 7, Platform: "pc", Role: "vanguard", Rank: "gm1"},
+		)
+		_, err := services.MergeGroups(targetGroup(), source)
+		assert.ErrorContains(t, err, "more members between them")
+	})
+	t.Run("Should refuse members whose role is full in the target", func(t *testing.T) {
+		source := sourceGroup()
+		source.Players[0].Role = "duelist"
+		_, err := services.MergeGroups(targetGroup(), source)
+		assert.Equal(t, []int{4}, conflictingPlayers(t, err))
+	})
+	t.Run("Should refuse members on another platform", func(t *testing.T) {
+		source := sourceGroup()
+		source.Players[1].Platform = "ps"
+		_, err := services.MergeGroups(targetGroup(), source)
+		assert.Equal(t, []int{5}, conflictingPlayers(t, err))
+	})
+	t.Run("Should refuse ranks that can't group together", func(t *testing.T) {
+		source := sourceGroup()
+		source.Players[1].Rank = "b3"
+		_, err := services.MergeGroups(targetGroup(), source)
+		assert.Equal(t, []int{4, 5}, conflictingPlayers(t, err))
+	})
+}
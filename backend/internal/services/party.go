@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// MaxPartySize is the most players that can queue or join a group together.
+const MaxPartySize = types.DefaultTeamSize
+
+type PartyMember struct {
+	ID         int32    `json:"id"`
+	Name       string   `json:"name"`
+	Leader     bool     `json:"leader"`
+	Platform   string   `json:"platform"`
+	Roles      []string `json:"roles"`
+	Rank       string   `json:"rank"`
+	Characters []string `json:"characters"`
+	VoiceChat  bool     `json:"voiceChat"`
+	Mic        bool     `json:"mic"`
+	GroupID    string   `json:"groupId,omitempty"`
+	// SessionID is the session the member joined the party from, which is checked against bans
+	SessionID string `json:"-"`
+}
+
+type PartyDTO struct {
+	ID       string        `json:"id"`
+	LeaderID int32         `json:"leaderId"`
+	Members  []PartyMember `json:"members"`
+}
+
+func (p *PartyDTO) Size() int {
+	return len(p.Members)
+}
+
+func (p *PartyDTO) IsMember(playerID int32) bool {
+	return p.Member(playerID) != nil
+}
+
+func (p *PartyDTO) Member(playerID int32) *PartyMember {
+	for i := range p.Members {
+		if p.Members[i].ID == playerID {
+			return &p.Members[i]
+		}
+	}
+	return nil
+}
+
+type JoinGroupAsPartyParams struct {
+	GroupID  string `json:"groupId"`
+	PartyID  string `json:"partyId"`
+	LeaderID int32  `json:"leaderId"`
+	Passcode string `json:"passcode"`
+}
+
+type Party struct {
+	repo *repository.Queries
+}
+
+func NewParty(repo *repository.Queries) *Party {
+	return &Party{
+		repo: repo,
+	}
+}
+
+// CreateParty saves the player's info and creates a party led by them.
+func (s *Party) CreateParty(ctx context.Context, player repository.JoinGroupParams) (string, int32, error) {
+	playerID, err := s.repo.UpsertPlayer(ctx, toUpsertPlayerParams(player))
+	if err != nil {
+		return "", 0, err
+	}
+
+	result, err := s.repo.CreateParty(ctx, repository.CreatePartyParams{
+		LeaderID:  playerID,
+		Roles:     rolePreferences(player),
+		SessionID: player.SessionID,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	switch result.Status {
+	case "200":
+		return result.PartyID, playerID, nil
+	case "400a":
+		return "", 0, NewError(http.StatusBadRequest, "Player is already in a party.", nil)
+	default:
+		return "", 0, NewError(http.StatusInternalServerError, "An unexpected error occurred.", nil)
+	}
+}
+
+// JoinParty saves the player's info and adds them to the party.
+func (s *Party) JoinParty(ctx context.Context, partyID string, player repository.JoinGroupParams) (int32, error) {
+	playerID, err := s.repo.UpsertPlayer(ctx, toUpsertPlayerParams(player))
+	if err != nil {
+		return 0, err
+	}
+
+	status, err := s.repo.JoinParty(ctx, repository.JoinPartyParams{
+		PlayerID:  playerID,
+		PartyID:   partyID,
+		Roles:     rolePreferences(player),
+		SessionID: player.SessionID,
+		MaxSize:   MaxPartySize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	switch status {
+	case "200":
+		return playerID, nil
+	case "400a":
+		return 0, NewError(http.StatusBadRequest, "Player is already in a party.", nil)
+	case "400f":
+		return 0, NewError(http.StatusBadRequest, "Party is full.", nil)
+	case "404":
+		return 0, NewError(http.StatusNotFound, "Party not found.", nil)
+	default:
+		return 0, NewError(http.StatusInternalServerError, "An unexpected error occurred.", nil)
+	}
+}
+
+func (s *Party) LeaveParty(ctx context.Context, partyID string, playerID int32) error {
+	status, err := s.repo.LeaveParty(ctx, repository.LeavePartyParams{
+		PartyID:  partyID,
+		PlayerID: playerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case "200", "204":
+		return nil
+	case "404":
+		return NewError(http.StatusNotFound, "Player not found.", nil)
+	default:
+		return NewError(http.StatusInternalServerError, "An unexpected error occurred.", nil)
+	}
+}
+
+// GetParty returns the party and its members, or nil if it doesn't exist.
+func (s *Party) GetParty(ctx context.Context, partyID string) (*PartyDTO, error) {
+	return getParty(ctx, s.repo, partyID)
+}
+
+// JoinGroup seats every member of the party in the group at once. The group
+// is locked for the duration of the transaction, and nobody is seated unless
+// there's room for the whole party and every member meets the group's
+// requirements. Members that are already in the group are skipped, which
+// lets a party leader create a group and then bring the rest of the party in.
+func (s *Party) JoinGroup(ctx context.Context, arg JoinGroupAsPartyParams) ([]int32, error) {
+	var seated []int32
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := q.LockGroup(ctx, arg.GroupID); err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusNotFound, "Group not found.", nil)
+			}
+			return err
+		}
+
+		group, err := q.GetGroupByID(ctx, arg.GroupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return NewError(http.StatusNotFound, "Group not found.", nil)
+		}
+		if err := requireStarted(group); err != nil {
+			return err
+		}
+
+		party, err := getParty(ctx, q, arg.PartyID)
+		if err != nil {
+			return err
+		}
+		if party == nil {
+			return NewError(http.StatusNotFound, "Party not found.", nil)
+		}
+		if party.LeaderID != arg.LeaderID {
+			return NewError(http.StatusForbidden, "Only the party leader can join a group.", nil)
+		}
+
+		leaderInGroup := party.Member(arg.LeaderID).GroupID == group.ID
+		if !group.Open && group.Passcode != arg.Passcode && !leaderInGroup {
+			return NewError(http.StatusForbidden, "Access denied.", nil)
+		}
+
+		members := make([]PartyMember, 0, party.Size())
+		for _, member := range party.Members {
+			if member.GroupID == group.ID {
+				continue
+			}
+			if member.GroupID != "" {
+				return NewError(http.StatusBadRequest, fmt.Sprintf("%s is already in a group.", member.Name), nil)
+			}
+
+			banned, err := q.IsPlayerBanned(ctx, repository.IsPlayerBannedParams{
+				GroupID:   group.ID,
+				PlayerID:  member.ID,
+				SessionID: member.SessionID,
+			})
+			if err != nil {
+				return err
+			}
+			if banned {
+				return NewError(http.StatusForbidden, fmt.Sprintf("%s is banned from the group.", member.Name), nil)
+			}
+			members = append(members, member)
+		}
+
+		waitlist, err := getWaitlist(ctx, q, group.ID)
+		if err != nil {
+			return err
+		}
+		roles, err := SeatParty(HoldOffers(group, waitlist, time.Now(), 0), members)
+		if err != nil {
+			return err
+		}
+
+		for i, member := range members {
+			if err := q.AddGroupMember(ctx, repository.AddGroupMemberParams{
+				GroupID:   group.ID,
+				PlayerID:  member.ID,
+				Leader:    false,
+				Role:      roles[i],
+				SessionID: member.SessionID,
+			}); err != nil {
+				return err
+			}
+			seated = append(seated, member.ID)
+		}
+		return q.TouchGroup(ctx, group.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return seated, nil
+}
+
+// SeatParty picks the role each member will be seated in, as long as there's
+// room for all of them and each one meets the group's requirements. Later
+// members are checked against the ones seated before them.
+func SeatParty(group *repository.GroupWithPlayers, members []PartyMember) ([]string, error) {
+	if OpenSlots(*group) < len(members) {
+		return nil, NewError(http.StatusBadRequest, "Group does not have room for the whole party.", nil)
+	}
+
+	seated := *group
+	seated.Players = slices.Clone(group.Players)
+	roles := make([]string, 0, len(members))
+	for _, member := range members {
+		player := member.toJoinGroupParams(group.Gamemode, group.Region)
+		player.GroupID = group.ID
+		eligibility := CheckEligibility(&seated, player)
+		if !eligibility.Eligible {
+			return nil, NewDetailedError(http.StatusBadRequest, fmt.Sprintf("%s does not meet the group requirements.", member.Name), map[string]any{
+				"playerId":     member.ID,
+				"requirements": eligibility.Unmet(),
+			}, nil)
+		}
+
+		role := SeatRole(&seated, member.Roles)
+		seated.Players = append(seated.Players, repository.PlayerInGroup{
+			ID:       int(member.ID),
+			Name:     member.Name,
+			Platform: member.Platform,
+			Role:     role,
+			Rank:     member.Rank,
+		})
+		seated.Size++
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func getParty(ctx context.Context, q *repository.Queries, partyID string) (*PartyDTO, error) {
+	rows, err := q.GetPartyMembers(ctx, partyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	party := &PartyDTO{
+		ID:       rows[0].PartyID,
+		LeaderID: rows[0].LeaderID,
+		Members:  make([]PartyMember, 0, len(rows)),
+	}
+	for _, row := range rows {
+		party.Members = append(party.Members, PartyMember{
+			ID:         row.PlayerID,
+			Name:       row.Name,
+			Leader:     row.PlayerID == row.LeaderID,
+			Platform:   row.Platform,
+			Roles:      types.RolePreferences(row.Role, row.Roles),
+			Rank:       types.RankValToRankID[int(row.Rank)],
+			Characters: row.Characters,
+			VoiceChat:  row.VoiceChat,
+			Mic:        row.Mic,
+			GroupID:    row.GroupID.String,
+			SessionID:  row.SessionID.String,
+		})
+	}
+	return party, nil
+}
+
+// players describes every member as a player looking for a group in the
+// gamemode and region, with the leader first.
+func (p *PartyDTO) players(gamemode, region string) []repository.JoinGroupParams {
+	players := make([]repository.JoinGroupParams, 0, p.Size())
+	if leader := p.Member(p.LeaderID); leader != nil {
+		players = append(players, leader.toJoinGroupParams(gamemode, region))
+	}
+	for _, member := range p.Members {
+		if member.ID != p.LeaderID {
+			players = append(players, member.toJoinGroupParams(gamemode, region))
+		}
+	}
+	return players
+}
+
+// toJoinGroupParams describes the member as a player joining a group. Members
+// don't pick a gamemode or region of their own, so they're given the party's.
+func (m PartyMember) toJoinGroupParams(gamemode, region string) repository.JoinGroupParams {
+	return repository.JoinGroupParams{
+		PlayerID:   m.ID,
+		Gamemode:   gamemode,
+		Region:     region,
+		Platform:   m.Platform,
+		Role:       m.Roles[0],
+		Roles:      m.Roles,
+		RankVal:    int32(types.RankIDToRankVal[m.Rank]),
+		Name:       m.Name,
+		Characters: m.Characters,
+		VoiceChat:  m.VoiceChat,
+		Mic:        m.Mic,
+	}
+}
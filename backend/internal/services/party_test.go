@@ -0,0 +1,40 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func gm1Party(roles ...string) []services.PartyMember {
+	members := partyOf("ABCD", 7, roles...).Members
+	for i := range members {
+		members[i].Rank = "gm1"
+	}
+	return members
+}
+
+func TestSeatParty(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	held := now.Add(time.Minute)
+	waitlist := []services.WaitlistEntryDTO{
+		{PlayerID: 10, Rank: "gm1", OfferedRole: "strategist", OfferExpiresAt: &held},
+		{PlayerID: 11, Rank: "gm1", OfferedRole: "strategist", OfferExpiresAt: &held},
+	}
+
+	t.Run("Should seat each member in a role that's open", func(t *testing.T) {
+		roles, err := services.SeatParty(targetGroup(), gm1Party("vanguard", "strategist"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"vanguard", "strategist"}, roles)
+	})
+	t.Run("Should refuse a party that only fits in slots held for waitlisted players", func(t *testing.T) {
+		_, err := services.SeatParty(services.HoldOffers(targetGroup(), waitlist, now, 0), gm1Party("vanguard", "strategist"))
+		assert.ErrorContains(t, err, "does not have room for the whole party")
+	})
+	t.Run("Should check later members against the ones seated before them", func(t *testing.T) {
+		_, err := services.SeatParty(targetGroup(), gm1Party("vanguard", "vanguard"))
+		assert.ErrorContains(t, err, "player8 does not meet the group requirements")
+	})
+}
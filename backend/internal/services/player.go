@@ -2,13 +2,18 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
 )
 
 type Player struct {
-	repo *repository.Queries
+	repo   *repository.Queries
+	groups IGroup
 }
 
 func NewPlayer(repo *repository.Queries) *Player {
@@ -17,8 +22,35 @@ func NewPlayer(repo *repository.Queries) *Player {
 	}
 }
 
+// SetGroups sets the group service that slots freed up by players leaving are offered through.
+func (s *Player) SetGroups(groups IGroup) {
+	s.groups = groups
+}
+
+// JoinGroup seats the player in the group if they meet its requirements. The
+// group is locked while they're seated, so that concurrent joins can't take
+// it past its capacity.
 func (s *Player) JoinGroup(ctx context.Context, arg repository.JoinGroupParams) (int32, error) {
-	result, err := s.repo.JoinGroup(ctx, arg)
+	if arg.AllowedRanks == nil {
+		rankVal, _ := arg.RankVal.(int32)
+		arg.AllowedRanks = types.RankPolicyFor(arg.Gamemode).AllowedRanks(int(rankVal))
+	}
+	arg.Roles = rolePreferences(arg)
+	arg.TeamSize = int32(types.TeamSize(arg.Gamemode))
+
+	var result repository.JoinGroupRow
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := q.LockGroup(ctx, arg.GroupID); err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusNotFound, "Group not found.", nil)
+			}
+			return err
+		}
+
+		var err error
+		result, err = q.JoinGroup(ctx, arg)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -26,6 +58,7 @@ func (s *Player) JoinGroup(ctx context.Context, arg repository.JoinGroupParams)
 	switch result.Status {
 	case "200":
 		// Emit event to notify other players in group
+		s.recordActivity(ctx, arg.GroupID)
 		return result.PlayerID, nil
 	case "400a":
 		return 0, NewError(http.StatusBadRequest, "Player is already in a group.", nil)
@@ -33,13 +66,27 @@ func (s *Player) JoinGroup(ctx context.Context, arg repository.JoinGroupParams)
 		return 0, NewError(http.StatusNotFound, "Group not found.", nil)
 	case "403":
 		return 0, NewError(http.StatusForbidden, "Access denied.", nil)
+	case "403b":
+		return 0, NewError(http.StatusForbidden, "Player is banned from the group.", nil)
+	case "400f":
+		return 0, NewError(http.StatusBadRequest, "Group is full.", nil)
+	case "400s":
+		return 0, NewError(http.StatusBadRequest, "Group hasn't started yet, so the player has to RSVP to it.", nil)
 	case "400e":
-		return 0, NewError(http.StatusBadRequest, "Group requirements not met.", nil)
+		group, err := s.repo.GetGroupByID(ctx, arg.GroupID)
+		if err != nil || group == nil {
+			return 0, NewError(http.StatusBadRequest, "Group requirements not met.", nil)
+		}
+		return 0, newRequirementsError(CheckEligibility(group, arg))
 	default:
 		return 0, NewError(http.StatusInternalServerError, "An unexpected error occurred.", nil)
 	}
 }
 
+func (s *Player) UpsertPlayer(ctx context.Context, arg repository.UpsertPlayerParams) (int32, error) {
+	return s.repo.UpsertPlayer(ctx, arg)
+}
+
 func (s *Player) RemovePlayer(ctx context.Context, arg repository.RemovePlayerParams) (string, error) {
 	result, err := s.repo.RemovePlayer(ctx, arg)
 	if err != nil {
@@ -49,6 +96,8 @@ func (s *Player) RemovePlayer(ctx context.Context, arg repository.RemovePlayerPa
 	switch result.Status {
 	case "200":
 		// TODO: Emit event to notify player left to other players in group
+		s.recordActivity(ctx, arg.GroupID)
+		s.offerOpenSlots(ctx, arg.GroupID)
 		return result.Status, nil
 	case "204":
 		// TODO: Emit event to notify users on group page that group is deleted
@@ -59,3 +108,35 @@ func (s *Player) RemovePlayer(ctx context.Context, arg repository.RemovePlayerPa
 		return "", NewError(http.StatusInternalServerError, "An unexpected error occurred.", nil)
 	}
 }
+
+// GetPlayerGroup returns the ID of the group the player is in, or an empty string if they aren't in one.
+func (s *Player) GetPlayerGroup(ctx context.Context, playerID int32) (string, error) {
+	groupID, err := s.repo.GetPlayerGroup(ctx, playerID)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	return groupID, err
+}
+
+// recordActivity marks the group as active. Failing to do so doesn't fail the change that the members made.
+func (s *Player) recordActivity(ctx context.Context, groupID string) {
+	if err := s.repo.TouchGroup(ctx, groupID); err != nil {
+		log.Error(ctx, fmt.Sprintf("unable to record activity for group %s: %v", groupID, err))
+	}
+}
+
+// offerOpenSlots offers the slot that was freed up to the group's waitlist. Failing to do so doesn't fail the removal.
+func (s *Player) offerOpenSlots(ctx context.Context, groupID string) {
+	if s.groups == nil {
+		return
+	}
+	if err := s.groups.OfferOpenSlots(ctx, groupID); err != nil {
+		log.Error(ctx, fmt.Sprintf("unable to offer open slots in group %s: %v", groupID, err))
+	}
+}
+
+// rolePreferences returns the roles the player is willing to be seated in, in order of preference.
+func rolePreferences(arg repository.JoinGroupParams) []string {
+	role, _ := arg.Role.(string)
+	return types.RolePreferences(role, arg.Roles)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// ReapInterval is how often idle groups are looked for.
+const ReapInterval = time.Minute
+
+// Reaper deletes groups that nobody has been active in for longer than the
+// TTL. Members are warned ahead of time, so that they can keep the group alive.
+type Reaper struct {
+	groups   IGroup
+	notifier GroupNotifier
+	ttl      time.Duration
+	warning  time.Duration
+	now      func() time.Time
+
+	// The last activity that each group was warned about, so that they're only warned once per idle stretch.
+	warned map[string]time.Time
+}
+
+// NewReaper deletes groups once they've been idle for ttl, warning them when there's warning left.
+func NewReaper(groups IGroup, ttl, warning time.Duration) *Reaper {
+	return &Reaper{
+		groups:  groups,
+		ttl:     ttl,
+		warning: warning,
+		now:     time.Now,
+		warned:  make(map[string]time.Time),
+	}
+}
+
+// SetNotifier sets where idle warnings are pushed to.
+func (r *Reaper) SetNotifier(notifier GroupNotifier) {
+	r.notifier = notifier
+}
+
+// SetClock replaces the clock used to tell how long groups have been idle.
+func (r *Reaper) SetClock(now func() time.Time) {
+	r.now = now
+}
+
+// Run reaps idle groups every ReapInterval until the context is done.
+func (r *Reaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(ReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Reap(ctx); err != nil {
+				log.Error(ctx, fmt.Sprintf("unable to reap idle groups: %v", err))
+			}
+		}
+	}
+}
+
+// Reap deletes the groups that have outlived the TTL, and warns the ones that are about to.
+func (r *Reaper) Reap(ctx context.Context) error {
+	now := r.now()
+	groups, err := r.groups.GetIdleGroups(ctx, now.Add(-(r.ttl - r.warning)))
+	if err != nil {
+		return err
+	}
+
+	idle := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		expiresAt := group.LastActiveAt.Add(r.ttl)
+		if !now.Before(expiresAt) {
+			// Groups that became active since they were listed are left alone
+			if _, err := r.groups.DeleteIdleGroup(ctx, group.ID, now.Add(-r.ttl)); err != nil {
+				log.Error(ctx, fmt.Sprintf("unable to delete idle group %s: %v", group.ID, err))
+			}
+			continue
+		}
+
+		idle[group.ID] = true
+		if warnedAbout, ok := r.warned[group.ID]; ok && warnedAbout.Equal(group.LastActiveAt) {
+			continue
+		}
+		r.warned[group.ID] = group.LastActiveAt
+		if r.notifier != nil {
+			r.notifier.GroupIdle(ctx, group.ID, expiresAt)
+		}
+	}
+
+	for groupID := range r.warned {
+		if !idle[groupID] {
+			delete(r.warned, groupID)
+		}
+	}
+	return nil
+}
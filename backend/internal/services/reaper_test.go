@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// idleNotifier records the idle warnings that were sent, by group.
+type idleNotifier struct {
+	warnings map[string]time.Time
+}
+
+func (n *idleNotifier) GroupUpdated(ctx context.Context, group *repository.GroupWithPlayers) {}
+
+func (n *idleNotifier) GroupIdle(ctx context.Context, groupID string, expiresAt time.Time) {
+	n.warnings[groupID] = expiresAt
+}
+
+func (n *idleNotifier) PasscodeRotated(ctx context.Context, groupID string) {}
+
+func (n *idleNotifier) PlayerPromoted(ctx context.Context, groupID string, previousOwnerID, ownerID int32, sessionID string) {
+}
+
+func (n *idleNotifier) JoinRequested(ctx context.Context, ownerID int32, request *services.JoinRequestDTO) {
+}
+
+func (n *idleNotifier) JoinRequestResolved(ctx context.Context, ownerID int32, request *services.JoinRequestDTO) {
+}
+
+func (n *idleNotifier) WaitlistOffered(ctx context.Context, entry *services.WaitlistEntryDTO) {}
+
+func (n *idleNotifier) GroupDeleted(ctx context.Context, groupID string) {}
+
+func (n *idleNotifier) GroupStarted(ctx context.Context, groupID string, rsvps []services.RsvpDTO) {}
+
+func (n *idleNotifier) MergeProposed(ctx context.Context, merge *services.MergeDTO) {}
+
+func (n *idleNotifier) MergeResolved(ctx context.Context, merge *services.MergeDTO) {}
+
+func TestReaper_Reap(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	ttl, warning := time.Hour, 5*time.Minute
+
+	setup := func(t *testing.T) (*services.Reaper, *mocks.MockIGroup, *idleNotifier) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		notifier := &idleNotifier{warnings: make(map[string]time.Time)}
+
+		r := services.NewReaper(mockGroupService, ttl, warning)
+		r.SetNotifier(notifier)
+		r.SetClock(func() time.Time { return now })
+		return r, mockGroupService, notifier
+	}
+
+	t.Run("Should delete groups that have been idle for longer than the TTL", func(t *testing.T) {
+		r, mockGroupService, notifier := setup(t)
+		mockGroupService.EXPECT().GetIdleGroups(gomock.Any(), now.Add(-55*time.Minute)).Return([]repository.GetIdleGroupsRow{
+			{ID: "AAAA", LastActiveAt: now.Add(-2 * time.Hour)},
+		}, nil)
+		mockGroupService.EXPECT().DeleteIdleGroup(gomock.Any(), "AAAA", now.Add(-ttl)).Return(true, nil)
+
+		assert.NoError(t, r.Reap(ctx))
+		assert.Empty(t, notifier.warnings)
+	})
+
+	t.Run("Should warn groups that are about to expire once", func(t *testing.T) {
+		r, mockGroupService, notifier := setup(t)
+		lastActiveAt := now.Add(-57 * time.Minute)
+		mockGroupService.EXPECT().GetIdleGroups(gomock.Any(), gomock.Any()).Return([]repository.GetIdleGroupsRow{
+			{ID: "AAAA", LastActiveAt: lastActiveAt},
+		}, nil).Times(2)
+
+		assert.NoError(t, r.Reap(ctx))
+		assert.Equal(t, map[string]time.Time{"AAAA": lastActiveAt.Add(ttl)}, notifier.warnings)
+
+		delete(notifier.warnings, "AAAA")
+		assert.NoError(t, r.Reap(ctx))
+		assert.Empty(t, notifier.warnings)
+	})
+
+	t.Run("Should warn groups again if they went idle again", func(t *testing.T) {
+		r, mockGroupService, notifier := setup(t)
+		mockGroupService.EXPECT().GetIdleGroups(gomock.Any(), gomock.Any()).Return([]repository.GetIdleGroupsRow{
+			{ID: "AAAA", LastActiveAt: now.Add(-57 * time.Minute)},
+		}, nil)
+		assert.NoError(t, r.Reap(ctx))
+
+		// Someone was active since, so the group is no longer idle
+		mockGroupService.EXPECT().GetIdleGroups(gomock.Any(), gomock.Any()).Return([]repository.GetIdleGroupsRow{}, nil)
+		assert.NoError(t, r.Reap(ctx))
+
+		lastActiveAt := now.Add(-56 * time.Minute)
+		mockGroupService.EXPECT().GetIdleGroups(gomock.Any(), gomock.Any()).Return([]repository.GetIdleGroupsRow{
+			{ID: "AAAA", LastActiveAt: lastActiveAt},
+		}, nil)
+		assert.NoError(t, r.Reap(ctx))
+		assert.Equal(t, lastActiveAt.Add(ttl), notifier.warnings["AAAA"])
+	})
+}
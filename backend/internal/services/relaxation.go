@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// Constraints are the matchmaking rules that a queued player is held to, on
+// top of the group's own requirements.
+type Constraints struct {
+	// MaxRankSpread replaces the gamemode's rank policy spread. Bands and restrictions still apply.
+	MaxRankSpread int `json:"maxRankSpread"`
+	// RequireVoiceChat only matches the player with groups that use voice chat.
+	RequireVoiceChat bool `json:"requireVoiceChat"`
+}
+
+// RankPolicy returns the gamemode's rank policy, with the spread widened or narrowed to MaxRankSpread.
+func (c Constraints) RankPolicy(gamemode string) types.RankPolicy {
+	policy := types.RankPolicyFor(gamemode)
+	policy.MaxSpread = c.MaxRankSpread
+	return policy
+}
+
+// Allows reports whether the group satisfies the constraints that aren't already checked when searching.
+func (c Constraints) Allows(group repository.GroupWithPlayers) bool {
+	if c.RequireVoiceChat {
+		return group.GroupSettings != nil && group.GroupSettings.VoiceChat
+	}
+	return true
+}
+
+// RelaxationStep applies its constraints once the player has been queued for at least After.
+type RelaxationStep struct {
+	After time.Duration
+	Constraints
+}
+
+// RelaxationSchedule loosens a player's constraints the longer they wait. Steps are ordered by After.
+type RelaxationSchedule []RelaxationStep
+
+// DefaultRelaxationSchedule starts out with the default rank policy's spread, and widens it for players who have waited a while.
+var DefaultRelaxationSchedule = RelaxationSchedule{
+	{After: 0, Constraints: Constraints{MaxRankSpread: types.DefaultRankPolicy.MaxSpread}},
+	{After: 3 * time.Minute, Constraints: Constraints{MaxRankSpread: 12}},
+	{After: 6 * time.Minute, Constraints: Constraints{MaxRankSpread: 15}},
+}
+
+// Validate checks that the schedule starts right away, and that each step is at least as lenient as the one before it.
+func (s RelaxationSchedule) Validate() error {
+	if len(s) == 0 {
+		return fmt.Errorf("relaxation schedule must have at least one step")
+	}
+	if s[0].After != 0 {
+		return fmt.Errorf("relaxation schedule must start at 0s")
+	}
+
+	maxSpread := types.RankIDToRankVal["oa"] - types.RankIDToRankVal["b3"]
+	for i, step := range s {
+		if step.MaxRankSpread < 0 || step.MaxRankSpread > maxSpread {
+			return fmt.Errorf("maxRankSpread must be between 0 and %d", maxSpread)
+		}
+		if i == 0 {
+			continue
+		}
+
+		prev := s[i-1]
+		if step.After <= prev.After {
+			return fmt.Errorf("relaxation steps must be in order of when they apply")
+		}
+		if step.MaxRankSpread < prev.MaxRankSpread || (step.RequireVoiceChat && !prev.RequireVoiceChat) {
+			return fmt.Errorf("relaxation step at %s is stricter than the one before it", step.After)
+		}
+	}
+	return nil
+}
+
+// ValidateFor validates a schedule that a player asked for. The server owns
+// how quickly ranks relax, so players can only narrow the schedule: no step can
+// allow a wider spread than DefaultRelaxationSchedule has reached by then, or
+// the gamemode's rank policy if that's wider.
+func (s RelaxationSchedule) ValidateFor(gamemode string) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	for _, step := range s {
+		defaults, _ := DefaultRelaxationSchedule.At(step.After)
+		if limit := max(defaults.MaxRankSpread, types.RankPolicyFor(gamemode).MaxSpread); step.MaxRankSpread > limit {
+			return fmt.Errorf("maxRankSpread after %s must be at most %d", step.After, limit)
+		}
+	}
+	return nil
+}
+
+// At returns the constraints after having waited for the given time, along
+// with how long until they relax next. The wait until the next step is 0 if
+// there are no more steps.
+func (s RelaxationSchedule) At(waited time.Duration) (Constraints, time.Duration) {
+	if len(s) == 0 {
+		return DefaultRelaxationSchedule.At(waited)
+	}
+
+	current := s[0]
+	for _, step := range s[1:] {
+		if step.After > waited {
+			return current.Constraints, step.After - waited
+		}
+		current = step
+	}
+	return current.Constraints, 0
+}
@@ -0,0 +1,89 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelaxationSchedule_At(t *testing.T) {
+	schedule := services.RelaxationSchedule{
+		{After: 0, Constraints: services.Constraints{MaxRankSpread: 5, RequireVoiceChat: true}},
+		{After: 2 * time.Minute, Constraints: services.Constraints{MaxRankSpread: 10, RequireVoiceChat: true}},
+		{After: 5 * time.Minute, Constraints: services.Constraints{MaxRankSpread: 15}},
+	}
+
+	t.Run("Should start with the first step", func(t *testing.T) {
+		constraints, untilNext := schedule.At(0)
+		assert.Equal(t, services.Constraints{MaxRankSpread: 5, RequireVoiceChat: true}, constraints)
+		assert.Equal(t, 2*time.Minute, untilNext)
+	})
+
+	t.Run("Should apply the latest step that the player has waited for", func(t *testing.T) {
+		constraints, untilNext := schedule.At(3 * time.Minute)
+		assert.Equal(t, services.Constraints{MaxRankSpread: 10, RequireVoiceChat: true}, constraints)
+		assert.Equal(t, 2*time.Minute, untilNext)
+	})
+
+	t.Run("Should stop relaxing after the last step", func(t *testing.T) {
+		constraints, untilNext := schedule.At(time.Hour)
+		assert.Equal(t, services.Constraints{MaxRankSpread: 15}, constraints)
+		assert.Zero(t, untilNext)
+	})
+
+	t.Run("Should fall back to the default schedule", func(t *testing.T) {
+		constraints, _ := services.RelaxationSchedule(nil).At(0)
+		assert.Equal(t, services.DefaultRelaxationSchedule[0].Constraints, constraints)
+	})
+}
+
+func TestRelaxationSchedule_Validate(t *testing.T) {
+	step := func(after time.Duration, maxRankSpread int, requireVoiceChat bool) services.RelaxationStep {
+		return services.RelaxationStep{
+			After:       after,
+			Constraints: services.Constraints{MaxRankSpread: maxRankSpread, RequireVoiceChat: requireVoiceChat},
+		}
+	}
+
+	t.Run("Should accept the default schedule", func(t *testing.T) {
+		assert.NoError(t, services.DefaultRelaxationSchedule.Validate())
+	})
+	t.Run("Should require a step that applies right away", func(t *testing.T) {
+		assert.Error(t, services.RelaxationSchedule{}.Validate())
+		assert.Error(t, services.RelaxationSchedule{step(time.Minute, 10, false)}.Validate())
+	})
+	t.Run("Should require steps to be in order", func(t *testing.T) {
+		assert.Error(t, services.RelaxationSchedule{step(0, 10, false), step(0, 12, false)}.Validate())
+	})
+	t.Run("Should reject steps that are stricter than the one before", func(t *testing.T) {
+		assert.Error(t, services.RelaxationSchedule{step(0, 10, false), step(time.Minute, 5, false)}.Validate())
+		assert.Error(t, services.RelaxationSchedule{step(0, 10, false), step(time.Minute, 10, true)}.Validate())
+	})
+	t.Run("Should reject spreads outside of the ranks", func(t *testing.T) {
+		assert.Error(t, services.RelaxationSchedule{step(0, -1, false)}.Validate())
+		assert.Error(t, services.RelaxationSchedule{step(0, 81, false)}.Validate())
+	})
+}
+
+func TestRelaxationSchedule_ValidateFor(t *testing.T) {
+	step := func(after time.Duration, maxRankSpread int) services.RelaxationStep {
+		return services.RelaxationStep{After: after, Constraints: services.Constraints{MaxRankSpread: maxRankSpread}}
+	}
+
+	t.Run("Should accept the default schedule", func(t *testing.T) {
+		assert.NoError(t, services.DefaultRelaxationSchedule.ValidateFor("competitive"))
+	})
+	t.Run("Should accept schedules that are narrower than the default", func(t *testing.T) {
+		assert.NoError(t, services.RelaxationSchedule{step(0, 2), step(10*time.Minute, 4)}.ValidateFor("competitive"))
+	})
+	t.Run("Should reject spreads wider than the default has relaxed to by then", func(t *testing.T) {
+		assert.Error(t, services.RelaxationSchedule{step(0, 80)}.ValidateFor("competitive"))
+		assert.Error(t, services.RelaxationSchedule{step(0, 10), step(time.Minute, 12)}.ValidateFor("competitive"))
+		assert.NoError(t, services.RelaxationSchedule{step(0, 10), step(3*time.Minute, 12)}.ValidateFor("competitive"))
+	})
+	t.Run("Should still validate the schedule itself", func(t *testing.T) {
+		assert.Error(t, services.RelaxationSchedule{step(0, 10), step(time.Minute, 5)}.ValidateFor("competitive"))
+	})
+}
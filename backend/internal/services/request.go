@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// JoinRequestTTL is how long a request to join waits for the owner before it expires.
+const JoinRequestTTL = 10 * time.Minute
+
+const (
+	JoinRequestPending   = "pending"
+	JoinRequestApproved  = "approved"
+	JoinRequestDenied    = "denied"
+	JoinRequestCancelled = "cancelled"
+	JoinRequestExpired   = "expired"
+)
+
+// JoinRequestDTO is a player asking to join a group, as they'd be seen by its owner.
+type JoinRequestDTO struct {
+	ID         int32     `json:"id"`
+	GroupID    string    `json:"groupId"`
+	PlayerID   int32     `json:"playerId"`
+	Name       string    `json:"name"`
+	Platform   string    `json:"platform"`
+	Rank       string    `json:"rank"`
+	Roles      []string  `json:"roles"`
+	Characters []string  `json:"characters"`
+	VoiceChat  bool      `json:"voiceChat"`
+	Mic        bool      `json:"mic"`
+	Status     string    `json:"status"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	// SessionID is the session the player asked from, which is recorded on their membership once approved.
+	SessionID string `json:"-"`
+}
+
+func toJoinRequestDTO(row repository.GetJoinRequestRow) JoinRequestDTO {
+	return JoinRequestDTO{
+		ID:         row.ID,
+		GroupID:    row.GroupID,
+		PlayerID:   row.PlayerID,
+		Name:       row.Name,
+		Platform:   row.Platform,
+		Rank:       types.RankValToRankID[int(row.Rank)],
+		Roles:      row.Roles,
+		Characters: row.Characters,
+		VoiceChat:  row.VoiceChat,
+		Mic:        row.Mic,
+		Status:     row.Status,
+		ExpiresAt:  row.ExpiresAt,
+		CreatedAt:  row.CreatedAt,
+		SessionID:  row.SessionID.String,
+	}
+}
+
+// RequestToJoin asks the owner of a group that's taking requests to let the
+// player in. The player has to meet the group's requirements to ask, and can
+// only have one request pending at a time. The owner is told about it as soon
+// as it's made.
+func (s *Group) RequestToJoin(ctx context.Context, arg repository.JoinGroupParams) (*JoinRequestDTO, error) {
+	var request *JoinRequestDTO
+	var ownerID int32
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := q.LockGroup(ctx, arg.GroupID); err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusNotFound, "Group not found.", nil)
+			}
+			return err
+		}
+
+		group, err := q.GetGroupByID(ctx, arg.GroupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return NewError(http.StatusNotFound, "Group not found.", nil)
+		}
+		if err := requireStarted(group); err != nil {
+			return err
+		}
+		if group.Open || !group.RequestToJoin {
+			return NewError(http.StatusBadRequest, "Group does not take join requests.", nil)
+		}
+
+		if arg.PlayerID != 0 {
+			if _, err := q.GetPlayerGroup(ctx, arg.PlayerID); err != pgx.ErrNoRows {
+				if err != nil {
+					return err
+				}
+				return NewError(http.StatusBadRequest, "Player is already in a group.", nil)
+			}
+		}
+
+		banned, err := q.IsPlayerBanned(ctx, repository.IsPlayerBannedParams{
+			GroupID:   group.ID,
+			PlayerID:  arg.PlayerID,
+			SessionID: arg.SessionID,
+		})
+		if err != nil {
+			return err
+		}
+		if banned {
+			return NewError(http.StatusForbidden, "Player is banned from the group.", nil)
+		}
+
+		waitlist, err := getWaitlist(ctx, q, group.ID)
+		if err != nil {
+			return err
+		}
+		held := HoldOffers(group, waitlist, time.Now(), arg.PlayerID)
+		eligibility := CheckEligibility(held, arg)
+		if !eligibility.Eligible {
+			return newRequirementsError(eligibility)
+		}
+		if OpenSlots(*held) <= 0 {
+			return NewError(http.StatusBadRequest, "Group is full.", nil)
+		}
+
+		playerID, err := q.UpsertPlayer(ctx, toUpsertPlayerParams(arg))
+		if err != nil {
+			return err
+		}
+
+		if err := q.ExpireJoinRequests(ctx, playerID); err != nil {
+			return err
+		}
+		if _, err := q.GetPendingJoinRequest(ctx, playerID); err != pgx.ErrNoRows {
+			if err != nil {
+				return err
+			}
+			return NewError(http.StatusBadRequest, "Player already has a pending join request.", nil)
+		}
+
+		created, err := q.CreateJoinRequest(ctx, repository.CreateJoinRequestParams{
+			GroupID:   group.ID,
+			PlayerID:  playerID,
+			Roles:     rolePreferences(arg),
+			SessionID: arg.SessionID,
+			ExpiresAt: time.Now().Add(JoinRequestTTL),
+		})
+		if err != nil {
+			return err
+		}
+
+		rankVal, _ := arg.RankVal.(int32)
+		request = &JoinRequestDTO{
+			ID:         created.ID,
+			GroupID:    group.ID,
+			PlayerID:   playerID,
+			Name:       arg.Name,
+			Platform:   arg.Platform,
+			Rank:       types.RankValToRankID[int(rankVal)],
+			Roles:      created.Roles,
+			Characters: arg.Characters,
+			VoiceChat:  arg.VoiceChat,
+			Mic:        arg.Mic,
+			Status:     created.Status,
+			ExpiresAt:  created.ExpiresAt,
+			CreatedAt:  created.CreatedAt,
+			SessionID:  arg.SessionID,
+		}
+		ownerID = group.OwnerID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.JoinRequested(ctx, ownerID, request)
+	}
+	return request, nil
+}
+
+// GetJoinRequests returns the group's pending requests, oldest first.
+func (s *Group) GetJoinRequests(ctx context.Context, groupID string) ([]JoinRequestDTO, error) {
+	rows, err := s.repo.GetJoinRequests(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]JoinRequestDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toJoinRequestDTO(repository.GetJoinRequestRow(row)))
+	}
+	return result, nil
+}
+
+// GetJoinRequest returns the request whatever its status, so that the player
+// who made it can find out how it was resolved.
+func (s *Group) GetJoinRequest(ctx context.Context, groupID string, requestID int32) (*JoinRequestDTO, error) {
+	row, err := s.repo.GetJoinRequest(ctx, repository.GetJoinRequestParams{
+		ID:      requestID,
+		GroupID: groupID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, NewError(http.StatusNotFound, "Join request not found.", nil)
+		}
+		return nil, err
+	}
+	request := toJoinRequestDTO(row)
+	return &request, nil
+}
+
+// CancelJoinRequest withdraws the player's pending request. The owner is told that it's gone.
+func (s *Group) CancelJoinRequest(ctx context.Context, groupID string, requestID, playerID int32) error {
+	var request JoinRequestDTO
+	var ownerID int32
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := q.LockGroup(ctx, groupID); err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusNotFound, "Group not found.", nil)
+			}
+			return err
+		}
+
+		group, err := q.GetGroupByID(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return NewError(http.StatusNotFound, "Group not found.", nil)
+		}
+
+		request, err = getPendingJoinRequest(ctx, q, groupID, requestID)
+		if err != nil {
+			return err
+		}
+		if request.PlayerID != playerID {
+			return NewError(http.StatusForbidden, "Only the player who made the request can cancel it.", nil)
+		}
+
+		ownerID = group.OwnerID
+		request.Status = JoinRequestCancelled
+		return resolveJoinRequest(ctx, q, request)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notifier != nil {
+		s.notifier.JoinRequestResolved(ctx, ownerID, &request)
+	}
+	return nil
+}
+
+// ResolveJoinRequest approves or denies a pending request. Approved players
+// are seated in the first of their roles that's still open, as long as they
+// still meet the group's requirements. Either way, the player who asked is
+// told how it went.
+func (s *Group) ResolveJoinRequest(ctx context.Context, groupID string, requestID, ownerID int32, approve bool) (*JoinRequestDTO, error) {
+	var request JoinRequestDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		if _, err := q.LockGroup(ctx, groupID); err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusNotFound, "Group not found.", nil)
+			}
+			return err
+		}
+
+		group, err := q.GetGroupByID(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return NewError(http.StatusNotFound, "Group not found.", nil)
+		}
+		// Tokens outlive ownership, so the owner is checked against the group rather than trusted
+		if group.OwnerID != ownerID {
+			return NewError(http.StatusForbidden, "Only the group owner can resolve join requests.", nil)
+		}
+
+		request, err = getPendingJoinRequest(ctx, q, groupID, requestID)
+		if err != nil {
+			return err
+		}
+
+		request.Status = JoinRequestDenied
+		if approve {
+			request.Status = JoinRequestApproved
+			if err := seatJoinRequest(ctx, q, group, request); err != nil {
+				return err
+			}
+		}
+		return resolveJoinRequest(ctx, q, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.JoinRequestResolved(ctx, ownerID, &request)
+	}
+	return &request, nil
+}
+
+// getPendingJoinRequest returns the request if it can still be resolved.
+func getPendingJoinRequest(ctx context.Context, q *repository.Queries, groupID string, requestID int32) (JoinRequestDTO, error) {
+	row, err := q.GetJoinRequest(ctx, repository.GetJoinRequestParams{
+		ID:      requestID,
+		GroupID: groupID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return JoinRequestDTO{}, NewError(http.StatusNotFound, "Join request not found.", nil)
+		}
+		return JoinRequestDTO{}, err
+	}
+	if row.Status != JoinRequestPending {
+		return JoinRequestDTO{}, NewError(http.StatusBadRequest, "Join request is no longer pending.", nil)
+	}
+	return toJoinRequestDTO(row), nil
+}
+
+// seatJoinRequest adds the player who asked to the group, checking them against it as it is now. The group must be locked.
+func seatJoinRequest(ctx context.Context, q *repository.Queries, group *repository.GroupWithPlayers, request JoinRequestDTO) error {
+	if _, err := q.GetPlayerGroup(ctx, request.PlayerID); err != pgx.ErrNoRows {
+		if err != nil {
+			return err
+		}
+		return NewError(http.StatusBadRequest, "Player is already in a group.", nil)
+	}
+
+	waitlist, err := getWaitlist(ctx, q, group.ID)
+	if err != nil {
+		return err
+	}
+	role, err := SeatJoinRequest(HoldOffers(group, waitlist, time.Now(), request.PlayerID), request)
+	if err != nil {
+		return err
+	}
+
+	if err := q.AddGroupMember(ctx, repository.AddGroupMemberParams{
+		GroupID:   group.ID,
+		PlayerID:  request.PlayerID,
+		Leader:    false,
+		Role:      role,
+		SessionID: request.SessionID,
+	}); err != nil {
+		return err
+	}
+	return q.TouchGroup(ctx, group.ID)
+}
+
+// SeatJoinRequest picks the role the player who asked will be seated in, as
+// long as the group has room for them and they still meet its requirements.
+func SeatJoinRequest(group *repository.GroupWithPlayers, request JoinRequestDTO) (string, error) {
+	eligibility := CheckEligibility(group, request.asPlayer(group))
+	if !eligibility.Eligible {
+		return "", newRequirementsError(eligibility)
+	}
+	if OpenSlots(*group) <= 0 {
+		return "", NewError(http.StatusBadRequest, "Group is full.", nil)
+	}
+	return SeatRole(group, request.Roles), nil
+}
+
+// resolveJoinRequest records the request's new status, failing if it was resolved in the meantime.
+func resolveJoinRequest(ctx context.Context, q *repository.Queries, request JoinRequestDTO) error {
+	resolved, err := q.ResolveJoinRequest(ctx, repository.ResolveJoinRequestParams{
+		ID:     request.ID,
+		Status: request.Status,
+	})
+	if err != nil {
+		return err
+	}
+	if resolved == 0 {
+		return NewError(http.StatusBadRequest, "Join request is no longer pending.", nil)
+	}
+	return nil
+}
+
+// asPlayer describes the player who asked as joining the group.
+func (r JoinRequestDTO) asPlayer(group *repository.GroupWithPlayers) repository.JoinGroupParams {
+	player := repository.JoinGroupParams{
+		GroupID:    group.ID,
+		PlayerID:   r.PlayerID,
+		Gamemode:   group.Gamemode,
+		Region:     group.Region,
+		Platform:   r.Platform,
+		Roles:      r.Roles,
+		RankVal:    int32(types.RankIDToRankVal[r.Rank]),
+		Characters: r.Characters,
+		VoiceChat:  r.VoiceChat,
+		Mic:        r.Mic,
+	}
+	if len(r.Roles) > 0 {
+		player.Role = r.Roles[0]
+	}
+	return player
+}
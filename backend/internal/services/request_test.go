@@ -0,0 +1,51 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeatJoinRequest(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	held := now.Add(time.Minute)
+	request := services.JoinRequestDTO{PlayerID: 7, Platform: "pc", Roles: []string{"strategist"}, Rank: "gm1"}
+
+	t.Run("Should seat the player in a role that's open", func(t *testing.T) {
+		role, err := services.SeatJoinRequest(targetGroup(), request)
+		assert.NoError(t, err)
+		assert.Equal(t, "strategist", role)
+	})
+	t.Run("Should refuse a slot that's held for a waitlisted player", func(t *testing.T) {
+		waitlist := []services.WaitlistEntryDTO{
+			{PlayerID: 10, Rank: "gm1", OfferedRole: "strategist", OfferExpiresAt: &held},
+			{PlayerID: 11, Rank: "gm1", OfferedRole: "strategist", OfferExpiresAt: &held},
+		}
+		_, err := services.SeatJoinRequest(services.HoldOffers(targetGroup(), waitlist, now, request.PlayerID), request)
+		assert.Equal(t, []string{services.RequirementRole}, unmetRequirements(t, err))
+	})
+	t.Run("Should let the player take the slot held for them", func(t *testing.T) {
+		waitlist := []services.WaitlistEntryDTO{
+			{PlayerID: 7, Rank: "gm1", OfferedRole: "strategist", OfferExpiresAt: &held},
+			{PlayerID: 11, Rank: "gm1", OfferedRole: "strategist", OfferExpiresAt: &held},
+		}
+		role, err := services.SeatJoinRequest(services.HoldOffers(targetGroup(), waitlist, now, request.PlayerID), request)
+		assert.NoError(t, err)
+		assert.Equal(t, "strategist", role)
+	})
+}
+
+func unmetRequirements(t *testing.T, err error) []string {
+	t.Helper()
+	detailedErr, ok := err.(services.DetailedError)
+	if !assert.True(t, ok) {
+		return nil
+	}
+	names := []string{}
+	for _, requirement := range detailedErr.Details()["requirements"].([]services.Requirement) {
+		names = append(names, requirement.Name)
+	}
+	return names
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// MaxScheduleAhead is how far ahead a group can be scheduled.
+const MaxScheduleAhead = 14 * 24 * time.Hour
+
+// MaxScheduledDuration is how long a scheduled group can be played for.
+const MaxScheduledDuration = 12 * time.Hour
+
+// ScheduleSweepInterval is how often groups that are due to start are looked for.
+const ScheduleSweepInterval = 10 * time.Second
+
+const (
+	// RsvpSeated is an RSVP that the player was seated from when the group started.
+	RsvpSeated = "seated"
+	// RsvpMissed is an RSVP that couldn't be seated, e.g. the player was already in another group.
+	RsvpMissed = "missed"
+)
+
+// RsvpDTO is a player who's holding a slot in a scheduled group until it starts.
+type RsvpDTO struct {
+	GroupID    string    `json:"groupId"`
+	PlayerID   int32     `json:"playerId"`
+	Name       string    `json:"name"`
+	Platform   string    `json:"platform"`
+	Rank       string    `json:"rank"`
+	Characters []string  `json:"characters"`
+	VoiceChat  bool      `json:"voiceChat"`
+	Mic        bool      `json:"mic"`
+	Role       string    `json:"role"`
+	CreatedAt  time.Time `json:"createdAt"`
+	// Status is set once the group starts, to whether the player was seated
+	Status string `json:"status,omitempty"`
+
+	ID int32 `json:"-"`
+	// SessionID is the session the player RSVP'd from, which is recorded on their membership once the group starts.
+	SessionID string `json:"-"`
+}
+
+func toRsvpDTO(row repository.GetRsvpsRow) RsvpDTO {
+	return RsvpDTO{
+		GroupID:    row.GroupID,
+		PlayerID:   row.PlayerID,
+		Name:       row.Name,
+		Platform:   row.Platform,
+		Rank:       types.RankValToRankID[int(row.Rank)],
+		Characters: row.Characters,
+		VoiceChat:  row.VoiceChat,
+		Mic:        row.Mic,
+		Role:       row.Role,
+		CreatedAt:  row.CreatedAt,
+		ID:         row.ID,
+		SessionID:  row.SessionID.String,
+	}
+}
+
+// asPlayer describes the player as joining the group in the role they RSVP'd for.
+func (r RsvpDTO) asPlayer(group *repository.GroupWithPlayers) repository.JoinGroupParams {
+	return repository.JoinGroupParams{
+		GroupID:    group.ID,
+		PlayerID:   r.PlayerID,
+		Gamemode:   group.Gamemode,
+		Region:     group.Region,
+		Platform:   r.Platform,
+		Role:       r.Role,
+		Roles:      []string{r.Role},
+		RankVal:    int32(types.RankIDToRankVal[r.Rank]),
+		Characters: r.Characters,
+		VoiceChat:  r.VoiceChat,
+		Mic:        r.Mic,
+	}
+}
+
+// HoldRsvps returns the group as if the players who RSVP'd had been seated,
+// so that their slots aren't handed out twice. The player with exceptPlayerID
+// is left out, since they're the one taking their slot.
+func HoldRsvps(group *repository.GroupWithPlayers, rsvps []RsvpDTO, exceptPlayerID int32) *repository.GroupWithPlayers {
+	held := *group
+	held.Players = slices.Clone(group.Players)
+	for _, rsvp := range rsvps {
+		if rsvp.PlayerID == exceptPlayerID {
+			continue
+		}
+		held.Players = append(held.Players, repository.PlayerInGroup{
+			ID:         int(rsvp.PlayerID),
+			Name:       rsvp.Name,
+			Platform:   rsvp.Platform,
+			Role:       rsvp.Role,
+			Rank:       rsvp.Rank,
+			Characters: rsvp.Characters,
+			VoiceChat:  rsvp.VoiceChat,
+			Mic:        rsvp.Mic,
+		})
+		held.Size++
+	}
+	return &held
+}
+
+// requireStarted turns players away from a scheduled group, since they have to RSVP to it until it starts.
+func requireStarted(group *repository.GroupWithPlayers) error {
+	if group.Scheduled {
+		return NewError(http.StatusBadRequest, "Group hasn't started yet, so the player has to RSVP to it.", nil)
+	}
+	return nil
+}
+
+// Rsvp holds a slot in the scheduled group for the player, in one of their
+// preferred roles. Players have to meet the same requirements as they would
+// to join it, and private groups need the passcode.
+func (s *Group) Rsvp(ctx context.Context, player repository.JoinGroupParams) (*RsvpDTO, error) {
+	var rsvp *RsvpDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		group, err := lockGroup(ctx, q, player.GroupID)
+		if err != nil {
+			return err
+		}
+		if !group.Scheduled {
+			return NewError(http.StatusBadRequest, "Group has already started, so the player can join it directly.", nil)
+		}
+		if !group.Open && group.Passcode != player.Passcode {
+			return NewError(http.StatusForbidden, "Access denied.", nil)
+		}
+
+		banned, err := q.IsPlayerBanned(ctx, repository.IsPlayerBannedParams{
+			GroupID:   group.ID,
+			PlayerID:  player.PlayerID,
+			SessionID: player.SessionID,
+		})
+		if err != nil {
+			return err
+		}
+		if banned {
+			return NewError(http.StatusForbidden, "Player is banned from the group.", nil)
+		}
+
+		if slices.ContainsFunc(group.Players, func(p repository.PlayerInGroup) bool {
+			return player.PlayerID != 0 && p.ID == int(player.PlayerID)
+		}) {
+			return NewError(http.StatusBadRequest, "Player is already in the group.", nil)
+		}
+
+		rsvps, err := getRsvps(ctx, q, group.ID)
+		if err != nil {
+			return err
+		}
+		held := HoldRsvps(group, rsvps, player.PlayerID)
+		eligibility := CheckEligibility(held, player)
+		if !eligibility.Eligible {
+			return newRequirementsError(eligibility)
+		}
+		if OpenSlots(*held) <= 0 {
+			return NewError(http.StatusBadRequest, "Group is full.", nil)
+		}
+
+		playerID, err := q.UpsertPlayer(ctx, toUpsertPlayerParams(player))
+		if err != nil {
+			return err
+		}
+
+		role := SeatRole(held, rolePreferences(player))
+		created, err := q.AddRsvp(ctx, repository.AddRsvpParams{
+			GroupID:   group.ID,
+			PlayerID:  playerID,
+			Role:      role,
+			SessionID: player.SessionID,
+		})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusBadRequest, "Player has already RSVP'd to the group.", nil)
+			}
+			return err
+		}
+
+		rankVal, _ := player.RankVal.(int32)
+		rsvp = &RsvpDTO{
+			GroupID:    group.ID,
+			PlayerID:   playerID,
+			Name:       player.Name,
+			Platform:   player.Platform,
+			Rank:       types.RankValToRankID[int(rankVal)],
+			Characters: player.Characters,
+			VoiceChat:  player.VoiceChat,
+			Mic:        player.Mic,
+			Role:       role,
+			CreatedAt:  created.CreatedAt,
+			ID:         created.ID,
+			SessionID:  player.SessionID,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsvp, nil
+}
+
+// GetRsvps returns the players who RSVP'd to the group, in the order they did.
+func (s *Group) GetRsvps(ctx context.Context, groupID string) ([]RsvpDTO, error) {
+	return getRsvps(ctx, s.repo, groupID)
+}
+
+// CancelRsvp gives up the slot the player was holding in the scheduled group.
+func (s *Group) CancelRsvp(ctx context.Context, groupID string, playerID int32) error {
+	removed, err := s.repo.RemoveRsvp(ctx, repository.RemoveRsvpParams{
+		GroupID:  groupID,
+		PlayerID: playerID,
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return NewError(http.StatusNotFound, "Player has not RSVP'd to the group.", nil)
+	}
+	return nil
+}
+
+// GetDueGroups returns the scheduled groups whose start time has passed.
+func (s *Group) GetDueGroups(ctx context.Context) ([]string, error) {
+	return s.repo.GetDueGroups(ctx)
+}
+
+// StartGroup turns the scheduled group into a live one, seating the players
+// who RSVP'd to it. Players who found another group, were banned or no longer
+// meet the group's requirements are passed over. Everyone who RSVP'd is told
+// over the websocket whether they were seated.
+func (s *Group) StartGroup(ctx context.Context, groupID string) error {
+	var rsvps []RsvpDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		group, err := lockGroup(ctx, q, groupID)
+		if err != nil {
+			return err
+		}
+		if !group.Scheduled {
+			return nil
+		}
+
+		rsvps, err = getRsvps(ctx, q, groupID)
+		if err != nil {
+			return err
+		}
+		seated := HoldRsvps(group, nil, 0)
+		for i, rsvp := range rsvps {
+			rsvps[i].Status = RsvpMissed
+
+			if _, err := q.GetPlayerGroup(ctx, rsvp.PlayerID); err != pgx.ErrNoRows {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			banned, err := q.IsPlayerBanned(ctx, repository.IsPlayerBannedParams{
+				GroupID:   groupID,
+				PlayerID:  rsvp.PlayerID,
+				SessionID: rsvp.SessionID,
+			})
+			if err != nil {
+				return err
+			}
+			if banned {
+				continue
+			}
+
+			if OpenSlots(*seated) <= 0 || !CheckEligibility(seated, rsvp.asPlayer(seated)).Eligible {
+				continue
+			}
+			role := SeatRole(seated, []string{rsvp.Role})
+			if role == "" {
+				continue
+			}
+
+			if err := q.AddGroupMember(ctx, repository.AddGroupMemberParams{
+				GroupID:   groupID,
+				PlayerID:  rsvp.PlayerID,
+				Leader:    false,
+				Role:      role,
+				SessionID: rsvp.SessionID,
+			}); err != nil {
+				return err
+			}
+			rsvps[i].Status = RsvpSeated
+			seated = HoldRsvps(seated, []RsvpDTO{rsvps[i]}, 0)
+		}
+
+		if err := q.DeleteRsvps(ctx, groupID); err != nil {
+			return err
+		}
+		return q.StartGroup(ctx, groupID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notifier != nil && rsvps != nil {
+		s.notifier.GroupStarted(ctx, groupID, rsvps)
+	}
+	return nil
+}
+
+func getRsvps(ctx context.Context, q *repository.Queries, groupID string) ([]RsvpDTO, error) {
+	rows, err := q.GetRsvps(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RsvpDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toRsvpDTO(row))
+	}
+	return result, nil
+}
+
+// GroupScheduler starts scheduled groups once their start time comes.
+type GroupScheduler struct {
+	groups IGroup
+}
+
+func NewGroupScheduler(groups IGroup) *GroupScheduler {
+	return &GroupScheduler{
+		groups: groups,
+	}
+}
+
+// Run starts due groups every ScheduleSweepInterval until the context is done.
+func (g *GroupScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(ScheduleSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := g.Sweep(ctx); err != nil {
+				log.Error(ctx, fmt.Sprintf("unable to start scheduled groups: %v", err))
+			}
+		}
+	}
+}
+
+// Sweep starts each scheduled group whose start time has passed.
+func (g *GroupScheduler) Sweep(ctx context.Context) error {
+	groupIDs, err := g.groups.GetDueGroups(ctx)
+	if err != nil {
+		return err
+	}
+	for _, groupID := range groupIDs {
+		if err := g.groups.StartGroup(ctx, groupID); err != nil {
+			log.Error(ctx, fmt.Sprintf("unable to start scheduled group %s: %v", groupID, err))
+		}
+	}
+	return nil
+}
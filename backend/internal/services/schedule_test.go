@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHoldRsvps(t *testing.T) {
+	rsvps := []services.RsvpDTO{
+		{PlayerID: 4, Rank: "d3", Role: "vanguard"},
+		{PlayerID: 5, Rank: "d3", Role: "duelist"},
+	}
+
+	t.Run("Should take the slots of every player who RSVP'd", func(t *testing.T) {
+		group := memberedGroup()
+		result := services.HoldRsvps(group, rsvps, 0)
+
+		assert.Equal(t, group.Size+2, result.Size)
+		assert.Len(t, result.Players, len(group.Players)+2)
+		assert.Equal(t, "duelist", result.Players[len(result.Players)-1].Role)
+		assert.Equal(t, services.OpenSlots(*group)-2, services.OpenSlots(*result))
+	})
+
+	t.Run("Should leave out the player taking their slot", func(t *testing.T) {
+		group := memberedGroup()
+		result := services.HoldRsvps(group, rsvps, 4)
+
+		assert.Equal(t, group.Size+1, result.Size)
+		assert.Len(t, result.Players, len(group.Players)+1)
+	})
+
+	t.Run("Should not change the group it was given", func(t *testing.T) {
+		group := memberedGroup()
+		size, players := group.Size, len(group.Players)
+		services.HoldRsvps(group, rsvps, 0)
+
+		assert.Equal(t, size, group.Size)
+		assert.Len(t, group.Players, players)
+	})
+}
+
+func TestGroupScheduler_Sweep(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should start each group that's due", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockGroupService.EXPECT().GetDueGroups(gomock.Any()).Return([]string{"AAAA", "BBBB"}, nil)
+		mockGroupService.EXPECT().StartGroup(gomock.Any(), "AAAA").Return(fmt.Errorf("unexpected error"))
+		mockGroupService.EXPECT().StartGroup(gomock.Any(), "BBBB").Return(nil)
+
+		assert.NoError(t, services.NewGroupScheduler(mockGroupService).Sweep(ctx))
+	})
+
+	t.Run("Should return an error if the groups can't be found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockGroupService.EXPECT().GetDueGroups(gomock.Any()).Return(nil, fmt.Errorf("unexpected error"))
+
+		assert.Error(t, services.NewGroupScheduler(mockGroupService).Sweep(ctx))
+	})
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+)
+
+// QueueStatus describes where a queued player stands.
+type QueueStatus struct {
+	*QueueEntry
+	// Position among the unmatched players queued in the same region, gamemode and rank division, starting at 1.
+	Position      int `json:"position"`
+	WaitedSeconds int `json:"waitedSeconds"`
+	// EstimatedWaitSeconds is based on how many similar players were matched
+	// over the last ThroughputWindow. It's left out if there were none.
+	EstimatedWaitSeconds *int `json:"estimatedWaitSeconds,omitempty"`
+}
+
+// QueueNotifier is sent the status of every queued player after each matching pass.
+type QueueNotifier interface {
+	NotifyQueue(ctx context.Context, statuses []*QueueStatus)
+}
+
+// SetNotifier sets where queue updates are pushed to after each matching pass.
+func (m *Matcher) SetNotifier(notifier QueueNotifier) {
+	m.Lock()
+	defer m.Unlock()
+	m.notifier = notifier
+}
+
+// Status returns the status of a queued player, including their match if they haven't collected it yet.
+func (m *Matcher) Status(ctx context.Context, playerID int32) (*QueueStatus, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	entry, ok := m.queue[playerID]
+	if !ok {
+		return nil, NewError(http.StatusNotFound, "Player is not queued.", nil)
+	}
+	if entry.Match != nil {
+		return m.status(entry, 0), nil
+	}
+
+	bucket := throughputBucket(entry.Player)
+	position := 1
+	for _, other := range m.queue {
+		if other.Match == nil && throughputBucket(other.Player) == bucket && queuedBefore(other, entry) {
+			position++
+		}
+	}
+	return m.status(entry, position), nil
+}
+
+// notify pushes the status of every queued player to the notifier, if there is one.
+func (m *Matcher) notify(ctx context.Context) {
+	m.Lock()
+	notifier := m.notifier
+	if notifier == nil {
+		m.Unlock()
+		return
+	}
+
+	entries := make([]*QueueEntry, 0, len(m.queue))
+	for _, entry := range m.queue {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return queuedBefore(entries[i], entries[j])
+	})
+
+	positions := make(map[string]int)
+	statuses := make([]*QueueStatus, 0, len(entries))
+	for _, entry := range entries {
+		position := 0
+		if entry.Match == nil {
+			bucket := throughputBucket(entry.Player)
+			positions[bucket]++
+			position = positions[bucket]
+		}
+		statuses = append(statuses, m.status(entry, position))
+	}
+	m.Unlock()
+
+	notifier.NotifyQueue(ctx, statuses)
+}
+
+// status describes the entry at the given position. Matched entries have no
+// position, and stop counting their wait once matched. The lock must be held.
+func (m *Matcher) status(entry *QueueEntry, position int) *QueueStatus {
+	now := m.now()
+	status := &QueueStatus{
+		QueueEntry: entry.snapshot(now),
+		Position:   position,
+	}
+	if entry.Match != nil {
+		status.WaitedSeconds = int(entry.MatchedAt.Sub(entry.QueuedAt).Seconds())
+		return status
+	}
+
+	status.WaitedSeconds = int(now.Sub(entry.QueuedAt).Seconds())
+	if rate := m.throughput.Rate(throughputBucket(entry.Player), now); rate > 0 {
+		estimate := int(math.Ceil(float64(position) / rate))
+		status.EstimatedWaitSeconds = &estimate
+	}
+	return status
+}
+
+func queuedBefore(a, b *QueueEntry) bool {
+	if !a.QueuedAt.Equal(b.QueuedAt) {
+		return a.QueuedAt.Before(b.QueuedAt)
+	}
+	return a.PlayerID < b.PlayerID
+}
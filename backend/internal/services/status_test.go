@@ -0,0 +1,109 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/store"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type recordingNotifier struct {
+	statuses []*services.QueueStatus
+}
+
+func (n *recordingNotifier) NotifyQueue(ctx context.Context, statuses []*services.QueueStatus) {
+	n.statuses = statuses
+}
+
+func TestMatcher_Status(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+	mockPlayerService := mocks.NewMockIPlayer(ctrl)
+	m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+	now := start
+	m.SetClock(func() time.Time { return now })
+
+	queue := func(id int32, rankVal int32, groups ...repository.GroupWithPlayers) {
+		player := queuedPlayer()
+		player.RankVal = rankVal
+		mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(id, nil)
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return(groups, int32(len(groups)), nil)
+		_, err := m.Enqueue(ctx, player, nil)
+		assert.NoError(t, err)
+		now = now.Add(time.Second)
+	}
+	queue(1, 40)
+	queue(2, 20) // Queued in another rank division
+	queue(3, 42)
+
+	t.Run("Should return 404 if player is not queued", func(t *testing.T) {
+		_, err := m.Status(ctx, 4)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, err.(services.Error).Code())
+	})
+
+	t.Run("Should report the player's position among similar players", func(t *testing.T) {
+		status, err := m.Status(ctx, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, status.Position)
+		assert.Equal(t, 1, status.WaitedSeconds)
+		// Nobody similar has been matched yet
+		assert.Nil(t, status.EstimatedWaitSeconds)
+	})
+
+	t.Run("Should estimate the wait from recent matches", func(t *testing.T) {
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), joining("AAAA")).Return(int32(4), nil)
+		queue(4, 41, groupOfSize("AAAA", 3))
+
+		status, err := m.Status(ctx, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, status.Position)
+		// One match over the window
+		estimate := int(2 * services.ThroughputWindow.Seconds())
+		assert.Equal(t, &estimate, status.EstimatedWaitSeconds)
+
+		status, err = m.Status(ctx, 2)
+		assert.NoError(t, err)
+		assert.Nil(t, status.EstimatedWaitSeconds)
+	})
+
+	t.Run("Should forget matches that have fallen out of the window", func(t *testing.T) {
+		now = now.Add(services.ThroughputWindow)
+
+		status, err := m.Status(ctx, 3)
+		assert.NoError(t, err)
+		assert.Nil(t, status.EstimatedWaitSeconds)
+	})
+}
+
+func TestMatcher_Notify(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+	mockPlayerService := mocks.NewMockIPlayer(ctrl)
+	m := services.NewMatcher(mockGroupService, mockPlayerService, mocks.NewMockIParty(ctrl), store.NewMemoryStore())
+	notifier := &recordingNotifier{}
+	m.SetNotifier(notifier)
+
+	mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Any()).Return([]repository.GroupWithPlayers{}, int32(0), nil).AnyTimes()
+	mockPlayerService.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).Return(int32(7), nil)
+	_, err := m.Enqueue(ctx, queuedPlayer(), nil)
+	assert.NoError(t, err)
+
+	t.Run("Should push the status of queued players after matching", func(t *testing.T) {
+		m.MatchQueued(ctx)
+		assert.Len(t, notifier.statuses, 1)
+		assert.Equal(t, int32(7), notifier.statuses[0].PlayerID)
+		assert.Equal(t, 1, notifier.statuses[0].Position)
+	})
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+)
+
+// ThroughputWindow is how far back matches are counted when estimating wait times.
+const ThroughputWindow = 15 * time.Minute
+
+// Throughput counts how many queued players were matched over a rolling
+// window, per bucket of players that are matched alike.
+type Throughput struct {
+	sync.Mutex
+	window  time.Duration
+	matches map[string][]time.Time
+}
+
+func NewThroughput(window time.Duration) *Throughput {
+	return &Throughput{
+		window:  window,
+		matches: make(map[string][]time.Time),
+	}
+}
+
+// Record counts a match in the bucket at the given time. Matches are expected to be recorded in order.
+func (t *Throughput) Record(bucket string, at time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.matches[bucket] = append(t.prune(bucket, at), at)
+}
+
+// Rate returns how many matches the bucket had per second over the window, as of now.
+func (t *Throughput) Rate(bucket string, now time.Time) float64 {
+	t.Lock()
+	defer t.Unlock()
+
+	matches := t.prune(bucket, now)
+	if len(matches) == 0 {
+		delete(t.matches, bucket)
+		return 0
+	}
+	t.matches[bucket] = matches
+	return float64(len(matches)) / t.window.Seconds()
+}
+
+// prune drops the bucket's matches that have fallen out of the window.
+func (t *Throughput) prune(bucket string, now time.Time) []time.Time {
+	matches := t.matches[bucket]
+	cutoff := now.Add(-t.window)
+	i := 0
+	for i < len(matches) && !matches[i].After(cutoff) {
+		i++
+	}
+	return matches[i:]
+}
+
+// throughputBucket groups players by region, gamemode and rank division, e.g. na:competitive:4 for Diamond.
+func throughputBucket(player repository.JoinGroupParams) string {
+	rankVal, _ := player.RankVal.(int32)
+	return fmt.Sprintf("%s:%s:%d", player.Region, player.Gamemode, rankVal/10)
+}
@@ -0,0 +1,521 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/types"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// WaitlistOfferTTL is how long a slot is held for a waitlisted player before it passes down the list.
+const WaitlistOfferTTL = time.Minute
+
+// WaitlistSweepInterval is how often offers that lapsed are looked for.
+const WaitlistSweepInterval = 5 * time.Second
+
+type JoinWaitlistParams struct {
+	Player repository.JoinGroupParams
+	// Role limits the player to slots for that role, instead of any of their preferred roles
+	Role string
+}
+
+// WaitlistEntryDTO is a player waiting for a slot in a group, in the order they'll be offered one.
+type WaitlistEntryDTO struct {
+	GroupID        string     `json:"groupId"`
+	PlayerID       int32      `json:"playerId"`
+	Name           string     `json:"name"`
+	Platform       string     `json:"platform"`
+	Rank           string     `json:"rank"`
+	Characters     []string   `json:"characters"`
+	VoiceChat      bool       `json:"voiceChat"`
+	Mic            bool       `json:"mic"`
+	Position       int        `json:"position"`
+	Role           string     `json:"role,omitempty"`
+	Roles          []string   `json:"roles"`
+	OfferedRole    string     `json:"offeredRole,omitempty"`
+	OfferExpiresAt *time.Time `json:"offerExpiresAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+
+	ID int32 `json:"-"`
+	// SessionID is the session the player waited from, which is recorded on their membership once they accept.
+	SessionID string `json:"-"`
+}
+
+func toWaitlistEntryDTO(row repository.GetWaitlistRow, position int) WaitlistEntryDTO {
+	entry := WaitlistEntryDTO{
+		GroupID:     row.GroupID,
+		PlayerID:    row.PlayerID,
+		Name:        row.Name,
+		Platform:    row.Platform,
+		Rank:        types.RankValToRankID[int(row.Rank)],
+		Characters:  row.Characters,
+		VoiceChat:   row.VoiceChat,
+		Mic:         row.Mic,
+		Position:    position,
+		Role:        row.Role.String,
+		Roles:       row.Roles,
+		OfferedRole: row.OfferedRole.String,
+		CreatedAt:   row.CreatedAt,
+		ID:          row.ID,
+		SessionID:   row.SessionID.String,
+	}
+	if row.OfferExpiresAt.Valid {
+		entry.OfferExpiresAt = &row.OfferExpiresAt.Time
+	}
+	return entry
+}
+
+// Offered is whether a slot is still being held for the player.
+func (e WaitlistEntryDTO) Offered(now time.Time) bool {
+	return e.OfferExpiresAt != nil && e.OfferExpiresAt.After(now)
+}
+
+// seatingRoles returns the roles the player can be offered, in order of preference.
+func (e WaitlistEntryDTO) seatingRoles() []string {
+	if e.Role != "" {
+		return []string{e.Role}
+	}
+	return e.Roles
+}
+
+// asPlayer describes the waiting player as joining the group in one of roles.
+func (e WaitlistEntryDTO) asPlayer(group *repository.GroupWithPlayers, roles []string) repository.JoinGroupParams {
+	player := repository.JoinGroupParams{
+		GroupID:    group.ID,
+		PlayerID:   e.PlayerID,
+		Gamemode:   group.Gamemode,
+		Region:     group.Region,
+		Platform:   e.Platform,
+		Roles:      roles,
+		RankVal:    int32(types.RankIDToRankVal[e.Rank]),
+		Characters: e.Characters,
+		VoiceChat:  e.VoiceChat,
+		Mic:        e.Mic,
+	}
+	if len(roles) > 0 {
+		player.Role = roles[0]
+	}
+	return player
+}
+
+// HoldOffers returns the group as if the players holding offers had been
+// seated, so that their slots aren't offered twice. The player with
+// exceptPlayerID is left out, since they're the one taking their slot.
+func HoldOffers(group *repository.GroupWithPlayers, waitlist []WaitlistEntryDTO, now time.Time, exceptPlayerID int32) *repository.GroupWithPlayers {
+	held := *group
+	held.Players = slices.Clone(group.Players)
+	for _, entry := range waitlist {
+		if entry.PlayerID == exceptPlayerID || !entry.Offered(now) {
+			continue
+		}
+		held.Players = append(held.Players, repository.PlayerInGroup{
+			ID:         int(entry.PlayerID),
+			Name:       entry.Name,
+			Platform:   entry.Platform,
+			Role:       entry.OfferedRole,
+			Rank:       entry.Rank,
+			Characters: entry.Characters,
+			VoiceChat:  entry.VoiceChat,
+			Mic:        entry.Mic,
+		})
+		held.Size++
+	}
+	return &held
+}
+
+// JoinWaitlist puts the player at the back of the group's waitlist, for any of
+// their preferred roles or only the one they asked for. Players have to meet
+// the group's requirements other than its open roles, and private groups need
+// the passcode. Groups with room for the player turn them away, since they
+// can join it directly.
+func (s *Group) JoinWaitlist(ctx context.Context, arg JoinWaitlistParams) (*WaitlistEntryDTO, error) {
+	player := arg.Player
+	var entry *WaitlistEntryDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		group, err := lockGroup(ctx, q, player.GroupID)
+		if err != nil {
+			return err
+		}
+		if err := requireStarted(group); err != nil {
+			return err
+		}
+		if !group.Open && group.Passcode != player.Passcode {
+			return NewError(http.StatusForbidden, "Access denied.", nil)
+		}
+
+		if player.PlayerID != 0 {
+			if _, err := q.GetPlayerGroup(ctx, player.PlayerID); err != pgx.ErrNoRows {
+				if err != nil {
+					return err
+				}
+				return NewError(http.StatusBadRequest, "Player is already in a group.", nil)
+			}
+		}
+
+		banned, err := q.IsPlayerBanned(ctx, repository.IsPlayerBannedParams{
+			GroupID:   group.ID,
+			PlayerID:  player.PlayerID,
+			SessionID: player.SessionID,
+		})
+		if err != nil {
+			return err
+		}
+		if banned {
+			return NewError(http.StatusForbidden, "Player is banned from the group.", nil)
+		}
+
+		// The role requirement is left out, since waiting for one to open up is the point
+		eligibility := CheckEligibility(group, player)
+		eligibility.Eligible = true
+		for i, requirement := range eligibility.Requirements {
+			if requirement.Name == RequirementRole {
+				eligibility.Requirements[i].Met = true
+				continue
+			}
+			eligibility.Eligible = eligibility.Eligible && requirement.Met
+		}
+		if !eligibility.Eligible {
+			return newRequirementsError(eligibility)
+		}
+
+		roles := rolePreferences(player)
+		if arg.Role != "" {
+			if rq := group.RoleQueue; rq != nil && rq.Vanguards+rq.Duelists+rq.Strategists > 0 {
+				slots := map[string]int{"vanguard": rq.Vanguards, "duelist": rq.Duelists, "strategist": rq.Strategists}
+				if slots[arg.Role] == 0 {
+					return NewError(http.StatusBadRequest, fmt.Sprintf("Group has no %s slots.", arg.Role), nil)
+				}
+			}
+			roles = []string{arg.Role}
+		}
+
+		waitlist, err := getWaitlist(ctx, q, group.ID)
+		if err != nil {
+			return err
+		}
+		held := HoldOffers(group, waitlist, time.Now(), 0)
+		if OpenSlots(*held) > 0 && SeatRole(held, roles) != "" {
+			return NewError(http.StatusBadRequest, "Group has room, so the player can join it directly.", nil)
+		}
+
+		playerID, err := q.UpsertPlayer(ctx, toUpsertPlayerParams(player))
+		if err != nil {
+			return err
+		}
+
+		created, err := q.AddToWaitlist(ctx, repository.AddToWaitlistParams{
+			GroupID:   group.ID,
+			PlayerID:  playerID,
+			Role:      arg.Role,
+			Roles:     rolePreferences(player),
+			SessionID: player.SessionID,
+		})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return NewError(http.StatusBadRequest, "Player is already on the group's waitlist.", nil)
+			}
+			return err
+		}
+
+		rankVal, _ := player.RankVal.(int32)
+		entry = &WaitlistEntryDTO{
+			GroupID:    group.ID,
+			PlayerID:   playerID,
+			Name:       player.Name,
+			Platform:   player.Platform,
+			Rank:       types.RankValToRankID[int(rankVal)],
+			Characters: player.Characters,
+			VoiceChat:  player.VoiceChat,
+			Mic:        player.Mic,
+			Position:   len(waitlist) + 1,
+			Role:       arg.Role,
+			Roles:      created.Roles,
+			CreatedAt:  created.CreatedAt,
+			ID:         created.ID,
+			SessionID:  player.SessionID,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetWaitlist returns the players waiting on the group, in the order they'll be offered a slot.
+func (s *Group) GetWaitlist(ctx context.Context, groupID string) ([]WaitlistEntryDTO, error) {
+	return getWaitlist(ctx, s.repo, groupID)
+}
+
+// LeaveWaitlist takes the player off the group's waitlist. If a slot was being
+// held for them, it's offered to the next player down the list.
+func (s *Group) LeaveWaitlist(ctx context.Context, groupID string, playerID int32) error {
+	offerExpiresAt, err := s.repo.RemoveFromWaitlist(ctx, repository.RemoveFromWaitlistParams{
+		GroupID:  groupID,
+		PlayerID: playerID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return NewError(http.StatusNotFound, "Player is not on the group's waitlist.", nil)
+		}
+		return err
+	}
+	if offerExpiresAt.Valid && offerExpiresAt.Time.After(time.Now()) {
+		return s.OfferOpenSlots(ctx, groupID)
+	}
+	return nil
+}
+
+// AcceptWaitlistOffer seats the player in the slot that's being held for them,
+// as long as they still meet the group's requirements, and takes them off the waitlist.
+func (s *Group) AcceptWaitlistOffer(ctx context.Context, groupID string, playerID int32) (*WaitlistEntryDTO, error) {
+	var entry WaitlistEntryDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		group, err := lockGroup(ctx, q, groupID)
+		if err != nil {
+			return err
+		}
+
+		waitlist, err := getWaitlist(ctx, q, groupID)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(waitlist, func(e WaitlistEntryDTO) bool {
+			return e.PlayerID == playerID
+		})
+		if i < 0 {
+			return NewError(http.StatusNotFound, "Player is not on the group's waitlist.", nil)
+		}
+		entry = waitlist[i]
+
+		now := time.Now()
+		if !entry.Offered(now) {
+			return NewError(http.StatusBadRequest, "No slot is being held for the player.", nil)
+		}
+		if _, err := q.GetPlayerGroup(ctx, playerID); err != pgx.ErrNoRows {
+			if err != nil {
+				return err
+			}
+			return NewError(http.StatusBadRequest, "Player is already in a group.", nil)
+		}
+
+		held := HoldOffers(group, waitlist, now, playerID)
+		roles := append([]string{entry.OfferedRole}, entry.seatingRoles()...)
+		eligibility := CheckEligibility(held, entry.asPlayer(held, roles))
+		if !eligibility.Eligible {
+			return newRequirementsError(eligibility)
+		}
+		if OpenSlots(*held) <= 0 {
+			return NewError(http.StatusBadRequest, "Group is full.", nil)
+		}
+
+		if err := q.AddGroupMember(ctx, repository.AddGroupMemberParams{
+			GroupID:   group.ID,
+			PlayerID:  playerID,
+			Leader:    false,
+			Role:      SeatRole(held, roles),
+			SessionID: entry.SessionID,
+		}); err != nil {
+			return err
+		}
+		if _, err := q.RemoveFromWaitlist(ctx, repository.RemoveFromWaitlistParams{
+			GroupID:  groupID,
+			PlayerID: playerID,
+		}); err != nil {
+			return err
+		}
+		return q.TouchGroup(ctx, group.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// OfferOpenSlots holds the group's open slots for the players nearest the
+// front of its waitlist that fit them. Offers that lapsed are cleared first,
+// so that their slots pass down the list. Each player offered a slot is told
+// over the websocket, and has WaitlistOfferTTL to accept it.
+func (s *Group) OfferOpenSlots(ctx context.Context, groupID string) error {
+	var offers []WaitlistEntryDTO
+	err := s.repo.ExecTx(ctx, func(q *repository.Queries) error {
+		group, err := lockGroup(ctx, q, groupID)
+		if err != nil {
+			return err
+		}
+
+		if err := q.ClearLapsedOffers(ctx, groupID); err != nil {
+			return err
+		}
+		waitlist, err := getWaitlist(ctx, q, groupID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		held := HoldOffers(group, waitlist, now, 0)
+		for _, entry := range waitlist {
+			if OpenSlots(*held) <= 0 {
+				break
+			}
+			if entry.Offered(now) {
+				continue
+			}
+
+			// Players who found another group or were banned since they joined the waitlist are passed over
+			if _, err := q.GetPlayerGroup(ctx, entry.PlayerID); err != pgx.ErrNoRows {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			banned, err := q.IsPlayerBanned(ctx, repository.IsPlayerBannedParams{
+				GroupID:   groupID,
+				PlayerID:  entry.PlayerID,
+				SessionID: entry.SessionID,
+			})
+			if err != nil {
+				return err
+			}
+			if banned {
+				continue
+			}
+
+			roles := entry.seatingRoles()
+			if !CheckEligibility(held, entry.asPlayer(held, roles)).Eligible {
+				continue
+			}
+
+			expiresAt := now.Add(WaitlistOfferTTL)
+			entry.OfferedRole = SeatRole(held, roles)
+			entry.OfferExpiresAt = &expiresAt
+			if err := q.OfferWaitlistSlot(ctx, repository.OfferWaitlistSlotParams{
+				OfferedRole:    pgtype.Text{String: entry.OfferedRole, Valid: true},
+				OfferExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+				ID:             entry.ID,
+			}); err != nil {
+				return err
+			}
+			held = HoldOffers(held, []WaitlistEntryDTO{entry}, now, 0)
+			offers = append(offers, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notifier != nil {
+		for i := range offers {
+			s.notifier.WaitlistOffered(ctx, &offers[i])
+		}
+	}
+	return nil
+}
+
+// offerOpenSlots offers the slots that a change freed up to the group's waitlist. Failing to do so doesn't fail the change.
+func (s *Group) offerOpenSlots(ctx context.Context, groupID string) {
+	if err := s.OfferOpenSlots(ctx, groupID); err != nil {
+		log.Error(ctx, fmt.Sprintf("unable to offer open slots in group %s: %v", groupID, err))
+	}
+}
+
+// GetLapsedOfferGroups returns the groups with slots that a waitlisted player didn't accept in time.
+func (s *Group) GetLapsedOfferGroups(ctx context.Context) ([]string, error) {
+	return s.repo.GetLapsedOfferGroups(ctx)
+}
+
+// lockGroup locks the group for the rest of the transaction, and returns it.
+func lockGroup(ctx context.Context, q *repository.Queries, groupID string) (*repository.GroupWithPlayers, error) {
+	if _, err := q.LockGroup(ctx, groupID); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, NewError(http.StatusNotFound, "Group not found.", nil)
+		}
+		return nil, err
+	}
+
+	group, err := q.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, NewError(http.StatusNotFound, "Group not found.", nil)
+	}
+	return group, nil
+}
+
+// lockOwnedGroup locks the group for the rest of the transaction, and returns
+// it as long as the player owns it. Tokens outlive ownership, so the owner is
+// checked against the group rather than trusted.
+func lockOwnedGroup(ctx context.Context, q *repository.Queries, groupID string, ownerID int32) (*repository.GroupWithPlayers, error) {
+	group, err := lockGroup(ctx, q, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.OwnerID != ownerID {
+		return nil, NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil)
+	}
+	return group, nil
+}
+
+func getWaitlist(ctx context.Context, q *repository.Queries, groupID string) ([]WaitlistEntryDTO, error) {
+	rows, err := q.GetWaitlist(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]WaitlistEntryDTO, 0, len(rows))
+	for i, row := range rows {
+		result = append(result, toWaitlistEntryDTO(row, i+1))
+	}
+	return result, nil
+}
+
+// WaitlistSweeper passes the slots that waitlisted players didn't accept in time down the list.
+type WaitlistSweeper struct {
+	groups IGroup
+}
+
+func NewWaitlistSweeper(groups IGroup) *WaitlistSweeper {
+	return &WaitlistSweeper{
+		groups: groups,
+	}
+}
+
+// Run sweeps lapsed offers every WaitlistSweepInterval until the context is done.
+func (w *WaitlistSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(WaitlistSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Sweep(ctx); err != nil {
+				log.Error(ctx, fmt.Sprintf("unable to sweep waitlist offers: %v", err))
+			}
+		}
+	}
+}
+
+// Sweep offers the slots that lapsed to the next players on each group's waitlist.
+func (w *WaitlistSweeper) Sweep(ctx context.Context) error {
+	groupIDs, err := w.groups.GetLapsedOfferGroups(ctx)
+	if err != nil {
+		return err
+	}
+	for _, groupID := range groupIDs {
+		if err := w.groups.OfferOpenSlots(ctx, groupID); err != nil {
+			log.Error(ctx, fmt.Sprintf("unable to offer open slots in group %s: %v", groupID, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHoldOffers(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	held, lapsed := now.Add(time.Minute), now.Add(-time.Second)
+	waitlist := []services.WaitlistEntryDTO{
+		{PlayerID: 4, Rank: "d3", OfferedRole: "vanguard", OfferExpiresAt: &held},
+		{PlayerID: 5, Rank: "d3", OfferedRole: "duelist", OfferExpiresAt: &lapsed},
+		{PlayerID: 6, Rank: "d3"},
+	}
+
+	t.Run("Should take the slots being held, but not the ones that lapsed", func(t *testing.T) {
+		group := memberedGroup()
+		result := services.HoldOffers(group, waitlist, now, 0)
+
+		assert.Equal(t, group.Size+1, result.Size)
+		assert.Len(t, result.Players, len(group.Players)+1)
+		assert.Equal(t, "vanguard", result.Players[len(result.Players)-1].Role)
+		assert.Equal(t, services.OpenSlots(*group)-1, services.OpenSlots(*result))
+	})
+
+	t.Run("Should leave out the player taking their slot", func(t *testing.T) {
+		group := memberedGroup()
+		result := services.HoldOffers(group, waitlist, now, 4)
+
+		assert.Equal(t, group.Size, result.Size)
+		assert.Len(t, result.Players, len(group.Players))
+	})
+
+	t.Run("Should not change the group it was given", func(t *testing.T) {
+		group := memberedGroup()
+		size, players := group.Size, len(group.Players)
+		services.HoldOffers(group, waitlist, now, 0)
+
+		assert.Equal(t, size, group.Size)
+		assert.Len(t, group.Players, players)
+	})
+}
+
+func TestWaitlistSweeper_Sweep(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should offer lapsed slots to the next players in each group", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockGroupService.EXPECT().GetLapsedOfferGroups(gomock.Any()).Return([]string{"AAAA", "BBBB"}, nil)
+		mockGroupService.EXPECT().OfferOpenSlots(gomock.Any(), "AAAA").Return(fmt.Errorf("unexpected error"))
+		mockGroupService.EXPECT().OfferOpenSlots(gomock.Any(), "BBBB").Return(nil)
+
+		assert.NoError(t, services.NewWaitlistSweeper(mockGroupService).Sweep(ctx))
+	})
+
+	t.Run("Should return an error if the groups can't be found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGroupService := mocks.NewMockIGroup(ctrl)
+		mockGroupService.EXPECT().GetLapsedOfferGroups(gomock.Any()).Return(nil, fmt.Errorf("unexpected error"))
+
+		assert.Error(t, services.NewWaitlistSweeper(mockGroupService).Sweep(ctx))
+	})
+}
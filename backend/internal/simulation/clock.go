@@ -0,0 +1,31 @@
+package simulation
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a simulated clock that only moves when it's told to.
+type Clock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+func (c *Clock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+// AdvanceTo moves the clock forward to t. The clock never moves backwards.
+func (c *Clock) AdvanceTo(t time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+}
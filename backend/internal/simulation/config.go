@@ -0,0 +1,192 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// Duration is a time.Duration that is written as a string in config files, e.g. "90s" or "2h".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Weights maps each option to its relative likelihood of being picked. They don't need to add up to 1.
+type Weights map[string]float64
+
+// Config describes the players that arrive during a simulation, and how long it runs for.
+type Config struct {
+	// Seed makes runs reproducible. Runs with the same config and seed produce the same report.
+	Seed int64 `json:"seed"`
+	// Duration is how long players keep arriving for, in simulated time.
+	Duration Duration `json:"duration"`
+	// MatchInterval is how often queued players are retried, like the matcher's background loop.
+	MatchInterval Duration `json:"matchInterval"`
+	// ArrivalsPerMinute is the average rate that players join the queue at.
+	ArrivalsPerMinute float64 `json:"arrivalsPerMinute"`
+	// Patience is how long players wait in the queue before giving up.
+	Patience Duration `json:"patience"`
+
+	Gamemodes Weights `json:"gamemodes"`
+	Regions   Weights `json:"regions"`
+	Platforms Weights `json:"platforms"`
+	Roles     Weights `json:"roles"`
+	Ranks     Weights `json:"ranks"`
+
+	// FlexRate is the chance that a player lists every role, in order of preference.
+	FlexRate float64 `json:"flexRate"`
+	// VoiceChatRate and MicRate are the chances that a player has voice chat and a mic.
+	VoiceChatRate float64 `json:"voiceChatRate"`
+	MicRate       float64 `json:"micRate"`
+
+	// Relaxation is the schedule that every player queues with. The matcher's default is used if empty.
+	Relaxation []RelaxationStep `json:"relaxation"`
+}
+
+// RelaxationStep applies its constraints once a player has waited for After.
+// Players without voice chat never require it.
+type RelaxationStep struct {
+	After            Duration `json:"after"`
+	MaxRankSpread    int      `json:"maxRankSpread"`
+	RequireVoiceChat bool     `json:"requireVoiceChat"`
+}
+
+// schedule is the relaxation schedule for a player, which is nil if the matcher's default should be used.
+func (c Config) schedule(voiceChat bool) services.RelaxationSchedule {
+	if len(c.Relaxation) == 0 {
+		return nil
+	}
+
+	schedule := make(services.RelaxationSchedule, 0, len(c.Relaxation))
+	for _, step := range c.Relaxation {
+		schedule = append(schedule, services.RelaxationStep{
+			After: time.Duration(step.After),
+			Constraints: services.Constraints{
+				MaxRankSpread:    step.MaxRankSpread,
+				RequireVoiceChat: step.RequireVoiceChat && voiceChat,
+			},
+		})
+	}
+	return schedule
+}
+
+// DefaultConfig is a busy hour of competitive and quickplay, with ranks skewed towards the middle.
+func DefaultConfig() Config {
+	return Config{
+		Seed:              1,
+		Duration:          Duration(time.Hour),
+		MatchInterval:     Duration(services.MatchInterval),
+		ArrivalsPerMinute: 20,
+		Patience:          Duration(10 * time.Minute),
+		Gamemodes:         Weights{"competitive": 0.7, "quickplay": 0.3},
+		Regions:           Weights{"na": 0.4, "eu": 0.35, "ap": 0.2, "sa": 0.05},
+		Platforms:         Weights{"pc": 0.8, "co": 0.2},
+		Roles:             Weights{"vanguard": 0.25, "duelist": 0.5, "strategist": 0.25},
+		Ranks: Weights{
+			"b3": 2, "b2": 2, "b1": 2,
+			"s3": 4, "s2": 4, "s1": 4,
+			"g3": 6, "g2": 6, "g1": 6,
+			"p3": 7, "p2": 7, "p1": 7,
+			"d3": 6, "d2": 6, "d1": 6,
+			"gm3": 4, "gm2": 4, "gm1": 4,
+			"c3": 2, "c2": 2, "c1": 2,
+			"e": 1, "oa": 0.5,
+		},
+		FlexRate:      0.2,
+		VoiceChatRate: 0.6,
+		MicRate:       0.5,
+	}
+}
+
+// LoadConfig reads a config from a JSON file. Fields that are left out keep their DefaultConfig value.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+	return cfg, cfg.Validate()
+}
+
+func (c Config) Validate() error {
+	if c.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	if c.MatchInterval <= 0 {
+		return fmt.Errorf("matchInterval must be positive")
+	}
+	if c.ArrivalsPerMinute <= 0 {
+		return fmt.Errorf("arrivalsPerMinute must be positive")
+	}
+	if c.Patience <= 0 {
+		return fmt.Errorf("patience must be positive")
+	}
+
+	weights := []struct {
+		name     string
+		weights  Weights
+		validate func(string) error
+	}{
+		{"gamemodes", c.Gamemodes, types.ValidateGamemode},
+		{"regions", c.Regions, types.ValidateRegion},
+		{"platforms", c.Platforms, types.ValidatePlatform},
+		{"roles", c.Roles, types.ValidateRole},
+		{"ranks", c.Ranks, func(rankID string) error {
+			if !types.IsValidRankID(rankID) {
+				return fmt.Errorf("rankId %s is invalid", rankID)
+			}
+			return nil
+		}},
+	}
+	for _, w := range weights {
+		total := 0.0
+		for option, weight := range w.weights {
+			if err := w.validate(option); err != nil {
+				return fmt.Errorf("%s: %w", w.name, err)
+			}
+			if weight < 0 {
+				return fmt.Errorf("%s: weight of %s must not be negative", w.name, option)
+			}
+			total += weight
+		}
+		if total <= 0 {
+			return fmt.Errorf("%s: at least one option needs a positive weight", w.name)
+		}
+	}
+
+	for name, rate := range map[string]float64{"flexRate": c.FlexRate, "voiceChatRate": c.VoiceChatRate, "micRate": c.MicRate} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("%s must be between 0 and 1", name)
+		}
+	}
+
+	if schedule := c.schedule(true); schedule != nil {
+		if err := schedule.Validate(); err != nil {
+			return fmt.Errorf("relaxation: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package simulation
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// Width of each bucket of the rank spread histogram, in rank values. A
+// division is 10 apart, so this splits each division into its tiers.
+const spreadBucketWidth = 5
+
+type Percentiles struct {
+	P50 Duration `json:"p50"`
+	P90 Duration `json:"p90"`
+	P99 Duration `json:"p99"`
+	Max Duration `json:"max"`
+}
+
+// SpreadBucket counts the groups whose rank spread is within [Min, Max].
+type SpreadBucket struct {
+	Min    int `json:"min"`
+	Max    int `json:"max"`
+	Groups int `json:"groups"`
+}
+
+type Report struct {
+	Arrivals  int `json:"arrivals"`
+	Grouped   int `json:"grouped"`
+	Abandoned int `json:"abandoned"`
+	// TimeToGroup is how long players that found a group waited for it.
+	TimeToGroup Percentiles `json:"timeToGroup"`
+
+	Groups       int `json:"groups"`
+	FilledGroups int `json:"filledGroups"`
+	// FillRate is the share of groups that filled up.
+	FillRate float64 `json:"fillRate"`
+	// AverageFill is the average share of seats that were taken, including groups that never filled up.
+	AverageFill float64 `json:"averageFill"`
+	// RankSpread is a histogram of the difference between the highest and lowest rank values in each group.
+	RankSpread []SpreadBucket `json:"rankSpread"`
+}
+
+// Write prints the report as a human readable summary.
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Players\t%d arrived\t%d grouped (%.1f%%)\t%d abandoned (%.1f%%)\n",
+		r.Arrivals, r.Grouped, percent(r.Grouped, r.Arrivals), r.Abandoned, percent(r.Abandoned, r.Arrivals))
+	fmt.Fprintf(tw, "Time to group\tp50 %s\tp90 %s\tp99 %s\tmax %s\n",
+		round(r.TimeToGroup.P50), round(r.TimeToGroup.P90), round(r.TimeToGroup.P99), round(r.TimeToGroup.Max))
+	fmt.Fprintf(tw, "Groups\t%d formed\t%d filled (%.1f%%)\t%.1f%% of seats taken\n",
+		r.Groups, r.FilledGroups, r.FillRate*100, r.AverageFill*100)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "\nRank spread")
+	most := 0
+	for _, bucket := range r.RankSpread {
+		most = max(most, bucket.Groups)
+	}
+	for _, bucket := range r.RankSpread {
+		bar := 0
+		if most > 0 {
+			bar = bucket.Groups * 40 / most
+		}
+		fmt.Fprintf(tw, "%d-%d\t%d\t%s\n", bucket.Min, bucket.Max, bucket.Groups, strings.Repeat("#", bar))
+	}
+	return tw.Flush()
+}
+
+type stats struct {
+	arrivals  int
+	abandoned int
+	waits     []time.Duration
+
+	groups       int
+	filledGroups int
+	seatsTaken   int
+	seats        int
+	spreads      []int
+}
+
+func newStats() *stats {
+	return &stats{}
+}
+
+func (s *stats) addWait(wait time.Duration) {
+	s.waits = append(s.waits, wait)
+}
+
+func (s *stats) addGroup(group repository.GroupWithPlayers, filled bool) {
+	s.groups++
+	if filled {
+		s.filledGroups++
+	}
+	s.seatsTaken += group.Size
+	s.seats += group.Size + services.OpenSlots(group)
+
+	lowest, highest := types.RankIDToRankVal["oa"], types.RankIDToRankVal["b3"]
+	for _, player := range group.Players {
+		rankVal := types.RankIDToRankVal[player.Rank]
+		lowest, highest = min(lowest, rankVal), max(highest, rankVal)
+	}
+	s.spreads = append(s.spreads, max(highest-lowest, 0))
+}
+
+func (s *stats) report() *Report {
+	report := &Report{
+		Arrivals:     s.arrivals,
+		Grouped:      len(s.waits),
+		Abandoned:    s.abandoned,
+		TimeToGroup:  percentiles(s.waits),
+		Groups:       s.groups,
+		FilledGroups: s.filledGroups,
+		RankSpread:   histogram(s.spreads),
+	}
+	if s.groups > 0 {
+		report.FillRate = float64(s.filledGroups) / float64(s.groups)
+	}
+	if s.seats > 0 {
+		report.AverageFill = float64(s.seatsTaken) / float64(s.seats)
+	}
+	return report
+}
+
+// percentiles uses the nearest-rank method.
+func percentiles(waits []time.Duration) Percentiles {
+	if len(waits) == 0 {
+		return Percentiles{}
+	}
+
+	sorted := append([]time.Duration(nil), waits...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	at := func(p float64) Duration {
+		rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return Duration(sorted[min(max(rank, 0), len(sorted)-1)])
+	}
+	return Percentiles{
+		P50: at(0.5),
+		P90: at(0.9),
+		P99: at(0.99),
+		Max: Duration(sorted[len(sorted)-1]),
+	}
+}
+
+// histogram buckets the spreads, up to the bucket of the widest one.
+func histogram(spreads []int) []SpreadBucket {
+	widest := 0
+	for _, spread := range spreads {
+		widest = max(widest, spread)
+	}
+
+	buckets := make([]SpreadBucket, widest/spreadBucketWidth+1)
+	for i := range buckets {
+		buckets[i].Min = i * spreadBucketWidth
+		buckets[i].Max = buckets[i].Min + spreadBucketWidth - 1
+	}
+	for _, spread := range spreads {
+		buckets[spread/spreadBucketWidth].Groups++
+	}
+	return buckets
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total) * 100
+}
+
+func round(d Duration) time.Duration {
+	return time.Duration(d).Round(time.Second)
+}
@@ -0,0 +1,284 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// Repository is an in-memory stand-in for the parts of the group and player
+// services that the matcher uses. Joins go through the same eligibility checks
+// and role seating as the real join, so that the matcher behaves as it would
+// against the database.
+type Repository struct {
+	sync.Mutex
+	clock *Clock
+
+	players  map[int32]repository.UpsertPlayerParams
+	groups   map[string]*repository.GroupWithPlayers
+	members  map[int32]string
+	joinedAt map[int32]time.Time
+
+	lastPlayerID int32
+	groupCount   int
+}
+
+func NewRepository(clock *Clock) *Repository {
+	return &Repository{
+		clock:    clock,
+		players:  make(map[int32]repository.UpsertPlayerParams),
+		groups:   make(map[string]*repository.GroupWithPlayers),
+		members:  make(map[int32]string),
+		joinedAt: make(map[int32]time.Time),
+	}
+}
+
+func (r *Repository) UpsertPlayer(ctx context.Context, arg repository.UpsertPlayerParams) (int32, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if arg.ID == 0 {
+		r.lastPlayerID++
+		arg.ID = r.lastPlayerID
+	}
+	r.players[arg.ID] = arg
+	return arg.ID, nil
+}
+
+func (r *Repository) CreateGroup(ctx context.Context, arg repository.CreateGroupParams) (repository.CreateGroupRow, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	playerID, _ := arg.PlayerID.(int32)
+	if _, ok := r.members[playerID]; ok {
+		return repository.CreateGroupRow{}, services.NewError(http.StatusBadRequest, "Player is already in a group.", nil)
+	}
+
+	r.groupCount++
+	group := &repository.GroupWithPlayers{
+		GroupDTO: repository.GroupDTO{
+			ID:       groupID(r.groupCount),
+			OwnerID:  playerID,
+			Owner:    arg.Owner,
+			Region:   arg.Region,
+			Gamemode: arg.Gamemode,
+			Open:     arg.Open,
+			RoleQueue: &repository.RoleQueue{
+				Vanguards:   int(arg.Vanguards),
+				Duelists:    int(arg.Duelists),
+				Strategists: int(arg.Strategists),
+			},
+			GroupSettings: &repository.GroupSettings{
+				Platform:  arg.Platform,
+				VoiceChat: arg.GroupVoiceChat.Bool,
+				Mic:       arg.GroupMic.Bool,
+			},
+		},
+		Name: fmt.Sprintf("%s's Group", arg.Owner),
+	}
+	r.groups[group.ID] = group
+	r.seat(group, repository.PlayerInGroup{
+		ID:         int(playerID),
+		Name:       arg.Owner,
+		Leader:     true,
+		Platform:   arg.Platform,
+		Role:       strings.ToLower(arg.Role),
+		Rank:       types.RankValToRankID[int(arg.RankVal)],
+		Characters: arg.Characters,
+		VoiceChat:  arg.VoiceChat,
+		Mic:        arg.Mic,
+	})
+
+	return repository.CreateGroupRow{
+		GroupID:  group.ID,
+		PlayerID: playerID,
+	}, nil
+}
+
+// GetGroups supports the filters and sorting that the matcher uses. Player
+// requirements are only checked if a platform is given, since the matcher
+// always searches with the player's full info.
+func (r *Repository) GetGroups(ctx context.Context, arg repository.GetGroupsParams) ([]repository.GroupWithPlayers, int32, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	groups := make([]repository.GroupWithPlayers, 0)
+	for _, group := range r.groups {
+		if arg.RegionFilter != "" && group.Region != arg.RegionFilter {
+			continue
+		}
+		if arg.GamemodeFilter != "" && group.Gamemode != arg.GamemodeFilter {
+			continue
+		}
+		if arg.OpenFilter != "" && strconv.FormatBool(group.Open) != arg.OpenFilter {
+			continue
+		}
+		if arg.Platform != nil && !services.CheckEligibility(group, playerFromRequirements(group, arg)).Eligible {
+			continue
+		}
+		groups = append(groups, clone(group))
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Size != groups[j].Size {
+			if arg.SizeSort == "desc" {
+				return groups[i].Size > groups[j].Size
+			}
+			return groups[i].Size < groups[j].Size
+		}
+		return groups[i].ID < groups[j].ID
+	})
+
+	total := int32(len(groups))
+	if arg.Offset > 0 {
+		groups = groups[min(arg.Offset, len(groups)):]
+	}
+	if arg.Limit > 0 && len(groups) > arg.Limit {
+		groups = groups[:arg.Limit]
+	}
+	return groups, total, nil
+}
+
+func (r *Repository) JoinGroup(ctx context.Context, arg repository.JoinGroupParams) (int32, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	group, ok := r.groups[arg.GroupID]
+	if !ok {
+		return 0, services.NewError(http.StatusNotFound, "Group not found.", nil)
+	}
+	if _, ok := r.members[arg.PlayerID]; ok {
+		return 0, services.NewError(http.StatusBadRequest, "Player is already in a group.", nil)
+	}
+	if !group.Open && group.Passcode != arg.Passcode {
+		return 0, services.NewError(http.StatusForbidden, "Access denied.", nil)
+	}
+	if services.OpenSlots(*group) <= 0 {
+		return 0, services.NewError(http.StatusBadRequest, "Group is full.", nil)
+	}
+	if !services.CheckEligibility(group, arg).Eligible {
+		return 0, services.NewError(http.StatusBadRequest, "Group requirements not met.", nil)
+	}
+
+	role, _ := arg.Role.(string)
+	rankVal, _ := arg.RankVal.(int32)
+	r.seat(group, repository.PlayerInGroup{
+		ID:         int(arg.PlayerID),
+		Name:       arg.Name,
+		Platform:   arg.Platform,
+		Role:       services.SeatRole(group, types.RolePreferences(role, arg.Roles)),
+		Rank:       types.RankValToRankID[int(rankVal)],
+		Characters: arg.Characters,
+		VoiceChat:  arg.VoiceChat,
+		Mic:        arg.Mic,
+	})
+	return arg.PlayerID, nil
+}
+
+// GroupOf returns the group that the player is in, and when they joined it.
+func (r *Repository) GroupOf(playerID int32) (string, time.Time, bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	groupID, ok := r.members[playerID]
+	return groupID, r.joinedAt[playerID], ok
+}
+
+// TakeFullGroups removes and returns the groups that have filled up, as they'd go on to play together.
+func (r *Repository) TakeFullGroups() []repository.GroupWithPlayers {
+	r.Lock()
+	defer r.Unlock()
+
+	full := make([]repository.GroupWithPlayers, 0)
+	for id, group := range r.groups {
+		if services.OpenSlots(*group) > 0 {
+			continue
+		}
+		full = append(full, clone(group))
+		for _, player := range group.Players {
+			delete(r.members, int32(player.ID))
+			delete(r.joinedAt, int32(player.ID))
+		}
+		delete(r.groups, id)
+	}
+	sort.Slice(full, func(i, j int) bool {
+		return full[i].ID < full[j].ID
+	})
+	return full
+}
+
+// Groups returns every group that is still open, ordered by ID.
+func (r *Repository) Groups() []repository.GroupWithPlayers {
+	r.Lock()
+	defer r.Unlock()
+
+	groups := make([]repository.GroupWithPlayers, 0, len(r.groups))
+	for _, group := range r.groups {
+		groups = append(groups, clone(group))
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].ID < groups[j].ID
+	})
+	return groups
+}
+
+// seat adds the player to the group. The caller must hold the lock.
+func (r *Repository) seat(group *repository.GroupWithPlayers, player repository.PlayerInGroup) {
+	now := r.clock.Now()
+	group.Players = append(group.Players, player)
+	group.Size = len(group.Players)
+	group.SetSlots()
+	group.LastActiveAt = now
+	r.members[int32(player.ID)] = group.ID
+	r.joinedAt[int32(player.ID)] = now
+}
+
+// playerFromRequirements describes the player that the matcher is searching for groups on behalf of.
+func playerFromRequirements(group *repository.GroupWithPlayers, arg repository.GetGroupsParams) repository.JoinGroupParams {
+	player := repository.JoinGroupParams{
+		Gamemode: group.Gamemode,
+		Region:   group.Region,
+		Platform: *arg.Platform,
+		Roles:    arg.Roles,
+		// Only set when the ranks were narrowed down or widened for this search
+		AllowedRanks: arg.AllowedRanks[group.Gamemode],
+	}
+	if arg.Role != nil {
+		player.Role = *arg.Role
+	}
+	if arg.RankVal != nil {
+		player.RankVal = *arg.RankVal
+	}
+	if arg.VoiceChat != nil {
+		player.VoiceChat = *arg.VoiceChat
+	}
+	if arg.Mic != nil {
+		player.Mic = *arg.Mic
+	}
+	return player
+}
+
+func clone(group *repository.GroupWithPlayers) repository.GroupWithPlayers {
+	result := *group
+	result.Players = append([]repository.PlayerInGroup(nil), group.Players...)
+	return result
+}
+
+// groupID turns a counter into a four letter ID like the ones the database generates, e.g. 1 is AAAB.
+func groupID(n int) string {
+	id := []byte("AAAA")
+	for i := len(id) - 1; i >= 0 && n > 0; i-- {
+		id[i] = byte('A' + n%26)
+		n /= 26
+	}
+	return string(id)
+}
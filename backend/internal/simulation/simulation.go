@@ -0,0 +1,181 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/store"
+	"github.com/jcserv/rivalslfg/internal/types"
+)
+
+// Simulations start at a fixed time so that reports don't depend on when they were run.
+var simulationStart = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Simulation runs synthetic players through the real matcher, on top of an
+// in-memory repository and store that share a simulated clock.
+type Simulation struct {
+	cfg     Config
+	rand    *rand.Rand
+	clock   *Clock
+	repo    *Repository
+	matcher *services.Matcher
+
+	// When each player that is still looking for a group joined the queue
+	queued map[int32]time.Time
+	stats  *stats
+}
+
+func New(cfg Config) *Simulation {
+	clock := NewClock(simulationStart)
+	repo := NewRepository(clock)
+
+	s := store.NewMemoryStore()
+	s.SetClock(clock.Now)
+	matcher := services.NewMatcher(repo, repo, nil, s)
+	matcher.SetClock(clock.Now)
+
+	return &Simulation{
+		cfg:     cfg,
+		rand:    rand.New(rand.NewSource(cfg.Seed)),
+		clock:   clock,
+		repo:    repo,
+		matcher: matcher,
+		queued:  make(map[int32]time.Time),
+		stats:   newStats(),
+	}
+}
+
+// Run generates arrivals for the configured duration, and keeps matching
+// afterwards until every player has either found a group or run out of
+// patience. Groups leave as soon as they fill up.
+func (s *Simulation) Run(ctx context.Context) (*Report, error) {
+	start := s.clock.Now()
+	arrivalsEnd := start.Add(time.Duration(s.cfg.Duration))
+	end := arrivalsEnd.Add(time.Duration(s.cfg.Patience))
+
+	nextArrival := start.Add(s.interarrival())
+	for tick := start; !tick.After(end); tick = tick.Add(time.Duration(s.cfg.MatchInterval)) {
+		for nextArrival.Before(arrivalsEnd) && !nextArrival.After(tick) {
+			s.clock.AdvanceTo(nextArrival)
+			if err := s.arrive(ctx); err != nil {
+				return nil, err
+			}
+			nextArrival = nextArrival.Add(s.interarrival())
+		}
+
+		s.clock.AdvanceTo(tick)
+		s.matcher.MatchQueued(ctx)
+		s.collect(ctx)
+	}
+
+	for _, group := range s.repo.Groups() {
+		s.stats.addGroup(group, false)
+	}
+	return s.stats.report(), nil
+}
+
+// arrive queues a new player, who is matched right away if possible.
+func (s *Simulation) arrive(ctx context.Context) error {
+	player := s.generatePlayer()
+	entry, err := s.matcher.Enqueue(ctx, player, s.cfg.schedule(player.VoiceChat))
+	if err != nil {
+		return fmt.Errorf("unable to queue player: %w", err)
+	}
+	s.queued[entry.PlayerID] = s.clock.Now()
+	s.stats.arrivals++
+	return nil
+}
+
+// collect takes players that found a group out of the queue, along with those
+// that ran out of patience. Groups that filled up are then sent off to play.
+func (s *Simulation) collect(ctx context.Context) {
+	now := s.clock.Now()
+
+	playerIDs := make([]int32, 0, len(s.queued))
+	for playerID := range s.queued {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Slice(playerIDs, func(i, j int) bool {
+		return playerIDs[i] < playerIDs[j]
+	})
+
+	for _, playerID := range playerIDs {
+		queuedAt := s.queued[playerID]
+		if _, joinedAt, ok := s.repo.GroupOf(playerID); ok {
+			s.stats.addWait(joinedAt.Sub(queuedAt))
+		} else if now.Sub(queuedAt) >= time.Duration(s.cfg.Patience) {
+			s.stats.abandoned++
+		} else {
+			continue
+		}
+
+		delete(s.queued, playerID)
+		// Players that were matched on arrival have already left the queue
+		_ = s.matcher.Dequeue(ctx, playerID)
+	}
+
+	for _, group := range s.repo.TakeFullGroups() {
+		s.stats.addGroup(group, true)
+	}
+}
+
+func (s *Simulation) generatePlayer() repository.JoinGroupParams {
+	roles := []string{s.pick(s.cfg.Roles)}
+	if s.rand.Float64() < s.cfg.FlexRate {
+		others := make([]string, 0, len(types.Roles))
+		for _, role := range []string{"vanguard", "duelist", "strategist"} {
+			if role != roles[0] {
+				others = append(others, role)
+			}
+		}
+		s.rand.Shuffle(len(others), func(i, j int) {
+			others[i], others[j] = others[j], others[i]
+		})
+		roles = append(roles, others...)
+	}
+
+	return repository.JoinGroupParams{
+		Name:       fmt.Sprintf("Player %d", s.stats.arrivals+1),
+		Gamemode:   s.pick(s.cfg.Gamemodes),
+		Region:     s.pick(s.cfg.Regions),
+		Platform:   s.pick(s.cfg.Platforms),
+		Role:       roles[0],
+		Roles:      roles,
+		RankVal:    int32(types.RankIDToRankVal[s.pick(s.cfg.Ranks)]),
+		Characters: []string{},
+		VoiceChat:  s.rand.Float64() < s.cfg.VoiceChatRate,
+		Mic:        s.rand.Float64() < s.cfg.MicRate,
+	}
+}
+
+// pick chooses an option with a likelihood proportional to its weight.
+func (s *Simulation) pick(weights Weights) string {
+	options := make([]string, 0, len(weights))
+	total := 0.0
+	for option, weight := range weights {
+		options = append(options, option)
+		total += weight
+	}
+	sort.Strings(options)
+
+	target := s.rand.Float64() * total
+	for _, option := range options {
+		target -= weights[option]
+		if target < 0 {
+			return option
+		}
+	}
+	return options[len(options)-1]
+}
+
+// interarrival is the time until the next player arrives. Arrivals follow a
+// Poisson process, so the gaps between them are exponentially distributed.
+func (s *Simulation) interarrival() time.Duration {
+	perSecond := s.cfg.ArrivalsPerMinute / 60
+	return time.Duration(s.rand.ExpFloat64() / perSecond * float64(time.Second))
+}
@@ -0,0 +1,131 @@
+package simulation_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/simulation"
+	"github.com/stretchr/testify/assert"
+)
+
+func shortConfig() simulation.Config {
+	cfg := simulation.DefaultConfig()
+	cfg.Duration = simulation.Duration(10 * time.Minute)
+	cfg.Patience = simulation.Duration(5 * time.Minute)
+	return cfg
+}
+
+func TestSimulation_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should account for every player that arrived", func(t *testing.T) {
+		report, err := simulation.New(shortConfig()).Run(ctx)
+		assert.NoError(t, err)
+		assert.Greater(t, report.Arrivals, 0)
+		assert.Equal(t, report.Arrivals, report.Grouped+report.Abandoned)
+		assert.LessOrEqual(t, report.TimeToGroup.Max, shortConfig().Patience)
+
+		grouped := 0
+		for _, bucket := range report.RankSpread {
+			grouped += bucket.Groups
+		}
+		assert.Equal(t, report.Groups, grouped)
+	})
+
+	t.Run("Should produce the same report for the same seed", func(t *testing.T) {
+		first, err := simulation.New(shortConfig()).Run(ctx)
+		assert.NoError(t, err)
+		second, err := simulation.New(shortConfig()).Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("Should keep groups within the rank policy", func(t *testing.T) {
+		report, err := simulation.New(shortConfig()).Run(ctx)
+		assert.NoError(t, err)
+
+		// The Bronze-Gold band is the widest spread the default policy allows
+		widest := report.RankSpread[len(report.RankSpread)-1]
+		assert.LessOrEqual(t, widest.Min, 22)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("Should accept the default config", func(t *testing.T) {
+		assert.NoError(t, simulation.DefaultConfig().Validate())
+	})
+	t.Run("Should reject unknown options", func(t *testing.T) {
+		cfg := simulation.DefaultConfig()
+		cfg.Ranks = simulation.Weights{"d4": 1}
+		assert.Error(t, cfg.Validate())
+	})
+	t.Run("Should reject weights that are all zero", func(t *testing.T) {
+		cfg := simulation.DefaultConfig()
+		cfg.Regions = simulation.Weights{"na": 0}
+		assert.Error(t, cfg.Validate())
+	})
+}
+
+func TestRepository_JoinGroup(t *testing.T) {
+	ctx := context.Background()
+	repo := simulation.NewRepository(simulation.NewClock(time.Now()))
+
+	created, err := repo.CreateGroup(ctx, repository.CreateGroupParams{
+		PlayerID:       int32(1),
+		Owner:          "leader",
+		Platform:       "pc",
+		Role:           "vanguard",
+		RankVal:        40,
+		Region:         "na",
+		Gamemode:       "competitive",
+		Open:           true,
+		Vanguards:      1,
+		Duelists:       1,
+		Strategists:    0,
+		GroupVoiceChat: pgtype.Bool{Valid: true},
+		GroupMic:       pgtype.Bool{Valid: true},
+	})
+	assert.NoError(t, err)
+
+	join := func(playerID int32, rankVal int32, roles ...string) error {
+		_, err := repo.JoinGroup(ctx, repository.JoinGroupParams{
+			GroupID:  created.GroupID,
+			PlayerID: playerID,
+			Gamemode: "competitive",
+			Region:   "na",
+			Platform: "pc",
+			Role:     roles[0],
+			Roles:    roles,
+			RankVal:  rankVal,
+		})
+		return err
+	}
+
+	t.Run("Should reject players that don't meet the requirements", func(t *testing.T) {
+		err := join(2, 0, "duelist")
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.(services.Error).Code())
+	})
+
+	t.Run("Should seat players in their first open preferred role", func(t *testing.T) {
+		assert.NoError(t, join(3, 41, "vanguard", "duelist"))
+
+		groups := repo.Groups()
+		assert.Len(t, groups, 1)
+		assert.Equal(t, "duelist", groups[0].Players[1].Role)
+	})
+
+	t.Run("Should hand off full groups", func(t *testing.T) {
+		full := repo.TakeFullGroups()
+		assert.Len(t, full, 1)
+		assert.Empty(t, repo.Groups())
+
+		_, _, ok := repo.GroupOf(3)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	value     any
+	expiresAt time.Time
+}
+
+func (i memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+// MemoryStore is an in-process Store, used in tests and wherever Redis is not available.
+type MemoryStore struct {
+	sync.Mutex
+	items map[string]memoryItem
+	now   func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		items: make(map[string]memoryItem),
+		now:   time.Now,
+	}
+}
+
+// SetClock replaces the clock used to expire keys, so that the store can run on simulated time.
+func (s *MemoryStore) SetClock(now func() time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	s.now = now
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (any, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	item, ok := s.load(key)
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return item.value, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value any) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.items[key] = memoryItem{value: value}
+	return nil
+}
+
+func (s *MemoryStore) SetNX(ctx context.Context, key string, value any, duration time.Duration) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.load(key); ok {
+		return false, nil
+	}
+
+	item := memoryItem{value: value}
+	if duration > 0 {
+		item.expiresAt = s.now().Add(duration)
+	}
+	s.items[key] = item
+	return true, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.items, key)
+	return nil
+}
+
+func (s *MemoryStore) DeleteIfValue(ctx context.Context, key string, value string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	item, ok := s.load(key)
+	if !ok || item.value != value {
+		return false, nil
+	}
+	delete(s.items, key)
+	return true, nil
+}
+
+func (s *MemoryStore) Expire(ctx context.Context, key string, duration time.Duration) error {
+	s.Lock()
+	defer s.Unlock()
+
+	item, ok := s.load(key)
+	if !ok {
+		return ErrKeyNotFound
+	}
+	item.expiresAt = s.now().Add(duration)
+	s.items[key] = item
+	return nil
+}
+
+// load returns the item stored at key, evicting it if it has expired. The caller must hold the lock.
+func (s *MemoryStore) load(key string) (memoryItem, bool) {
+	item, ok := s.items[key]
+	if !ok {
+		return memoryItem{}, false
+	}
+	if item.expired(s.now()) {
+		delete(s.items, key)
+		return memoryItem{}, false
+	}
+	return item, true
+}
@@ -2,12 +2,20 @@ package store
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+// deleteIfValue deletes the key only if it holds the given value, in one step so
+// that nobody can set the key in between.
+var deleteIfValue = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
 type RedisStore struct {
 	conn *redis.Client
 }
@@ -24,7 +32,7 @@ func (s *RedisStore) Get(ctx context.Context, key string) (any, error) {
 		return nil, err
 	}
 	if result == "" {
-		return nil, errors.New("key not found")
+		return nil, ErrKeyNotFound
 	}
 	return result, nil
 }
@@ -33,6 +41,22 @@ func (s *RedisStore) Set(ctx context.Context, key string, value any) error {
 	return s.conn.HSet(ctx, key, value).Err()
 }
 
+func (s *RedisStore) SetNX(ctx context.Context, key string, value any, duration time.Duration) (bool, error) {
+	return s.conn.SetNX(ctx, key, value, duration).Result()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	return s.conn.Del(ctx, key).Err()
+}
+
+func (s *RedisStore) DeleteIfValue(ctx context.Context, key string, value string) (bool, error) {
+	deleted, err := deleteIfValue.Run(ctx, s.conn, []string{key}, value).Int()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
 func (s *RedisStore) Expire(ctx context.Context, key string, duration time.Duration) error {
 	return s.conn.Expire(ctx, key, duration).Err()
 }
@@ -2,15 +2,22 @@ package store
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+var ErrKeyNotFound = errors.New("key not found")
+
 type Store interface {
 	Get(ctx context.Context, key string) (any, error)
 	Set(ctx context.Context, key string, value any) error
-	// Delete(ctx context.Context, key string) error
+	// SetNX sets the key only if it does not already exist, and reports whether it was set.
+	SetNX(ctx context.Context, key string, value any, duration time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// DeleteIfValue deletes the key only if it's still set to value, and reports whether it was deleted.
+	DeleteIfValue(ctx context.Context, key string, value string) (bool, error)
 	Expire(ctx context.Context, key string, duration time.Duration) error
 }
 
@@ -14,7 +14,9 @@ import (
 	reflect "reflect"
 
 	repository "github.com/jcserv/rivalslfg/internal/repository"
+	services "github.com/jcserv/rivalslfg/internal/services"
 	gomock "go.uber.org/mock/gomock"
+	time "time"
 )
 
 // MockIGroup is a mock of IGroup interface.
@@ -41,6 +43,49 @@ func (m *MockIGroup) EXPECT() *MockIGroupMockRecorder {
 	return m.recorder
 }
 
+// AcceptWaitlistOffer mocks base method.
+func (m *MockIGroup) AcceptWaitlistOffer(ctx context.Context, groupID string, playerID int32) (*services.WaitlistEntryDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptWaitlistOffer", ctx, groupID, playerID)
+	ret0, _ := ret[0].(*services.WaitlistEntryDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptWaitlistOffer indicates an expected call of AcceptWaitlistOffer.
+func (mr *MockIGroupMockRecorder) AcceptWaitlistOffer(ctx, groupID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptWaitlistOffer", reflect.TypeOf((*MockIGroup)(nil).AcceptWaitlistOffer), ctx, groupID, playerID)
+}
+
+// CancelJoinRequest mocks base method.
+func (m *MockIGroup) CancelJoinRequest(ctx context.Context, groupID string, requestID, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJoinRequest", ctx, groupID, requestID, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelJoinRequest indicates an expected call of CancelJoinRequest.
+func (mr *MockIGroupMockRecorder) CancelJoinRequest(ctx, groupID, requestID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJoinRequest", reflect.TypeOf((*MockIGroup)(nil).CancelJoinRequest), ctx, groupID, requestID, playerID)
+}
+
+// CancelRsvp mocks base method.
+func (m *MockIGroup) CancelRsvp(ctx context.Context, groupID string, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelRsvp", ctx, groupID, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelRsvp indicates an expected call of CancelRsvp.
+func (mr *MockIGroupMockRecorder) CancelRsvp(ctx, groupID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRsvp", reflect.TypeOf((*MockIGroup)(nil).CancelRsvp), ctx, groupID, playerID)
+}
+
 // CreateGroup mocks base method.
 func (m *MockIGroup) CreateGroup(ctx context.Context, arg repository.CreateGroupParams) (repository.CreateGroupRow, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +101,109 @@ func (mr *MockIGroupMockRecorder) CreateGroup(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockIGroup)(nil).CreateGroup), ctx, arg)
 }
 
+// CreateInvite mocks base method.
+func (m *MockIGroup) CreateInvite(ctx context.Context, arg services.CreateInviteParams) (*services.InviteDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvite", ctx, arg)
+	ret0, _ := ret[0].(*services.InviteDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvite indicates an expected call of CreateInvite.
+func (mr *MockIGroupMockRecorder) CreateInvite(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvite", reflect.TypeOf((*MockIGroup)(nil).CreateInvite), ctx, arg)
+}
+
+// DeleteCommunityGroup mocks base method.
+func (m *MockIGroup) DeleteCommunityGroup(ctx context.Context, communityID int32, groupID string, moderatorID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCommunityGroup", ctx, communityID, groupID, moderatorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCommunityGroup indicates an expected call of DeleteCommunityGroup.
+func (mr *MockIGroupMockRecorder) DeleteCommunityGroup(ctx, communityID, groupID, moderatorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCommunityGroup", reflect.TypeOf((*MockIGroup)(nil).DeleteCommunityGroup), ctx, communityID, groupID, moderatorID)
+}
+
+// DeleteGroup mocks base method.
+func (m *MockIGroup) DeleteGroup(ctx context.Context, groupID string, ownerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroup", ctx, groupID, ownerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroup indicates an expected call of DeleteGroup.
+func (mr *MockIGroupMockRecorder) DeleteGroup(ctx, groupID, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockIGroup)(nil).DeleteGroup), ctx, groupID, ownerID)
+}
+
+// DeleteIdleGroup mocks base method.
+func (m *MockIGroup) DeleteIdleGroup(ctx context.Context, groupID string, idleSince time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdleGroup", ctx, groupID, idleSince)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIdleGroup indicates an expected call of DeleteIdleGroup.
+func (mr *MockIGroupMockRecorder) DeleteIdleGroup(ctx, groupID, idleSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdleGroup", reflect.TypeOf((*MockIGroup)(nil).DeleteIdleGroup), ctx, groupID, idleSince)
+}
+
+// GetBans mocks base method.
+func (m *MockIGroup) GetBans(ctx context.Context, groupID string, ownerID int32) ([]services.BanDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBans", ctx, groupID, ownerID)
+	ret0, _ := ret[0].([]services.BanDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBans indicates an expected call of GetBans.
+func (mr *MockIGroupMockRecorder) GetBans(ctx, groupID, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBans", reflect.TypeOf((*MockIGroup)(nil).GetBans), ctx, groupID, ownerID)
+}
+
+// GetDueGroups mocks base method.
+func (m *MockIGroup) GetDueGroups(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueGroups", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueGroups indicates an expected call of GetDueGroups.
+func (mr *MockIGroupMockRecorder) GetDueGroups(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueGroups", reflect.TypeOf((*MockIGroup)(nil).GetDueGroups), ctx)
+}
+
+// GetEligibility mocks base method.
+func (m *MockIGroup) GetEligibility(ctx context.Context, groupID string, player repository.JoinGroupParams) (*services.Eligibility, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEligibility", ctx, groupID, player)
+	ret0, _ := ret[0].(*services.Eligibility)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEligibility indicates an expected call of GetEligibility.
+func (mr *MockIGroupMockRecorder) GetEligibility(ctx, groupID, player any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEligibility", reflect.TypeOf((*MockIGroup)(nil).GetEligibility), ctx, groupID, player)
+}
+
 // GetGroupByID mocks base method.
 func (m *MockIGroup) GetGroupByID(ctx context.Context, id string, isGroupOwner bool) (*repository.GroupWithPlayers, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +235,374 @@ func (mr *MockIGroupMockRecorder) GetGroups(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroups", reflect.TypeOf((*MockIGroup)(nil).GetGroups), ctx, arg)
 }
 
+// GetIdleGroups mocks base method.
+func (m *MockIGroup) GetIdleGroups(ctx context.Context, idleSince time.Time) ([]repository.GetIdleGroupsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdleGroups", ctx, idleSince)
+	ret0, _ := ret[0].([]repository.GetIdleGroupsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdleGroups indicates an expected call of GetIdleGroups.
+func (mr *MockIGroupMockRecorder) GetIdleGroups(ctx, idleSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdleGroups", reflect.TypeOf((*MockIGroup)(nil).GetIdleGroups), ctx, idleSince)
+}
+
+// GetInvites mocks base method.
+func (m *MockIGroup) GetInvites(ctx context.Context, groupID string, ownerID int32) ([]services.InviteDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvites", ctx, groupID, ownerID)
+	ret0, _ := ret[0].([]services.InviteDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvites indicates an expected call of GetInvites.
+func (mr *MockIGroupMockRecorder) GetInvites(ctx, groupID, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvites", reflect.TypeOf((*MockIGroup)(nil).GetInvites), ctx, groupID, ownerID)
+}
+
+// GetJoinRequest mocks base method.
+func (m *MockIGroup) GetJoinRequest(ctx context.Context, groupID string, requestID int32) (*services.JoinRequestDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJoinRequest", ctx, groupID, requestID)
+	ret0, _ := ret[0].(*services.JoinRequestDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJoinRequest indicates an expected call of GetJoinRequest.
+func (mr *MockIGroupMockRecorder) GetJoinRequest(ctx, groupID, requestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJoinRequest", reflect.TypeOf((*MockIGroup)(nil).GetJoinRequest), ctx, groupID, requestID)
+}
+
+// GetJoinRequests mocks base method.
+func (m *MockIGroup) GetJoinRequests(ctx context.Context, groupID string) ([]services.JoinRequestDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJoinRequests", ctx, groupID)
+	ret0, _ := ret[0].([]services.JoinRequestDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJoinRequests indicates an expected call of GetJoinRequests.
+func (mr *MockIGroupMockRecorder) GetJoinRequests(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJoinRequests", reflect.TypeOf((*MockIGroup)(nil).GetJoinRequests), ctx, groupID)
+}
+
+// GetLapsedOfferGroups mocks base method.
+func (m *MockIGroup) GetLapsedOfferGroups(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLapsedOfferGroups", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLapsedOfferGroups indicates an expected call of GetLapsedOfferGroups.
+func (mr *MockIGroupMockRecorder) GetLapsedOfferGroups(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLapsedOfferGroups", reflect.TypeOf((*MockIGroup)(nil).GetLapsedOfferGroups), ctx)
+}
+
+// GetMergeProposals mocks base method.
+func (m *MockIGroup) GetMergeProposals(ctx context.Context, groupID string) ([]services.MergeDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMergeProposals", ctx, groupID)
+	ret0, _ := ret[0].([]services.MergeDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMergeProposals indicates an expected call of GetMergeProposals.
+func (mr *MockIGroupMockRecorder) GetMergeProposals(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMergeProposals", reflect.TypeOf((*MockIGroup)(nil).GetMergeProposals), ctx, groupID)
+}
+
+// GetPasscode mocks base method.
+func (m *MockIGroup) GetPasscode(ctx context.Context, groupID string, ownerID int32) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasscode", ctx, groupID, ownerID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasscode indicates an expected call of GetPasscode.
+func (mr *MockIGroupMockRecorder) GetPasscode(ctx, groupID, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasscode", reflect.TypeOf((*MockIGroup)(nil).GetPasscode), ctx, groupID, ownerID)
+}
+
+// GetRsvps mocks base method.
+func (m *MockIGroup) GetRsvps(ctx context.Context, groupID string) ([]services.RsvpDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRsvps", ctx, groupID)
+	ret0, _ := ret[0].([]services.RsvpDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRsvps indicates an expected call of GetRsvps.
+func (mr *MockIGroupMockRecorder) GetRsvps(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRsvps", reflect.TypeOf((*MockIGroup)(nil).GetRsvps), ctx, groupID)
+}
+
+// GetWaitlist mocks base method.
+func (m *MockIGroup) GetWaitlist(ctx context.Context, groupID string) ([]services.WaitlistEntryDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitlist", ctx, groupID)
+	ret0, _ := ret[0].([]services.WaitlistEntryDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitlist indicates an expected call of GetWaitlist.
+func (mr *MockIGroupMockRecorder) GetWaitlist(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitlist", reflect.TypeOf((*MockIGroup)(nil).GetWaitlist), ctx, groupID)
+}
+
+// JoinWaitlist mocks base method.
+func (m *MockIGroup) JoinWaitlist(ctx context.Context, arg services.JoinWaitlistParams) (*services.WaitlistEntryDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinWaitlist", ctx, arg)
+	ret0, _ := ret[0].(*services.WaitlistEntryDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinWaitlist indicates an expected call of JoinWaitlist.
+func (mr *MockIGroupMockRecorder) JoinWaitlist(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinWaitlist", reflect.TypeOf((*MockIGroup)(nil).JoinWaitlist), ctx, arg)
+}
+
+// LeaveWaitlist mocks base method.
+func (m *MockIGroup) LeaveWaitlist(ctx context.Context, groupID string, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveWaitlist", ctx, groupID, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaveWaitlist indicates an expected call of LeaveWaitlist.
+func (mr *MockIGroupMockRecorder) LeaveWaitlist(ctx, groupID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveWaitlist", reflect.TypeOf((*MockIGroup)(nil).LeaveWaitlist), ctx, groupID, playerID)
+}
+
+// LiftBan mocks base method.
+func (m *MockIGroup) LiftBan(ctx context.Context, groupID string, ownerID, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LiftBan", ctx, groupID, ownerID, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LiftBan indicates an expected call of LiftBan.
+func (mr *MockIGroupMockRecorder) LiftBan(ctx, groupID, ownerID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LiftBan", reflect.TypeOf((*MockIGroup)(nil).LiftBan), ctx, groupID, ownerID, playerID)
+}
+
+// OfferOpenSlots mocks base method.
+func (m *MockIGroup) OfferOpenSlots(ctx context.Context, groupID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OfferOpenSlots", ctx, groupID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OfferOpenSlots indicates an expected call of OfferOpenSlots.
+func (mr *MockIGroupMockRecorder) OfferOpenSlots(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferOpenSlots", reflect.TypeOf((*MockIGroup)(nil).OfferOpenSlots), ctx, groupID)
+}
+
+// PromoteMember mocks base method.
+func (m *MockIGroup) PromoteMember(ctx context.Context, groupID string, ownerID, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteMember", ctx, groupID, ownerID, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PromoteMember indicates an expected call of PromoteMember.
+func (mr *MockIGroupMockRecorder) PromoteMember(ctx, groupID, ownerID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteMember", reflect.TypeOf((*MockIGroup)(nil).PromoteMember), ctx, groupID, ownerID, playerID)
+}
+
+// ProposeMerge mocks base method.
+func (m *MockIGroup) ProposeMerge(ctx context.Context, arg services.ProposeMergeParams) (*services.MergeDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProposeMerge", ctx, arg)
+	ret0, _ := ret[0].(*services.MergeDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProposeMerge indicates an expected call of ProposeMerge.
+func (mr *MockIGroupMockRecorder) ProposeMerge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProposeMerge", reflect.TypeOf((*MockIGroup)(nil).ProposeMerge), ctx, arg)
+}
+
+// RecordActivity mocks base method.
+func (m *MockIGroup) RecordActivity(ctx context.Context, groupID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordActivity", ctx, groupID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordActivity indicates an expected call of RecordActivity.
+func (mr *MockIGroupMockRecorder) RecordActivity(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordActivity", reflect.TypeOf((*MockIGroup)(nil).RecordActivity), ctx, groupID)
+}
+
+// RequestToJoin mocks base method.
+func (m *MockIGroup) RequestToJoin(ctx context.Context, arg repository.JoinGroupParams) (*services.JoinRequestDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestToJoin", ctx, arg)
+	ret0, _ := ret[0].(*services.JoinRequestDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestToJoin indicates an expected call of RequestToJoin.
+func (mr *MockIGroupMockRecorder) RequestToJoin(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestToJoin", reflect.TypeOf((*MockIGroup)(nil).RequestToJoin), ctx, arg)
+}
+
+// ResolveJoinRequest mocks base method.
+func (m *MockIGroup) ResolveJoinRequest(ctx context.Context, groupID string, requestID, ownerID int32, approve bool) (*services.JoinRequestDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveJoinRequest", ctx, groupID, requestID, ownerID, approve)
+	ret0, _ := ret[0].(*services.JoinRequestDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveJoinRequest indicates an expected call of ResolveJoinRequest.
+func (mr *MockIGroupMockRecorder) ResolveJoinRequest(ctx, groupID, requestID, ownerID, approve any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveJoinRequest", reflect.TypeOf((*MockIGroup)(nil).ResolveJoinRequest), ctx, groupID, requestID, ownerID, approve)
+}
+
+// ResolveMerge mocks base method.
+func (m *MockIGroup) ResolveMerge(ctx context.Context, groupID string, mergeID, ownerID int32, accept bool) (*services.MergeDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveMerge", ctx, groupID, mergeID, ownerID, accept)
+	ret0, _ := ret[0].(*services.MergeDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveMerge indicates an expected call of ResolveMerge.
+func (mr *MockIGroupMockRecorder) ResolveMerge(ctx, groupID, mergeID, ownerID, accept any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveMerge", reflect.TypeOf((*MockIGroup)(nil).ResolveMerge), ctx, groupID, mergeID, ownerID, accept)
+}
+
+// RevokeInvite mocks base method.
+func (m *MockIGroup) RevokeInvite(ctx context.Context, groupID string, inviteID, ownerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeInvite", ctx, groupID, inviteID, ownerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeInvite indicates an expected call of RevokeInvite.
+func (mr *MockIGroupMockRecorder) RevokeInvite(ctx, groupID, inviteID, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeInvite", reflect.TypeOf((*MockIGroup)(nil).RevokeInvite), ctx, groupID, inviteID, ownerID)
+}
+
+// RotatePasscode mocks base method.
+func (m *MockIGroup) RotatePasscode(ctx context.Context, groupID string, ownerID int32, revokeInvites bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotatePasscode", ctx, groupID, ownerID, revokeInvites)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotatePasscode indicates an expected call of RotatePasscode.
+func (mr *MockIGroupMockRecorder) RotatePasscode(ctx, groupID, ownerID, revokeInvites any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotatePasscode", reflect.TypeOf((*MockIGroup)(nil).RotatePasscode), ctx, groupID, ownerID, revokeInvites)
+}
+
+// Rsvp mocks base method.
+func (m *MockIGroup) Rsvp(ctx context.Context, player repository.JoinGroupParams) (*services.RsvpDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rsvp", ctx, player)
+	ret0, _ := ret[0].(*services.RsvpDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rsvp indicates an expected call of Rsvp.
+func (mr *MockIGroupMockRecorder) Rsvp(ctx, player any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rsvp", reflect.TypeOf((*MockIGroup)(nil).Rsvp), ctx, player)
+}
+
+// StartGroup mocks base method.
+func (m *MockIGroup) StartGroup(ctx context.Context, groupID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartGroup", ctx, groupID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartGroup indicates an expected call of StartGroup.
+func (mr *MockIGroupMockRecorder) StartGroup(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartGroup", reflect.TypeOf((*MockIGroup)(nil).StartGroup), ctx, groupID)
+}
+
+// UpdateGroup mocks base method.
+func (m *MockIGroup) UpdateGroup(ctx context.Context, arg services.UpdateGroupParams) (*repository.GroupWithPlayers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGroup", ctx, arg)
+	ret0, _ := ret[0].(*repository.GroupWithPlayers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGroup indicates an expected call of UpdateGroup.
+func (mr *MockIGroupMockRecorder) UpdateGroup(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroup", reflect.TypeOf((*MockIGroup)(nil).UpdateGroup), ctx, arg)
+}
+
+// UpdateMember mocks base method.
+func (m *MockIGroup) UpdateMember(ctx context.Context, arg services.UpdateMemberParams) (*repository.GroupWithPlayers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMember", ctx, arg)
+	ret0, _ := ret[0].(*repository.GroupWithPlayers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMember indicates an expected call of UpdateMember.
+func (mr *MockIGroupMockRecorder) UpdateMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMember", reflect.TypeOf((*MockIGroup)(nil).UpdateMember), ctx, arg)
+}
+
 // MockIPlayer is a mock of IPlayer interface.
 type MockIPlayer struct {
 	ctrl     *gomock.Controller
@@ -111,6 +627,21 @@ func (m *MockIPlayer) EXPECT() *MockIPlayerMockRecorder {
 	return m.recorder
 }
 
+// GetPlayerGroup mocks base method.
+func (m *MockIPlayer) GetPlayerGroup(ctx context.Context, playerID int32) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlayerGroup", ctx, playerID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlayerGroup indicates an expected call of GetPlayerGroup.
+func (mr *MockIPlayerMockRecorder) GetPlayerGroup(ctx, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlayerGroup", reflect.TypeOf((*MockIPlayer)(nil).GetPlayerGroup), ctx, playerID)
+}
+
 // JoinGroup mocks base method.
 func (m *MockIPlayer) JoinGroup(ctx context.Context, arg repository.JoinGroupParams) (int32, error) {
 	m.ctrl.T.Helper()
@@ -140,3 +671,326 @@ func (mr *MockIPlayerMockRecorder) RemovePlayer(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePlayer", reflect.TypeOf((*MockIPlayer)(nil).RemovePlayer), ctx, arg)
 }
+
+// UpsertPlayer mocks base method.
+func (m *MockIPlayer) UpsertPlayer(ctx context.Context, arg repository.UpsertPlayerParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPlayer", ctx, arg)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertPlayer indicates an expected call of UpsertPlayer.
+func (mr *MockIPlayerMockRecorder) UpsertPlayer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPlayer", reflect.TypeOf((*MockIPlayer)(nil).UpsertPlayer), ctx, arg)
+}
+
+// MockIParty is a mock of IParty interface.
+type MockIParty struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPartyMockRecorder
+	isgomock struct{}
+}
+
+// MockIPartyMockRecorder is the mock recorder for MockIParty.
+type MockIPartyMockRecorder struct {
+	mock *MockIParty
+}
+
+// NewMockIParty creates a new mock instance.
+func NewMockIParty(ctrl *gomock.Controller) *MockIParty {
+	mock := &MockIParty{ctrl: ctrl}
+	mock.recorder = &MockIPartyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIParty) EXPECT() *MockIPartyMockRecorder {
+	return m.recorder
+}
+
+// CreateParty mocks base method.
+func (m *MockIParty) CreateParty(ctx context.Context, player repository.JoinGroupParams) (string, int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateParty", ctx, player)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(int32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateParty indicates an expected call of CreateParty.
+func (mr *MockIPartyMockRecorder) CreateParty(ctx, player any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateParty", reflect.TypeOf((*MockIParty)(nil).CreateParty), ctx, player)
+}
+
+// GetParty mocks base method.
+func (m *MockIParty) GetParty(ctx context.Context, partyID string) (*services.PartyDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParty", ctx, partyID)
+	ret0, _ := ret[0].(*services.PartyDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParty indicates an expected call of GetParty.
+func (mr *MockIPartyMockRecorder) GetParty(ctx, partyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParty", reflect.TypeOf((*MockIParty)(nil).GetParty), ctx, partyID)
+}
+
+// JoinGroup mocks base method.
+func (m *MockIParty) JoinGroup(ctx context.Context, arg services.JoinGroupAsPartyParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinGroup", ctx, arg)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinGroup indicates an expected call of JoinGroup.
+func (mr *MockIPartyMockRecorder) JoinGroup(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinGroup", reflect.TypeOf((*MockIParty)(nil).JoinGroup), ctx, arg)
+}
+
+// JoinParty mocks base method.
+func (m *MockIParty) JoinParty(ctx context.Context, partyID string, player repository.JoinGroupParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinParty", ctx, partyID, player)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinParty indicates an expected call of JoinParty.
+func (mr *MockIPartyMockRecorder) JoinParty(ctx, partyID, player any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinParty", reflect.TypeOf((*MockIParty)(nil).JoinParty), ctx, partyID, player)
+}
+
+// LeaveParty mocks base method.
+func (m *MockIParty) LeaveParty(ctx context.Context, partyID string, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveParty", ctx, partyID, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaveParty indicates an expected call of LeaveParty.
+func (mr *MockIPartyMockRecorder) LeaveParty(ctx, partyID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveParty", reflect.TypeOf((*MockIParty)(nil).LeaveParty), ctx, partyID, playerID)
+}
+
+// MockICommunity is a mock of ICommunity interface.
+type MockICommunity struct {
+	ctrl     *gomock.Controller
+	recorder *MockICommunityMockRecorder
+	isgomock struct{}
+}
+
+// MockICommunityMockRecorder is the mock recorder for MockICommunity.
+type MockICommunityMockRecorder struct {
+	mock *MockICommunity
+}
+
+// NewMockICommunity creates a new mock instance.
+func NewMockICommunity(ctrl *gomock.Controller) *MockICommunity {
+	mock := &MockICommunity{ctrl: ctrl}
+	mock.recorder = &MockICommunityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICommunity) EXPECT() *MockICommunityMockRecorder {
+	return m.recorder
+}
+
+// AddModerator mocks base method.
+func (m *MockICommunity) AddModerator(ctx context.Context, id, moderatorID, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModerator", ctx, id, moderatorID, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModerator indicates an expected call of AddModerator.
+func (mr *MockICommunityMockRecorder) AddModerator(ctx, id, moderatorID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModerator", reflect.TypeOf((*MockICommunity)(nil).AddModerator), ctx, id, moderatorID, playerID)
+}
+
+// CreateCommunity mocks base method.
+func (m *MockICommunity) CreateCommunity(ctx context.Context, arg services.CreateCommunityParams) (*services.CommunityDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCommunity", ctx, arg)
+	ret0, _ := ret[0].(*services.CommunityDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCommunity indicates an expected call of CreateCommunity.
+func (mr *MockICommunityMockRecorder) CreateCommunity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommunity", reflect.TypeOf((*MockICommunity)(nil).CreateCommunity), ctx, arg)
+}
+
+// DeleteCommunity mocks base method.
+func (m *MockICommunity) DeleteCommunity(ctx context.Context, id, moderatorID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCommunity", ctx, id, moderatorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCommunity indicates an expected call of DeleteCommunity.
+func (mr *MockICommunityMockRecorder) DeleteCommunity(ctx, id, moderatorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCommunity", reflect.TypeOf((*MockICommunity)(nil).DeleteCommunity), ctx, id, moderatorID)
+}
+
+// GetCommunities mocks base method.
+func (m *MockICommunity) GetCommunities(ctx context.Context) ([]services.CommunityDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommunities", ctx)
+	ret0, _ := ret[0].([]services.CommunityDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommunities indicates an expected call of GetCommunities.
+func (mr *MockICommunityMockRecorder) GetCommunities(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunities", reflect.TypeOf((*MockICommunity)(nil).GetCommunities), ctx)
+}
+
+// GetCommunity mocks base method.
+func (m *MockICommunity) GetCommunity(ctx context.Context, id int32) (*services.CommunityDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommunity", ctx, id)
+	ret0, _ := ret[0].(*services.CommunityDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommunity indicates an expected call of GetCommunity.
+func (mr *MockICommunityMockRecorder) GetCommunity(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunity", reflect.TypeOf((*MockICommunity)(nil).GetCommunity), ctx, id)
+}
+
+// RemoveModerator mocks base method.
+func (m *MockICommunity) RemoveModerator(ctx context.Context, id, moderatorID, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveModerator", ctx, id, moderatorID, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveModerator indicates an expected call of RemoveModerator.
+func (mr *MockICommunityMockRecorder) RemoveModerator(ctx, id, moderatorID, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveModerator", reflect.TypeOf((*MockICommunity)(nil).RemoveModerator), ctx, id, moderatorID, playerID)
+}
+
+// UpdateCommunity mocks base method.
+func (m *MockICommunity) UpdateCommunity(ctx context.Context, arg services.UpdateCommunityParams) (*services.CommunityDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCommunity", ctx, arg)
+	ret0, _ := ret[0].(*services.CommunityDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCommunity indicates an expected call of UpdateCommunity.
+func (mr *MockICommunityMockRecorder) UpdateCommunity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCommunity", reflect.TypeOf((*MockICommunity)(nil).UpdateCommunity), ctx, arg)
+}
+
+// MockIMatcher is a mock of IMatcher interface.
+type MockIMatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockIMatcherMockRecorder
+	isgomock struct{}
+}
+
+// MockIMatcherMockRecorder is the mock recorder for MockIMatcher.
+type MockIMatcherMockRecorder struct {
+	mock *MockIMatcher
+}
+
+// NewMockIMatcher creates a new mock instance.
+func NewMockIMatcher(ctrl *gomock.Controller) *MockIMatcher {
+	mock := &MockIMatcher{ctrl: ctrl}
+	mock.recorder = &MockIMatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIMatcher) EXPECT() *MockIMatcherMockRecorder {
+	return m.recorder
+}
+
+// Dequeue mocks base method.
+func (m *MockIMatcher) Dequeue(ctx context.Context, playerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dequeue", ctx, playerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Dequeue indicates an expected call of Dequeue.
+func (mr *MockIMatcherMockRecorder) Dequeue(ctx, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dequeue", reflect.TypeOf((*MockIMatcher)(nil).Dequeue), ctx, playerID)
+}
+
+// Enqueue mocks base method.
+func (m *MockIMatcher) Enqueue(ctx context.Context, arg repository.JoinGroupParams, relaxation services.RelaxationSchedule) (*services.QueueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, arg, relaxation)
+	ret0, _ := ret[0].(*services.QueueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockIMatcherMockRecorder) Enqueue(ctx, arg, relaxation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockIMatcher)(nil).Enqueue), ctx, arg, relaxation)
+}
+
+// EnqueueParty mocks base method.
+func (m *MockIMatcher) EnqueueParty(ctx context.Context, partyID string, arg repository.JoinGroupParams, relaxation services.RelaxationSchedule) (*services.QueueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueParty", ctx, partyID, arg, relaxation)
+	ret0, _ := ret[0].(*services.QueueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueParty indicates an expected call of EnqueueParty.
+func (mr *MockIMatcherMockRecorder) EnqueueParty(ctx, partyID, arg, relaxation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueParty", reflect.TypeOf((*MockIMatcher)(nil).EnqueueParty), ctx, partyID, arg, relaxation)
+}
+
+// Status mocks base method.
+func (m *MockIMatcher) Status(ctx context.Context, playerID int32) (*services.QueueStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx, playerID)
+	ret0, _ := ret[0].(*services.QueueStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockIMatcherMockRecorder) Status(ctx, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockIMatcher)(nil).Status), ctx, playerID)
+}
@@ -3,6 +3,7 @@ package httputil
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -13,6 +14,10 @@ type FilterOp string
 
 const (
 	Eq FilterOp = "eq"
+	Ge FilterOp = "ge"
+	Le FilterOp = "le"
+	// In matches any of a list of values, e.g. tag in ("chill", "learning")
+	In FilterOp = "in"
 )
 
 type Filter struct {
@@ -42,10 +47,17 @@ func parseFilters(r *http.Request) ([]Filter, error) {
 		if op == "" {
 			return nil, fmt.Errorf("missing operator in filter: %s", part)
 		}
-		if op != string(Eq) {
+		if !slices.Contains([]FilterOp{Eq, Ge, Le, In}, FilterOp(op)) {
 			return nil, fmt.Errorf("unsupported operator: %s", op)
 		}
-		value := parseFilterValue(strings.Join(fields[2:], " "))
+		var value any = parseFilterValue(strings.Join(fields[2:], " "))
+		if FilterOp(op) == In {
+			values, err := parseFilterValues(strings.Join(fields[2:], " "))
+			if err != nil {
+				return nil, err
+			}
+			value = values
+		}
 
 		filters = append(filters, Filter{
 			Field: field,
@@ -57,6 +69,23 @@ func parseFilters(r *http.Request) ([]Filter, error) {
 	return filters, nil
 }
 
+// parseFilterValues parses a parenthesized, comma-separated list of values.
+func parseFilterValues(list string) ([]any, error) {
+	if !strings.HasPrefix(list, "(") || !strings.HasSuffix(list, ")") {
+		return nil, fmt.Errorf("invalid list of values: %s", list)
+	}
+
+	var values []any
+	for _, value := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(list, "("), ")"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, fmt.Errorf("empty value in list: %s", list)
+		}
+		values = append(values, parseFilterValue(value))
+	}
+	return values, nil
+}
+
 func parseFilterValue(value string) any {
 	if value == "true" {
 		return true
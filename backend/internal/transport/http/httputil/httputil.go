@@ -65,16 +65,32 @@ func OK(w http.ResponseWriter, response any) {
 	writeResponse(w, response)
 }
 
+func Accepted(w http.ResponseWriter, response any) {
+	w.WriteHeader(http.StatusAccepted)
+	writeResponse(w, response)
+}
+
 func NoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// EmbedTokenInResponse issues a token for the player. The requester's session
+// is carried over, unless the auth info names one.
 func EmbedTokenInResponse(ctx context.Context, w http.ResponseWriter, authInfo *reqCtx.AuthInfo, rights []auth.Right) {
 	pID := utils.IntToString(authInfo.PlayerID)
-	newToken, err := auth.GenerateToken(pID, map[string]string{
+	claims := map[string]string{
 		"playerId": pID,
 		"groupId":  authInfo.GroupID,
-	}, rights...)
+	}
+	sessionID := authInfo.SessionID
+	if sessionID == "" {
+		sessionID = reqCtx.GetSessionID(ctx)
+	}
+	if sessionID != "" {
+		claims["sessionId"] = sessionID
+	}
+
+	newToken, err := auth.GenerateToken(pID, claims, rights...)
 	if err != nil {
 		InternalServerError(ctx, w, err)
 		return
@@ -112,6 +112,41 @@ func TestParseQueryParams(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name:  "Range Filter",
+			query: `filter=startsAt ge "2025-01-01T18:00:00Z" and startsAt le "2025-01-01T22:00:00Z"`,
+			expected: &QueryParams{
+				FilterBy: []Filter{
+					{Field: "startsAt", Op: Ge, Value: "2025-01-01T18:00:00Z"},
+					{Field: "startsAt", Op: Le, Value: "2025-01-01T22:00:00Z"},
+				},
+				PaginateBy: defaultPagination,
+			},
+			expectedError: false,
+		},
+		{
+			name:  "List Filter",
+			query: `filter=tag in ("chill", "18%2B",learning)`,
+			expected: &QueryParams{
+				FilterBy: []Filter{
+					{Field: "tag", Op: In, Value: []any{"chill", "18+", "learning"}},
+				},
+				PaginateBy: defaultPagination,
+			},
+			expectedError: false,
+		},
+		{
+			name:          "Malformed List Filter",
+			query:         `filter=tag in "chill", "learning"`,
+			expected:      nil,
+			expectedError: true,
+		},
+		{
+			name:          "Unsupported Filter Operator",
+			query:         `filter=field gt 3`,
+			expected:      nil,
+			expectedError: true,
+		},
 		{
 			name:          "Empty Sort Field",
 			query:         `sort=,-field`,
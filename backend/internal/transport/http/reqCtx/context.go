@@ -11,9 +11,10 @@ import (
 )
 
 type AuthInfo struct {
-	PlayerID int
-	GroupID  string
-	Token    string
+	PlayerID  int
+	GroupID   string
+	SessionID string
+	Token     string
 }
 
 type contextKey string
@@ -57,6 +58,14 @@ func GetGroupID(ctx context.Context) string {
 	return info.GroupID
 }
 
+func GetSessionID(ctx context.Context) string {
+	info, ok := GetAuthInfo(ctx)
+	if !ok {
+		return ""
+	}
+	return info.SessionID
+}
+
 func GetToken(ctx context.Context) string {
 	info, ok := GetAuthInfo(ctx)
 	if !ok {
@@ -105,18 +114,22 @@ func Init(r *http.Request, claims jwt.MapClaims, token string) *http.Request {
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, "request_id", uuid.New().String())
 
-	playerID, groupID := 0, ""
+	playerID, groupID, sessionID := 0, "", ""
 	if playerIDVal, ok := claims["playerId"]; ok {
 		playerID = utils.StringToInt(playerIDVal.(string))
 	}
 	if groupIDVal, ok := claims["groupId"]; ok {
 		groupID = groupIDVal.(string)
 	}
+	if sessionIDVal, ok := claims["sessionId"].(string); ok {
+		sessionID = sessionIDVal
+	}
 
 	info := &AuthInfo{
-		PlayerID: playerID,
-		GroupID:  groupID,
-		Token:    token,
+		PlayerID:  playerID,
+		GroupID:   groupID,
+		SessionID: sessionID,
+		Token:     token,
 	}
 	return r.WithContext(ctxWithAuthInfo(ctx, info))
 }
@@ -0,0 +1,314 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/jcserv/rivalslfg/internal/utils"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// CreateCommunity creates a community, with the player who created it as its
+// first moderator. Only admins and the default community's moderators can create them.
+func (a *API) CreateCommunity() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var input CreateCommunity
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		if input.PlayerID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		community, err := a.communityService.CreateCommunity(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, community)
+	}
+}
+
+func (a *API) GetCommunities() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		communities, err := a.communityService.GetCommunities(ctx)
+		if err != nil {
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, communities)
+	}
+}
+
+// GetCommunity returns the community along with its rules and moderators.
+func (a *API) GetCommunity() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		communityID := utils.StringToInt(vars["id"])
+		if communityID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("communityId is invalid"))
+			return
+		}
+
+		community, err := a.communityService.GetCommunity(ctx, int32(communityID))
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusNotFound {
+				httputil.NotFound(w)
+				return
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, community)
+	}
+}
+
+// UpdateCommunity changes the community. Only its moderators can change it.
+func (a *API) UpdateCommunity() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var input UpdateCommunity
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.ID = utils.StringToInt(vars["id"])
+		if input.ID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("communityId is invalid"))
+			return
+		}
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		if input.PlayerID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		community, err := a.communityService.UpdateCommunity(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, community)
+	}
+}
+
+// DeleteCommunity deletes the community once it has no groups left. Only its moderators can delete it.
+func (a *API) DeleteCommunity() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		moderatorID := reqCtx.GetPlayerID(ctx)
+		if moderatorID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+		vars := mux.Vars(r)
+		communityID := utils.StringToInt(vars["id"])
+		if communityID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("communityId is invalid"))
+			return
+		}
+
+		if err := a.communityService.DeleteCommunity(ctx, int32(communityID), int32(moderatorID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
+
+// AddCommunityModerator makes a player a moderator of the community. Only its moderators can add others.
+func (a *API) AddCommunityModerator() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		moderatorID := reqCtx.GetPlayerID(ctx)
+		if moderatorID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+
+		var input AddModerator
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+		if input.PlayerID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("playerId is invalid"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		communityID := utils.StringToInt(vars["id"])
+		if communityID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("communityId is invalid"))
+			return
+		}
+
+		if err := a.communityService.AddModerator(ctx, int32(communityID), int32(moderatorID), int32(input.PlayerID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
+
+// RemoveCommunityModerator stops a player from moderating the community.
+// Only its moderators can remove others, as long as one is left.
+func (a *API) RemoveCommunityModerator() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		moderatorID := reqCtx.GetPlayerID(ctx)
+		if moderatorID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+		vars := mux.Vars(r)
+		communityID := utils.StringToInt(vars["id"])
+		if communityID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("communityId is invalid"))
+			return
+		}
+		playerID := utils.StringToInt(vars["playerId"])
+		if playerID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("playerId is invalid"))
+			return
+		}
+
+		if err := a.communityService.RemoveModerator(ctx, int32(communityID), int32(moderatorID), int32(playerID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
+
+// DeleteCommunityGroup lets the community's moderators delete any group in it.
+func (a *API) DeleteCommunityGroup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		moderatorID := reqCtx.GetPlayerID(ctx)
+		if moderatorID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+		vars := mux.Vars(r)
+		communityID := utils.StringToInt(vars["id"])
+		if communityID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("communityId is invalid"))
+			return
+		}
+		groupID := vars["groupId"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		if err := a.groupService.DeleteCommunityGroup(ctx, int32(communityID), groupID, int32(moderatorID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
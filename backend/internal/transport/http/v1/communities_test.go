@@ -0,0 +1,290 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIntegration_CreateCommunity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockCommunityService := mocks.NewMockICommunity(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:     mocks.NewMockIGroup(ctrl),
+			CommunityService: mockCommunityService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the player as its moderator", func(t *testing.T) {
+		mockCommunityService.EXPECT().CreateCommunity(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(services.CreateCommunityParams)
+			return arg.Name == "Grandmasters" && arg.CreatedBy == 2 && arg.Rules.MinRank == "gm3" && arg.Rules.InviteOnly
+		})).Return(&services.CommunityDTO{
+			ID:         3,
+			Name:       "Grandmasters",
+			Rules:      services.CommunityRules{MinRank: "gm3", Regions: []string{}, InviteOnly: true},
+			Moderators: []services.CommunityModeratorDTO{{PlayerID: 2, Name: "imphungky"}},
+		}, nil)
+		body := test.GetBody(map[string]any{
+			"name":  "Grandmasters",
+			"rules": map[string]any{"minRank": "gm3", "inviteOnly": true},
+		})
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/communities", body), 2, ""))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"playerId":2`)
+	})
+	t.Run("Should return 400 if the rules are invalid", func(t *testing.T) {
+		body := test.GetBody(map[string]any{
+			"name":  "Grandmasters",
+			"rules": map[string]any{"minRank": "gm3", "maxRank": "d3"},
+		})
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/communities", body), 2, ""))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if the player doesn't moderate the default community", func(t *testing.T) {
+		mockCommunityService.EXPECT().CreateCommunity(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Only the community's moderators can do that.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/communities", test.GetBody(map[string]any{"name": "Grandmasters"})), 3, ""))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 401 without a token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/communities", test.GetBody(map[string]any{"name": "Grandmasters"}))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestIntegration_GetCommunity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockCommunityService := mocks.NewMockICommunity(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:     mocks.NewMockIGroup(ctrl),
+			CommunityService: mockCommunityService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with every community", func(t *testing.T) {
+		mockCommunityService.EXPECT().GetCommunities(gomock.Any()).Return([]services.CommunityDTO{
+			{ID: 1, Name: "Rivals LFG"},
+		}, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/communities", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Rivals LFG")
+	})
+	t.Run("Should return 200 with the community", func(t *testing.T) {
+		mockCommunityService.EXPECT().GetCommunity(gomock.Any(), int32(1)).Return(&services.CommunityDTO{ID: 1, Name: "Rivals LFG"}, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/communities/1", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Should return 404 if the community doesn't exist", func(t *testing.T) {
+		mockCommunityService.EXPECT().GetCommunity(gomock.Any(), int32(9)).Return(nil, services.NewError(http.StatusNotFound, "Community not found.", nil))
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/communities/9", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 400 if the id isn't a number", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/communities/abc", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestIntegration_UpdateCommunity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockCommunityService := mocks.NewMockICommunity(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:     mocks.NewMockIGroup(ctrl),
+			CommunityService: mockCommunityService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the changed community", func(t *testing.T) {
+		mockCommunityService.EXPECT().UpdateCommunity(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(services.UpdateCommunityParams)
+			return arg.ID == 3 && arg.ModeratorID == 2 && arg.Name == nil && arg.Rules != nil && len(arg.Rules.Regions) == 1
+		})).Return(&services.CommunityDTO{ID: 3}, nil)
+		body := test.GetBody(map[string]any{"rules": map[string]any{"regions": []string{"eu"}}})
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/communities/3", body), 2, ""))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Should return 400 if nothing changes", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/communities/3", test.GetBody(map[string]any{})), 2, ""))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if the player doesn't moderate the community", func(t *testing.T) {
+		mockCommunityService.EXPECT().UpdateCommunity(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Only the community's moderators can do that.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/communities/3", test.GetBody(map[string]any{"name": "Grandmasters"})), 4, ""))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 401 without a token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/communities/3", test.GetBody(map[string]any{"name": "Grandmasters"}))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestIntegration_DeleteCommunity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockCommunityService := mocks.NewMockICommunity(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:     mocks.NewMockIGroup(ctrl),
+			CommunityService: mockCommunityService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 204 once the community is deleted", func(t *testing.T) {
+		mockCommunityService.EXPECT().DeleteCommunity(gomock.Any(), int32(3), int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/communities/3", nil), 2, ""))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 400 if the community still has groups", func(t *testing.T) {
+		mockCommunityService.EXPECT().DeleteCommunity(gomock.Any(), int32(3), int32(2)).Return(services.NewError(http.StatusBadRequest, "Community still has groups.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/communities/3", nil), 2, ""))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 500 if the community can't be deleted", func(t *testing.T) {
+		mockCommunityService.EXPECT().DeleteCommunity(gomock.Any(), int32(3), int32(2)).Return(fmt.Errorf("unexpected error"))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/communities/3", nil), 2, ""))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestIntegration_CommunityModerators(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockCommunityService := mocks.NewMockICommunity(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:     mocks.NewMockIGroup(ctrl),
+			CommunityService: mockCommunityService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 204 once the moderator is added", func(t *testing.T) {
+		mockCommunityService.EXPECT().AddModerator(gomock.Any(), int32(3), int32(2), int32(4)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/communities/3/moderators", test.GetBody(map[string]any{"playerId": 4})), 2, ""))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 404 if the player to add doesn't exist", func(t *testing.T) {
+		mockCommunityService.EXPECT().AddModerator(gomock.Any(), int32(3), int32(2), int32(9)).Return(services.NewError(http.StatusNotFound, "Player not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/communities/3/moderators", test.GetBody(map[string]any{"playerId": 9})), 2, ""))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 204 once the moderator is removed", func(t *testing.T) {
+		mockCommunityService.EXPECT().RemoveModerator(gomock.Any(), int32(3), int32(2), int32(4)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/communities/3/moderators/4", nil), 2, ""))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 400 if the last moderator would be removed", func(t *testing.T) {
+		mockCommunityService.EXPECT().RemoveModerator(gomock.Any(), int32(3), int32(2), int32(2)).Return(services.NewError(http.StatusBadRequest, "Communities need at least one moderator.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/communities/3/moderators/2", nil), 2, ""))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestIntegration_DeleteCommunityGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:     mockGroupService,
+			CommunityService: mocks.NewMockICommunity(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 204 once a moderator deletes the group", func(t *testing.T) {
+		mockGroupService.EXPECT().DeleteCommunityGroup(gomock.Any(), int32(3), "AAAA", int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/communities/3/groups/AAAA", nil), 2, ""))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 403 if the player doesn't moderate the community", func(t *testing.T) {
+		mockGroupService.EXPECT().DeleteCommunityGroup(gomock.Any(), int32(3), "AAAA", int32(4)).Return(services.NewError(http.StatusForbidden, "Only the community's moderators can do that.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/communities/3/groups/AAAA", nil), 4, ""))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the group isn't in the community", func(t *testing.T) {
+		mockGroupService.EXPECT().DeleteCommunityGroup(gomock.Any(), int32(3), "BBBB", int32(2)).Return(services.NewError(http.StatusNotFound, "Group not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/communities/3/groups/BBBB", nil), 2, ""))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
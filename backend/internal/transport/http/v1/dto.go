@@ -2,11 +2,16 @@ package v1
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jcserv/rivalslfg/internal/services"
 	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
 	"github.com/jcserv/rivalslfg/internal/types"
+	"github.com/jcserv/rivalslfg/internal/utils"
 
 	"github.com/jcserv/rivalslfg/internal/repository"
 )
@@ -38,8 +43,22 @@ func Parse(params *httputil.QueryParams) (*repository.GetGroupsParams, error) {
 	return args, nil
 }
 
+// filterOps are the operators that each filter supports, which is only eq unless listed.
+var filterOps = map[string][]httputil.FilterOp{
+	"startsAt": {httputil.Ge, httputil.Le},
+	"tag":      {httputil.Eq, httputil.In},
+	"language": {httputil.Eq, httputil.In},
+}
+
 func parseFilters(args *repository.GetGroupsParams, filterBy []httputil.Filter) error {
 	for _, filter := range filterBy {
+		ops, ok := filterOps[filter.Field]
+		if !ok {
+			ops = []httputil.FilterOp{httputil.Eq}
+		}
+		if !slices.Contains(ops, filter.Op) {
+			return fmt.Errorf("unsupported operator for %s filter", filter.Field)
+		}
 		if filter.Field == "region" {
 			switch filter.Value.(type) {
 			case string:
@@ -72,10 +91,81 @@ func parseFilters(args *repository.GetGroupsParams, filterBy []httputil.Filter)
 				return fmt.Errorf("invalid type value for open filter value")
 			}
 		}
+		if filter.Field == "community" {
+			switch filter.Value.(type) {
+			case int:
+				communityID := int32(filter.Value.(int))
+				args.CommunityFilter = &communityID
+			default:
+				return fmt.Errorf("invalid type value for community filter value")
+			}
+		}
+		if filter.Field == "startsAt" {
+			value, ok := filter.Value.(string)
+			if !ok {
+				return fmt.Errorf("invalid type value for startsAt filter value")
+			}
+			startsAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return fmt.Errorf("invalid value for startsAt filter, expected an RFC 3339 timestamp")
+			}
+			if filter.Op == httputil.Ge {
+				args.StartsAfter = &startsAt
+			} else {
+				args.StartsBefore = &startsAt
+			}
+		}
+		// tag eq requires the tag, while tag in requires any of the tags
+		if filter.Field == "tag" {
+			tags, err := filterStrings(filter)
+			if err != nil {
+				return err
+			}
+			for _, tag := range tags {
+				if !types.Tags.Contains(tag) {
+					return fmt.Errorf("invalid value for tag filter, %s is not a tag", tag)
+				}
+			}
+			if filter.Op == httputil.Eq {
+				args.TagsFilter = append(args.TagsFilter, tags...)
+			} else {
+				args.AnyTagsFilter = append(args.AnyTagsFilter, tags...)
+			}
+		}
+		if filter.Field == "language" {
+			languages, err := filterStrings(filter)
+			if err != nil {
+				return err
+			}
+			for _, language := range languages {
+				if !types.Languages.Contains(language) {
+					return fmt.Errorf("invalid value for language filter, %s is not supported", language)
+				}
+			}
+			args.LanguagesFilter = languages
+		}
 	}
 	return nil
 }
 
+// filterStrings returns the filter's value, or each of its values for in, lowercased.
+func filterStrings(filter httputil.Filter) ([]string, error) {
+	values := []any{filter.Value}
+	if filter.Op == httputil.In {
+		values, _ = filter.Value.([]any)
+	}
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid type value for %s filter value", filter.Field)
+		}
+		result = append(result, strings.ToLower(s))
+	}
+	return result, nil
+}
+
 func parseSorting(args *repository.GetGroupsParams, sorters []httputil.Sort) error {
 	for _, sorter := range sorters {
 		field := strings.ToLower(sorter.Field)
@@ -86,6 +176,14 @@ func parseSorting(args *repository.GetGroupsParams, sorters []httputil.Sort) err
 			}
 			args.SizeSort = "desc"
 		}
+		// Sorting by composition needs the player's requirements, see PlayerRequirements
+		if field == "composition" {
+			if sorter.Ascending {
+				args.CompositionSort = "asc"
+				continue
+			}
+			args.CompositionSort = "desc"
+		}
 	}
 	return nil
 }
@@ -98,13 +196,16 @@ func parsePagination(args *repository.GetGroupsParams, paginateBy *httputil.Offs
 }
 
 type PlayerRequirements struct {
-	Gamemode  string `json:"gamemode,omitempty"`
-	Region    string `json:"region,omitempty"`
-	Platform  string `json:"platform,omitempty"`
-	Role      string `json:"role,omitempty"`
-	RankID    string `json:"rank,omitempty"`
-	VoiceChat bool   `json:"voiceChat,omitempty"`
-	Mic       bool   `json:"mic,omitempty"`
+	Gamemode  string   `json:"gamemode,omitempty"`
+	Region    string   `json:"region,omitempty"`
+	Platform  string   `json:"platform,omitempty"`
+	Role      string   `json:"role,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	RankID    string   `json:"rank,omitempty"`
+	VoiceChat bool     `json:"voiceChat,omitempty"`
+	Mic       bool     `json:"mic,omitempty"`
+	// Only used to sort by composition
+	Characters []string `json:"characters,omitempty"`
 }
 
 func (p *PlayerRequirements) Validate() error {
@@ -130,8 +231,8 @@ func (p *PlayerRequirements) Validate() error {
 		}
 	}
 
-	if p.Role != "" {
-		if err := types.ValidateRole(p.Role); err != nil {
+	if p.Role != "" || len(p.Roles) > 0 {
+		if err := types.ValidateRolePreferences(p.Role, p.Roles); err != nil {
 			return err
 		}
 	}
@@ -183,9 +284,11 @@ func (p *PlayerRequirements) ToParams() (*repository.GetGroupsParams, error) {
 		RegionFilter:   region,
 		Platform:       platform,
 		Role:           role,
+		Roles:          types.RolePreferences(p.Role, p.Roles),
 		RankVal:        rankVal,
 		VoiceChat:      voiceChat,
 		Mic:            mic,
+		Characters:     p.Characters,
 	}, nil
 }
 
@@ -196,6 +299,17 @@ type CreateGroup struct {
 	Region   string `json:"region"`
 	Gamemode string `json:"gamemode"`
 	Open     bool   `json:"open"`
+	// RequestToJoin lets players ask the owner to join a private group, as well as joining with the passcode
+	RequestToJoin bool `json:"requestToJoin"`
+	// CommunityID defaults to services.DefaultCommunityID
+	CommunityID int `json:"communityId"`
+	// StartsAt schedules the group for later, which players RSVP to until it starts
+	StartsAt        *time.Time `json:"startsAt"`
+	DurationMinutes int        `json:"durationMinutes"`
+
+	Description string   `json:"description"`
+	Language    string   `json:"language"`
+	Tags        []string `json:"tags"`
 
 	Platform   string   `json:"platform"`
 	Role       string   `json:"role"`
@@ -243,10 +357,38 @@ func (c *CreateGroup) validate() error {
 		return fmt.Errorf("gamemode %s is not supported", c.Gamemode)
 	}
 
+	if c.Open && c.RequestToJoin {
+		return fmt.Errorf("open groups can't take join requests")
+	}
+
+	if c.CommunityID < 0 {
+		return fmt.Errorf("communityId %d is invalid", c.CommunityID)
+	}
+
+	if err := c.validateSchedule(); err != nil {
+		return err
+	}
+
+	if err := types.ValidateDescription(c.Description); err != nil {
+		return err
+	}
+
+	if err := types.ValidateLanguage(c.Language); err != nil {
+		return err
+	}
+
+	if err := types.ValidateTags(c.Tags); err != nil {
+		return err
+	}
+
 	if err := types.ValidateRoleQueue(c.Vanguards, c.Duelists, c.Strategists); err != nil {
 		return err
 	}
 
+	if err := types.ValidateTeamSize(c.Gamemode, c.Vanguards, c.Duelists, c.Strategists); err != nil {
+		return err
+	}
+
 	if c.GroupPlatform != "" {
 		if err := types.ValidatePlatform(c.GroupPlatform); err != nil {
 			return err
@@ -255,6 +397,29 @@ func (c *CreateGroup) validate() error {
 	return nil
 }
 
+func (c *CreateGroup) validateSchedule() error {
+	if c.StartsAt == nil {
+		if c.DurationMinutes != 0 {
+			return fmt.Errorf("durationMinutes can only be set with startsAt")
+		}
+		return nil
+	}
+
+	untilStart := time.Until(*c.StartsAt)
+	if untilStart <= 0 {
+		return fmt.Errorf("startsAt must be in the future")
+	}
+	if untilStart > services.MaxScheduleAhead {
+		return fmt.Errorf("startsAt must be within %s", services.MaxScheduleAhead)
+	}
+
+	maxDuration := int(services.MaxScheduledDuration / time.Minute)
+	if c.DurationMinutes < 1 || c.DurationMinutes > maxDuration {
+		return fmt.Errorf("durationMinutes must be between 1 and %d", maxDuration)
+	}
+	return nil
+}
+
 func (c *CreateGroup) Parse() (*repository.CreateGroupParams, error) {
 	params := &repository.CreateGroupParams{}
 
@@ -274,6 +439,15 @@ func (c *CreateGroup) Parse() (*repository.CreateGroupParams, error) {
 	params.Region = c.Region
 	params.Gamemode = c.Gamemode
 	params.Open = c.Open
+	params.RequestToJoin = c.RequestToJoin
+	params.CommunityID = int32(c.CommunityID)
+	if c.StartsAt != nil {
+		params.StartsAt = pgtype.Timestamptz{Time: *c.StartsAt, Valid: true}
+		params.DurationMinutes = pgtype.Int4{Int32: int32(c.DurationMinutes), Valid: true}
+	}
+	params.Description = strings.TrimSpace(c.Description)
+	params.Language = strings.ToLower(c.Language)
+	params.Tags = utils.StringSliceToLower(c.Tags)
 
 	params.Vanguards = int32(c.Vanguards)
 	params.Duelists = int32(c.Duelists)
@@ -286,16 +460,255 @@ func (c *CreateGroup) Parse() (*repository.CreateGroupParams, error) {
 	return params, nil
 }
 
+// UpdateGroup changes the group's settings. Settings that are left out keep their current value.
+type UpdateGroup struct {
+	GroupID  string `json:"groupId"`
+	PlayerID int    `json:"playerId"`
+
+	Region        *string `json:"region"`
+	Gamemode      *string `json:"gamemode"`
+	Open          *bool   `json:"open"`
+	RequestToJoin *bool   `json:"requestToJoin"`
+	Vanguards     *int    `json:"vanguards"`
+	Duelists      *int    `json:"duelists"`
+	Strategists   *int    `json:"strategists"`
+	Platform      *string `json:"platform"`
+	VoiceChat     *bool   `json:"voiceChat"`
+	Mic           *bool   `json:"mic"`
+
+	Description *string   `json:"description"`
+	Language    *string   `json:"language"`
+	Tags        *[]string `json:"tags"`
+}
+
+func (c *UpdateGroup) validate() error {
+	if c.GroupID == "" {
+		return fmt.Errorf("groupId is required")
+	}
+
+	if c.Region == nil && c.Gamemode == nil && c.Open == nil && c.RequestToJoin == nil &&
+		c.Vanguards == nil && c.Duelists == nil && c.Strategists == nil &&
+		c.Platform == nil && c.VoiceChat == nil && c.Mic == nil &&
+		c.Description == nil && c.Language == nil && c.Tags == nil {
+		return fmt.Errorf("at least one setting is required")
+	}
+
+	if valueOr(c.Open, false) && valueOr(c.RequestToJoin, false) {
+		return fmt.Errorf("open groups can't take join requests")
+	}
+
+	if c.Region != nil {
+		if err := types.ValidateRegion(*c.Region); err != nil {
+			return err
+		}
+	}
+
+	if c.Gamemode != nil {
+		if err := types.ValidateGamemode(*c.Gamemode); err != nil {
+			return err
+		}
+	}
+
+	// Slots that are left out keep their current value, which is already valid
+	if err := types.ValidateRoleQueue(valueOr(c.Vanguards, 0), valueOr(c.Duelists, 0), valueOr(c.Strategists, 0)); err != nil {
+		return err
+	}
+
+	if c.Platform != nil {
+		if err := types.ValidatePlatform(*c.Platform); err != nil {
+			return err
+		}
+	}
+
+	if c.Description != nil {
+		if err := types.ValidateDescription(*c.Description); err != nil {
+			return err
+		}
+	}
+
+	if c.Language != nil {
+		if err := types.ValidateLanguage(*c.Language); err != nil {
+			return err
+		}
+	}
+
+	if c.Tags != nil {
+		if err := types.ValidateTags(*c.Tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *UpdateGroup) Parse() (*services.UpdateGroupParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &services.UpdateGroupParams{}
+	params.GroupID = c.GroupID
+	params.OwnerID = int32(c.PlayerID)
+	params.Region = c.Region
+	params.Gamemode = c.Gamemode
+	params.Open = c.Open
+	params.RequestToJoin = c.RequestToJoin
+	params.Vanguards = c.Vanguards
+	params.Duelists = c.Duelists
+	params.Strategists = c.Strategists
+	params.Platform = c.Platform
+	params.VoiceChat = c.VoiceChat
+	params.Mic = c.Mic
+	if c.Description != nil {
+		description := strings.TrimSpace(*c.Description)
+		params.Description = &description
+	}
+	if c.Language != nil {
+		language := strings.ToLower(*c.Language)
+		params.Language = &language
+	}
+	if c.Tags != nil {
+		tags := utils.StringSliceToLower(*c.Tags)
+		params.Tags = &tags
+	}
+	return params, nil
+}
+
+func valueOr[T any](value *T, fallback T) T {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+type CreateCommunity struct {
+	PlayerID    int                     `json:"playerId"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Link        string                  `json:"link"`
+	Rules       services.CommunityRules `json:"rules"`
+}
+
+func (c *CreateCommunity) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	return c.Rules.Validate()
+}
+
+func (c *CreateCommunity) Parse() (*services.CreateCommunityParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &services.CreateCommunityParams{}
+	params.Name = c.Name
+	params.Description = c.Description
+	params.Link = c.Link
+	params.Rules = c.Rules
+	params.CreatedBy = int32(c.PlayerID)
+	return params, nil
+}
+
+// UpdateCommunity changes the community. Fields that are left out keep their
+// current value, and rules are replaced as a whole.
+type UpdateCommunity struct {
+	ID       int `json:"id"`
+	PlayerID int `json:"playerId"`
+
+	Name        *string                  `json:"name"`
+	Description *string                  `json:"description"`
+	Link        *string                  `json:"link"`
+	Rules       *services.CommunityRules `json:"rules"`
+}
+
+func (c *UpdateCommunity) validate() error {
+	if c.Name == nil && c.Description == nil && c.Link == nil && c.Rules == nil {
+		return fmt.Errorf("at least one field is required")
+	}
+	if c.Name != nil && *c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if c.Rules != nil {
+		return c.Rules.Validate()
+	}
+	return nil
+}
+
+func (c *UpdateCommunity) Parse() (*services.UpdateCommunityParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &services.UpdateCommunityParams{}
+	params.ID = int32(c.ID)
+	params.ModeratorID = int32(c.PlayerID)
+	params.Name = c.Name
+	params.Description = c.Description
+	params.Link = c.Link
+	params.Rules = c.Rules
+	return params, nil
+}
+
+type AddModerator struct {
+	PlayerID int `json:"playerId"`
+}
+
+type RotatePasscode struct {
+	RevokeInvites bool `json:"revokeInvites"`
+}
+
+type CreateInvite struct {
+	GroupID  string `json:"groupId"`
+	PlayerID int    `json:"playerId"`
+
+	// MaxUses defaults to a single use
+	MaxUses *int `json:"maxUses"`
+	// ExpiresIn is a duration like "30m", and defaults to services.DefaultInviteTTL
+	ExpiresIn string `json:"expiresIn"`
+}
+
+func (c *CreateInvite) validate() error {
+	if c.GroupID == "" {
+		return fmt.Errorf("groupId is required")
+	}
+	if c.MaxUses != nil && (*c.MaxUses < 1 || *c.MaxUses > services.MaxInviteUses) {
+		return fmt.Errorf("maxUses must be between 1 and %d", services.MaxInviteUses)
+	}
+	return nil
+}
+
+func (c *CreateInvite) Parse() (*services.CreateInviteParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &services.CreateInviteParams{}
+	params.GroupID = c.GroupID
+	params.CreatedBy = int32(c.PlayerID)
+	params.MaxUses = valueOr(c.MaxUses, 1)
+	params.ExpiresIn = services.DefaultInviteTTL
+	if c.ExpiresIn != "" {
+		expiresIn, err := time.ParseDuration(c.ExpiresIn)
+		if err != nil {
+			return nil, fmt.Errorf("expiresIn %s is invalid", c.ExpiresIn)
+		}
+		if expiresIn <= 0 || expiresIn > services.MaxInviteTTL {
+			return nil, fmt.Errorf("expiresIn must be positive and at most %gh", services.MaxInviteTTL.Hours())
+		}
+		params.ExpiresIn = expiresIn
+	}
+	return params, nil
+}
+
 type JoinGroup struct {
 	GroupID  string `json:"groupId"`
 	PlayerID int    `json:"playerId"`
 
 	Name        string   `json:"name"`
 	Passcode    string   `json:"passcode"`
+	Invite      string   `json:"invite"`
+	SessionID   string   `json:"-"`
 	Platform    string   `json:"platform"`
 	Gamemode    string   `json:"gamemode"`
 	Region      string   `json:"region"`
 	Role        string   `json:"role"`
+	Roles       []string `json:"roles"`
 	RankID      string   `json:"rankId"`
 	Characters  []string `json:"characters"`
 	VoiceChat   bool     `json:"voiceChat"`
@@ -326,7 +739,7 @@ func (c *JoinGroup) validate() error {
 		return err
 	}
 
-	if err := types.ValidateRole(c.Role); err != nil {
+	if err := types.ValidateRolePreferences(c.Role, c.Roles); err != nil {
 		return err
 	}
 
@@ -350,10 +763,16 @@ func (c *JoinGroup) Parse() (*repository.JoinGroupParams, error) {
 	params.Gamemode = c.Gamemode
 	params.Region = c.Region
 	params.Platform = c.Platform
-	params.Role = strings.ToLower(c.Role)
+	params.Roles = types.RolePreferences(c.Role, c.Roles)
+	params.Role = params.Roles[0]
+	if c.Role != "" {
+		params.Role = strings.ToLower(c.Role)
+	}
 	params.RankVal = int32(types.RankIDToRankVal[c.RankID])
 	params.Name = c.Name
 	params.Passcode = c.Passcode
+	params.Invite = c.Invite
+	params.SessionID = c.SessionID
 	params.Characters = c.Characters
 	params.VoiceChat = c.VoiceChat
 	params.Mic = c.Mic
@@ -363,9 +782,121 @@ func (c *JoinGroup) Parse() (*repository.JoinGroupParams, error) {
 	return params, nil
 }
 
+// JoinWaitlist is a player waiting on a full group, with the same details
+// they'd join it with. ForRole limits them to slots for that role.
+type JoinWaitlist struct {
+	JoinGroup
+	ForRole string `json:"forRole"`
+}
+
+func (c *JoinWaitlist) Parse() (*services.JoinWaitlistParams, error) {
+	player, err := c.JoinGroup.Parse()
+	if err != nil {
+		return nil, err
+	}
+	params := &services.JoinWaitlistParams{}
+	params.Player = *player
+	if c.ForRole != "" {
+		if err := types.ValidateRole(c.ForRole); err != nil {
+			return nil, err
+		}
+		params.Role = strings.ToLower(c.ForRole)
+		if !slices.Contains(player.Roles, params.Role) {
+			return nil, fmt.Errorf("forRole %s must be one of the player's roles", c.ForRole)
+		}
+	}
+	return params, nil
+}
+
+type Eligibility struct {
+	GroupID string `json:"groupId"`
+
+	Gamemode  string   `json:"gamemode"`
+	Region    string   `json:"region"`
+	Platform  string   `json:"platform"`
+	Role      string   `json:"role"`
+	Roles     []string `json:"roles"`
+	RankID    string   `json:"rankId"`
+	VoiceChat bool     `json:"voiceChat"`
+	Mic       bool     `json:"mic"`
+}
+
+// EligibilityFromQuery reads the player's info from the query string, e.g. ?gamemode=competitive&rankId=d3&roles=duelist,strategist
+func EligibilityFromQuery(groupID string, q url.Values) Eligibility {
+	return Eligibility{
+		GroupID:   groupID,
+		Gamemode:  q.Get("gamemode"),
+		Region:    q.Get("region"),
+		Platform:  q.Get("platform"),
+		Role:      q.Get("role"),
+		Roles:     splitList(q.Get("roles")),
+		RankID:    q.Get("rankId"),
+		VoiceChat: strings.EqualFold(q.Get("voiceChat"), "true"),
+		Mic:       strings.EqualFold(q.Get("mic"), "true"),
+	}
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func (c *Eligibility) validate() error {
+	if c.GroupID == "" {
+		return fmt.Errorf("groupId is required")
+	}
+
+	if err := types.ValidateGamemode(c.Gamemode); err != nil {
+		return err
+	}
+
+	if err := types.ValidateRegion(c.Region); err != nil {
+		return err
+	}
+
+	if err := types.ValidatePlatform(c.Platform); err != nil {
+		return err
+	}
+
+	if err := types.ValidateRolePreferences(c.Role, c.Roles); err != nil {
+		return err
+	}
+
+	if valid := types.IsValidRankID(c.RankID); !valid {
+		return fmt.Errorf("rankId %s is invalid", c.RankID)
+	}
+	return nil
+}
+
+func (c *Eligibility) Parse() (*repository.JoinGroupParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &repository.JoinGroupParams{}
+	params.GroupID = c.GroupID
+	params.Gamemode = c.Gamemode
+	params.Region = c.Region
+	params.Platform = c.Platform
+	params.Roles = types.RolePreferences(c.Role, c.Roles)
+	params.Role = params.Roles[0]
+	if c.Role != "" {
+		params.Role = strings.ToLower(c.Role)
+	}
+	params.RankVal = int32(types.RankIDToRankVal[c.RankID])
+	params.VoiceChat = c.VoiceChat
+	params.Mic = c.Mic
+	return params, nil
+}
+
 type RemovePlayer struct {
 	GroupID          string `json:"groupId"`
 	PlayerToRemoveID int    `json:"playerToRemoveId"`
+
+	// Ban keeps the player from rejoining the group, until the owner lifts it
+	Ban      bool `json:"ban"`
+	BannedBy int  `json:"bannedBy"`
 }
 
 func (c *RemovePlayer) validate() error {
@@ -375,6 +906,9 @@ func (c *RemovePlayer) validate() error {
 	if c.PlayerToRemoveID <= 0 {
 		return fmt.Errorf("playerId is required")
 	}
+	if c.Ban && c.BannedBy == c.PlayerToRemoveID {
+		return fmt.Errorf("players cannot ban themselves")
+	}
 	return nil
 }
 
@@ -385,5 +919,290 @@ func (c *RemovePlayer) Parse() (*repository.RemovePlayerParams, error) {
 	params := &repository.RemovePlayerParams{}
 	params.GroupID = c.GroupID
 	params.PlayerID = int32(c.PlayerToRemoveID)
+	params.Ban = c.Ban
+	params.BannedBy = int32(c.BannedBy)
+	return params, nil
+}
+
+// ProposeMerge asks the owner of the target group to take in the members of the group.
+type ProposeMerge struct {
+	GroupID  string `json:"groupId"`
+	PlayerID int    `json:"playerId"`
+
+	TargetID string `json:"targetId"`
+}
+
+func (c *ProposeMerge) validate() error {
+	if c.GroupID == "" {
+		return fmt.Errorf("groupId is required")
+	}
+	if c.TargetID == "" {
+		return fmt.Errorf("targetId is required")
+	}
+	if c.GroupID == c.TargetID {
+		return fmt.Errorf("groups cannot be merged with themselves")
+	}
+	return nil
+}
+
+func (c *ProposeMerge) Parse() (*services.ProposeMergeParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &services.ProposeMergeParams{}
+	params.SourceID = c.GroupID
+	params.TargetID = c.TargetID
+	params.OwnerID = int32(c.PlayerID)
+	return params, nil
+}
+
+// UpdateMember changes a member's role or character pool. Whatever is left out stays the same.
+type UpdateMember struct {
+	GroupID  string `json:"groupId"`
+	PlayerID int    `json:"playerId"`
+
+	Role       *string   `json:"role"`
+	Characters *[]string `json:"characters"`
+}
+
+func (c *UpdateMember) validate() error {
+	if c.GroupID == "" {
+		return fmt.Errorf("groupId is required")
+	}
+	if c.PlayerID <= 0 {
+		return fmt.Errorf("playerId is required")
+	}
+	if c.Role == nil && c.Characters == nil {
+		return fmt.Errorf("role or characters is required")
+	}
+
+	if c.Role != nil {
+		if err := types.ValidateRole(*c.Role); err != nil {
+			return err
+		}
+	}
+
+	if c.Characters != nil {
+		if err := types.ValidateCharacters(*c.Characters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *UpdateMember) Parse() (*services.UpdateMemberParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &services.UpdateMemberParams{}
+	params.GroupID = c.GroupID
+	params.PlayerID = int32(c.PlayerID)
+	if c.Role != nil {
+		role := strings.ToLower(*c.Role)
+		params.Role = &role
+	}
+	params.Characters = c.Characters
+	return params, nil
+}
+
+type Queue struct {
+	PlayerID int `json:"playerId"`
+	// Queues the player's party along with them, if they lead one.
+	PartyID string `json:"partyId"`
+
+	Name        string   `json:"name"`
+	Platform    string   `json:"platform"`
+	Gamemode    string   `json:"gamemode"`
+	Region      string   `json:"region"`
+	Role        string   `json:"role"`
+	Roles       []string `json:"roles"`
+	RankID      string   `json:"rankId"`
+	Characters  []string `json:"characters"`
+	VoiceChat   bool     `json:"voiceChat"`
+	Mic         bool     `json:"mic"`
+	Vanguards   int      `json:"vanguards"`
+	Duelists    int      `json:"duelists"`
+	Strategists int      `json:"strategists"`
+
+	// Relaxation loosens the player's constraints the longer they wait. The default schedule is used if
+	// empty, and steps can't relax ranks any faster than it does.
+	Relaxation []RelaxationStep `json:"relaxation"`
+}
+
+// RelaxationStep applies its constraints once the player has waited for After, e.g. "3m".
+type RelaxationStep struct {
+	After            string `json:"after"`
+	MaxRankSpread    int    `json:"maxRankSpread"`
+	RequireVoiceChat bool   `json:"requireVoiceChat"`
+}
+
+func (c *Queue) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("playerName is required")
+	}
+
+	if err := types.ValidateGamemode(c.Gamemode); err != nil {
+		return err
+	}
+
+	if err := types.ValidateRegion(c.Region); err != nil {
+		return err
+	}
+
+	if err := types.ValidatePlatform(c.Platform); err != nil {
+		return err
+	}
+
+	if err := types.ValidateRolePreferences(c.Role, c.Roles); err != nil {
+		return err
+	}
+
+	if valid := types.IsValidRankID(c.RankID); !valid {
+		return fmt.Errorf("rankId %s is invalid", c.RankID)
+	}
+
+	if err := types.ValidateRoleQueue(c.Vanguards, c.Duelists, c.Strategists); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Queue) Parse() (*repository.JoinGroupParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &repository.JoinGroupParams{}
+	params.PlayerID = int32(c.PlayerID)
+	params.Gamemode = c.Gamemode
+	params.Region = c.Region
+	params.Platform = c.Platform
+	params.Roles = types.RolePreferences(c.Role, c.Roles)
+	params.Role = params.Roles[0]
+	if c.Role != "" {
+		params.Role = strings.ToLower(c.Role)
+	}
+	params.RankVal = int32(types.RankIDToRankVal[c.RankID])
+	params.Name = c.Name
+	params.Characters = c.Characters
+	params.VoiceChat = c.VoiceChat
+	params.Mic = c.Mic
+	params.Vanguards = int32(c.Vanguards)
+	params.Duelists = int32(c.Duelists)
+	params.Strategists = int32(c.Strategists)
+	return params, nil
+}
+
+// ParseRelaxation returns the player's relaxation schedule, or nil if they didn't give one.
+func (c *Queue) ParseRelaxation() (services.RelaxationSchedule, error) {
+	if len(c.Relaxation) == 0 {
+		return nil, nil
+	}
+
+	schedule := make(services.RelaxationSchedule, 0, len(c.Relaxation))
+	for _, step := range c.Relaxation {
+		after, err := time.ParseDuration(step.After)
+		if err != nil {
+			return nil, fmt.Errorf("relaxation after %s is invalid", step.After)
+		}
+		if step.RequireVoiceChat && !c.VoiceChat {
+			return nil, fmt.Errorf("voiceChat is required to requireVoiceChat")
+		}
+		schedule = append(schedule, services.RelaxationStep{
+			After: after,
+			Constraints: services.Constraints{
+				MaxRankSpread:    step.MaxRankSpread,
+				RequireVoiceChat: step.RequireVoiceChat,
+			},
+		})
+	}
+	if err := schedule.ValidateFor(c.Gamemode); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// JoinParty is the player's info, used both to create a party and to join one.
+// Parties pick a gamemode and region once they queue or join a group.
+type JoinParty struct {
+	PartyID  string `json:"partyId"`
+	PlayerID int    `json:"playerId"`
+
+	Name       string   `json:"name"`
+	Platform   string   `json:"platform"`
+	Role       string   `json:"role"`
+	Roles      []string `json:"roles"`
+	RankID     string   `json:"rankId"`
+	Characters []string `json:"characters"`
+	VoiceChat  bool     `json:"voiceChat"`
+	Mic        bool     `json:"mic"`
+	SessionID  string   `json:"-"`
+}
+
+func (c *JoinParty) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("playerName is required")
+	}
+
+	if err := types.ValidatePlatform(c.Platform); err != nil {
+		return err
+	}
+
+	if err := types.ValidateRolePreferences(c.Role, c.Roles); err != nil {
+		return err
+	}
+
+	if valid := types.IsValidRankID(c.RankID); !valid {
+		return fmt.Errorf("rankId %s is invalid", c.RankID)
+	}
+	return nil
+}
+
+func (c *JoinParty) Parse() (*repository.JoinGroupParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &repository.JoinGroupParams{}
+	params.PlayerID = int32(c.PlayerID)
+	params.Platform = c.Platform
+	params.Roles = types.RolePreferences(c.Role, c.Roles)
+	params.Role = params.Roles[0]
+	if c.Role != "" {
+		params.Role = strings.ToLower(c.Role)
+	}
+	params.RankVal = int32(types.RankIDToRankVal[c.RankID])
+	params.Name = c.Name
+	params.Characters = c.Characters
+	params.VoiceChat = c.VoiceChat
+	params.Mic = c.Mic
+	params.SessionID = c.SessionID
+	return params, nil
+}
+
+type JoinGroupAsParty struct {
+	GroupID  string `json:"groupId"`
+	PartyID  string `json:"partyId"`
+	PlayerID int    `json:"playerId"`
+	Passcode string `json:"passcode"`
+}
+
+func (c *JoinGroupAsParty) validate() error {
+	if c.GroupID == "" {
+		return fmt.Errorf("groupId is required")
+	}
+	if c.PartyID == "" {
+		return fmt.Errorf("partyId is required")
+	}
+	return nil
+}
+
+func (c *JoinGroupAsParty) Parse() (*services.JoinGroupAsPartyParams, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	params := &services.JoinGroupAsPartyParams{}
+	params.GroupID = c.GroupID
+	params.PartyID = c.PartyID
+	params.LeaderID = int32(c.PlayerID)
+	params.Passcode = c.Passcode
 	return params, nil
 }
@@ -2,8 +2,10 @@ package v1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jcserv/rivalslfg/internal/services"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -170,6 +172,101 @@ func TestCreateGroup_Validate(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "platform invalid is not supported")
 	})
+
+	t.Run("Should validate that the role queue fits into a team", func(t *testing.T) {
+		input := CreateGroup{
+			Owner:    "imphungky",
+			Region:   "na",
+			Gamemode: "competitive",
+			Role:     "vanguard",
+			Platform: "pc",
+			RankID:   "d3",
+			Characters: []string{
+				"Doctor Strange",
+			},
+			Vanguards:   3,
+			Duelists:    3,
+			Strategists: 3,
+		}
+		err := input.validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "role queue must have at most 6 slots for competitive")
+	})
+
+	t.Run("Should only let private groups take join requests", func(t *testing.T) {
+		input := CreateGroup{
+			Owner:    "imphungky",
+			Region:   "na",
+			Gamemode: "competitive",
+			Role:     "vanguard",
+			Platform: "pc",
+			RankID:   "d3",
+			Characters: []string{
+				"Doctor Strange",
+			},
+			Open:          true,
+			RequestToJoin: true,
+		}
+		err := input.validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "open groups can't take join requests")
+	})
+
+	t.Run("Should validate the description, language and tags", func(t *testing.T) {
+		input := CreateGroup{
+			Owner:       "imphungky",
+			Region:      "na",
+			Gamemode:    "competitive",
+			Role:        "vanguard",
+			Platform:    "pc",
+			RankID:      "d3",
+			Description: "Chill ranked games",
+			Language:    "en",
+			Tags:        []string{"chill", "learning"},
+		}
+		assert.NoError(t, input.validate())
+
+		input.Description = "visit www.example.com"
+		assert.ErrorContains(t, input.validate(), "description can't contain links")
+		input.Description = ""
+
+		input.Language = "klingon"
+		assert.ErrorContains(t, input.validate(), "language klingon is not supported")
+		input.Language = ""
+
+		input.Tags = []string{"chill", "toxic"}
+		assert.ErrorContains(t, input.validate(), "tag toxic is not supported")
+	})
+
+	t.Run("Should validate the schedule", func(t *testing.T) {
+		input := CreateGroup{
+			Owner:    "imphungky",
+			Region:   "na",
+			Gamemode: "competitive",
+			Role:     "vanguard",
+			Platform: "pc",
+			RankID:   "d3",
+		}
+		past, later, tooFar := time.Now().Add(-time.Hour), time.Now().Add(time.Hour), time.Now().Add(services.MaxScheduleAhead+time.Hour)
+
+		input.DurationMinutes = 60
+		assert.ErrorContains(t, input.validate(), "durationMinutes can only be set with startsAt")
+
+		input.StartsAt = &past
+		assert.ErrorContains(t, input.validate(), "startsAt must be in the future")
+
+		input.StartsAt = &tooFar
+		assert.ErrorContains(t, input.validate(), "startsAt must be within")
+
+		input.StartsAt = &later
+		assert.NoError(t, input.validate())
+
+		input.DurationMinutes = 0
+		assert.ErrorContains(t, input.validate(), "durationMinutes must be between 1 and 720")
+
+		input.DurationMinutes = 721
+		assert.ErrorContains(t, input.validate(), "durationMinutes must be between 1 and 720")
+	})
 }
 
 func TestCreateGroup_Parse(t *testing.T) {
@@ -205,6 +302,47 @@ func TestCreateGroup_Parse(t *testing.T) {
 		assert.True(t, result.Mic)
 		assert.Equal(t, pgtype.Bool{Bool: false, Valid: true}, result.GroupMic)
 		assert.Equal(t, pgtype.Bool{Bool: false, Valid: true}, result.GroupVoiceChat)
+		assert.False(t, result.StartsAt.Valid)
+		assert.False(t, result.DurationMinutes.Valid)
+	})
+
+	t.Run("Should parse the description, language and tags", func(t *testing.T) {
+		input := CreateGroup{
+			Owner:       "imphungky",
+			Region:      "na",
+			Gamemode:    "competitive",
+			Role:        "vanguard",
+			Platform:    "pc",
+			RankID:      "d3",
+			Description: " Chill ranked games ",
+			Language:    "PT",
+			Tags:        []string{"Chill", "18+"},
+		}
+
+		result, err := input.Parse()
+		assert.NoError(t, err)
+		assert.Equal(t, "Chill ranked games", result.Description)
+		assert.Equal(t, "pt", result.Language)
+		assert.Equal(t, []string{"chill", "18+"}, result.Tags)
+	})
+
+	t.Run("Should parse the schedule", func(t *testing.T) {
+		startsAt := time.Now().Add(time.Hour)
+		input := CreateGroup{
+			Owner:           "imphungky",
+			Region:          "na",
+			Gamemode:        "competitive",
+			Role:            "vanguard",
+			Platform:        "pc",
+			RankID:          "d3",
+			StartsAt:        &startsAt,
+			DurationMinutes: 90,
+		}
+
+		result, err := input.Parse()
+		assert.NoError(t, err)
+		assert.Equal(t, pgtype.Timestamptz{Time: startsAt, Valid: true}, result.StartsAt)
+		assert.Equal(t, pgtype.Int4{Int32: 90, Valid: true}, result.DurationMinutes)
 	})
 }
 
@@ -332,6 +470,35 @@ func TestJoinGroup_Validate(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "rankId invalid is invalid")
 	})
+
+	t.Run("Should allow preferred roles instead of a role", func(t *testing.T) {
+		input := JoinGroup{
+			GroupID:  "AAAA",
+			Name:     "imphungky",
+			Region:   "na",
+			Gamemode: "competitive",
+			Roles:    []string{"duelist", "strategist"},
+			Platform: "pc",
+			RankID:   "d3",
+		}
+		err := input.validate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should validate preferred roles", func(t *testing.T) {
+		input := JoinGroup{
+			GroupID:  "AAAA",
+			Name:     "imphungky",
+			Region:   "na",
+			Gamemode: "competitive",
+			Roles:    []string{"duelist", "Duelist"},
+			Platform: "pc",
+			RankID:   "d3",
+		}
+		err := input.validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "role Duelist is listed more than once")
+	})
 }
 
 func TestJoinGroup_Parse(t *testing.T) {
@@ -360,12 +527,30 @@ func TestJoinGroup_Parse(t *testing.T) {
 		assert.Equal(t, "na", result.Region)
 		assert.Equal(t, "competitive", result.Gamemode)
 		assert.Equal(t, "vanguard", result.Role)
+		assert.Equal(t, []string{"vanguard"}, result.Roles)
 		assert.Equal(t, "pc", result.Platform)
 		assert.Equal(t, int32(40), result.RankVal) // d3 = 40
 		assert.Equal(t, []string{"Doctor Strange"}, result.Characters)
 		assert.True(t, result.VoiceChat)
 		assert.True(t, result.Mic)
 	})
+
+	t.Run("Should use the first preferred role as the player's role", func(t *testing.T) {
+		input := JoinGroup{
+			GroupID:  "AAAA",
+			Name:     "imphungky",
+			Region:   "na",
+			Gamemode: "competitive",
+			Roles:    []string{"Strategist", "duelist"},
+			Platform: "pc",
+			RankID:   "d3",
+		}
+
+		result, err := input.Parse()
+		assert.NoError(t, err)
+		assert.Equal(t, "strategist", result.Role)
+		assert.Equal(t, []string{"strategist", "duelist"}, result.Roles)
+	})
 }
 
 func TestRemovePlayer_Validate(t *testing.T) {
@@ -424,3 +609,141 @@ func TestRemovePlayer_Parse(t *testing.T) {
 		assert.Equal(t, int32(2147483647), result.PlayerID)
 	})
 }
+
+func TestProposeMerge_Parse(t *testing.T) {
+	t.Run("Should propose merging the group into the target", func(t *testing.T) {
+		input := ProposeMerge{
+			GroupID:  "AAAA",
+			PlayerID: 1,
+			TargetID: "BBBB",
+		}
+
+		result, err := input.Parse()
+		assert.NoError(t, err)
+		assert.Equal(t, services.ProposeMergeParams{SourceID: "AAAA", TargetID: "BBBB", OwnerID: 1}, *result)
+	})
+
+	t.Run("Should require a target", func(t *testing.T) {
+		input := ProposeMerge{GroupID: "AAAA", PlayerID: 1}
+		_, err := input.Parse()
+		assert.ErrorContains(t, err, "targetId is required")
+	})
+
+	t.Run("Should refuse merging the group with itself", func(t *testing.T) {
+		input := ProposeMerge{GroupID: "AAAA", PlayerID: 1, TargetID: "AAAA"}
+		_, err := input.Parse()
+		assert.ErrorContains(t, err, "cannot be merged with themselves")
+	})
+}
+
+func TestUpdateMember_Validate(t *testing.T) {
+	role := "strategist"
+	characters := []string{"Luna Snow", "Mantis"}
+
+	t.Run("Valid input", func(t *testing.T) {
+		input := UpdateMember{
+			GroupID:    "AAAA",
+			PlayerID:   1,
+			Role:       &role,
+			Characters: &characters,
+		}
+		err := input.validate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should require a role or characters", func(t *testing.T) {
+		input := UpdateMember{
+			GroupID:  "AAAA",
+			PlayerID: 1,
+		}
+		err := input.validate()
+		assert.ErrorContains(t, err, "role or characters is required")
+	})
+
+	t.Run("Should validate the role", func(t *testing.T) {
+		healer := "healer"
+		input := UpdateMember{
+			GroupID:  "AAAA",
+			PlayerID: 1,
+			Role:     &healer,
+		}
+		err := input.validate()
+		assert.Error(t, err)
+	})
+
+	t.Run("Should validate the characters", func(t *testing.T) {
+		duplicated := []string{"Mantis", "Mantis"}
+		input := UpdateMember{
+			GroupID:    "AAAA",
+			PlayerID:   1,
+			Characters: &duplicated,
+		}
+		err := input.validate()
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateMember_Parse(t *testing.T) {
+	t.Run("Should lowercase the role", func(t *testing.T) {
+		role := "Strategist"
+		input := UpdateMember{
+			GroupID:  "AAAA",
+			PlayerID: 1,
+			Role:     &role,
+		}
+
+		result, err := input.Parse()
+		assert.NoError(t, err)
+		assert.Equal(t, "strategist", *result.Role)
+		assert.Nil(t, result.Characters)
+	})
+}
+
+func TestQueue_ParseRelaxation(t *testing.T) {
+	t.Run("Should use the default schedule if none is given", func(t *testing.T) {
+		input := Queue{}
+		schedule, err := input.ParseRelaxation()
+		assert.NoError(t, err)
+		assert.Nil(t, schedule)
+	})
+
+	t.Run("Should parse each step", func(t *testing.T) {
+		input := Queue{
+			VoiceChat: true,
+			Relaxation: []RelaxationStep{
+				{After: "0s", MaxRankSpread: 5, RequireVoiceChat: true},
+				{After: "6m", MaxRankSpread: 15},
+			},
+		}
+		schedule, err := input.ParseRelaxation()
+		assert.NoError(t, err)
+		assert.Equal(t, services.RelaxationSchedule{
+			{After: 0, Constraints: services.Constraints{MaxRankSpread: 5, RequireVoiceChat: true}},
+			{After: 6 * time.Minute, Constraints: services.Constraints{MaxRankSpread: 15}},
+		}, schedule)
+	})
+
+	t.Run("Should not let players relax their rank spread faster than the server would", func(t *testing.T) {
+		input := Queue{Gamemode: "competitive", Relaxation: []RelaxationStep{{After: "0s", MaxRankSpread: 80}}}
+		_, err := input.ParseRelaxation()
+		assert.Error(t, err)
+	})
+
+	t.Run("Should validate after", func(t *testing.T) {
+		input := Queue{Relaxation: []RelaxationStep{{After: "soon", MaxRankSpread: 5}}}
+		_, err := input.ParseRelaxation()
+		assert.Error(t, err)
+	})
+
+	t.Run("Should require voice chat to require it from groups", func(t *testing.T) {
+		input := Queue{Relaxation: []RelaxationStep{{After: "0s", MaxRankSpread: 5, RequireVoiceChat: true}}}
+		_, err := input.ParseRelaxation()
+		assert.Error(t, err)
+	})
+
+	t.Run("Should validate the schedule", func(t *testing.T) {
+		input := Queue{Relaxation: []RelaxationStep{{After: "0s", MaxRankSpread: 15}, {After: "1m", MaxRankSpread: 5}}}
+		_, err := input.ParseRelaxation()
+		assert.Error(t, err)
+	})
+}
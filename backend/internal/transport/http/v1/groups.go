@@ -2,15 +2,17 @@ package v1
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
 	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
 	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/jcserv/rivalslfg/internal/utils"
 	"github.com/jcserv/rivalslfg/internal/utils/log"
 )
 
@@ -36,6 +38,10 @@ func (a *API) CreateGroup() http.HandlerFunc {
 
 		result, err := a.groupService.CreateGroup(ctx, *params)
 		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusBadRequest {
+				httputil.BadRequest(w, serviceErr)
+				return
+			}
 			httputil.InternalServerError(ctx, w, err)
 			return
 		}
@@ -89,9 +95,24 @@ func (a *API) GetGroups() http.HandlerFunc {
 
 			args.Platform = playerReqParams.Platform
 			args.Role = playerReqParams.Role
+			args.Roles = playerReqParams.Roles
 			args.RankVal = playerReqParams.RankVal
 			args.VoiceChat = playerReqParams.VoiceChat
 			args.Mic = playerReqParams.Mic
+			args.Characters = playerReqParams.Characters
+		}
+
+		if args.CompositionSort != "" && !hasPlayerReqs {
+			httputil.BadRequest(w, fmt.Errorf("sorting by composition requires player requirements"))
+			return
+		}
+
+		// Groups that the player is banned from are left out
+		if playerID := int32(reqCtx.GetPlayerID(ctx)); playerID != 0 {
+			args.PlayerID = &playerID
+		}
+		if sessionID := reqCtx.GetSessionID(ctx); sessionID != "" {
+			args.SessionID = &sessionID
 		}
 
 		groups, totalCount, err := a.groupService.GetGroups(ctx, *args)
@@ -140,19 +161,410 @@ func (a *API) GetGroupByID() http.HandlerFunc {
 				PlayerID: int(group.OwnerID),
 				GroupID:  group.ID,
 			}, auth.GroupOwnerRights)
+		} else if reqCtx.IsGroupOwner(ctx, group.ID) {
+			// Or demoted, if they handed ownership to someone else
+			httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+				PlayerID: reqCtx.GetPlayerID(ctx),
+				GroupID:  group.ID,
+			}, auth.GroupMemberRights)
+		}
+		httputil.OK(w, group)
+	}
+}
+
+func (a *API) GetEligibility() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+
+		input := EligibilityFromQuery(vars["id"], r.URL.Query())
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		eligibility, err := a.groupService.GetEligibility(ctx, input.GroupID, *params)
+		if err != nil {
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		if eligibility == nil {
+			httputil.NotFound(w)
+			return
+		}
+		httputil.OK(w, eligibility)
+	}
+}
+
+// UpdateGroup changes the group's settings. Only the group's owner can update it.
+func (a *API) UpdateGroup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var input UpdateGroup
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.GroupID = vars["id"]
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		if !reqCtx.IsGroupOwner(ctx, input.GroupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		group, err := a.groupService.UpdateGroup(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
 		}
 		httputil.OK(w, group)
 	}
 }
 
-// DeleteGroup: TODO
+// GetPasscode returns the passcode for joining the group. Only the group's owner can see it.
+func (a *API) GetPasscode() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		passcode, err := a.groupService.GetPasscode(ctx, groupID, int32(reqCtx.GetPlayerID(ctx)))
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.OK(w, map[string]string{
+			"passcode": passcode,
+		})
+	}
+}
+
+// RotatePasscode replaces the group's passcode with a new one, and returns it.
+// Pending invites are revoked too if asked. Only the group's owner can rotate it.
+func (a *API) RotatePasscode() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		// The body is optional
+		var input RotatePasscode
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		passcode, err := a.groupService.RotatePasscode(ctx, groupID, int32(reqCtx.GetPlayerID(ctx)), input.RevokeInvites)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.OK(w, map[string]string{
+			"passcode": passcode,
+		})
+	}
+}
+
+// CreateInvite mints an invite that lets players join the group without the passcode. Only the group's owner can create them.
+func (a *API) CreateInvite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var input CreateInvite
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.GroupID = vars["id"]
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		if !reqCtx.IsGroupOwner(ctx, input.GroupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		invite, err := a.groupService.CreateInvite(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, invite)
+	}
+}
+
+// GetInvites lists the group's invites, along with who joined with each of them. Only the group's owner can see them.
+func (a *API) GetInvites() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		invites, err := a.groupService.GetInvites(ctx, groupID, int32(reqCtx.GetPlayerID(ctx)))
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, invites)
+	}
+}
+
+// RevokeInvite stops an invite from being redeemed. Only the group's owner can revoke it.
+func (a *API) RevokeInvite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		inviteID := utils.StringToInt(vars["inviteId"])
+		if inviteID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("inviteId is invalid"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		if err := a.groupService.RevokeInvite(ctx, groupID, int32(inviteID), int32(reqCtx.GetPlayerID(ctx))); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
+
+// GetBans lists the players that are banned from the group. Only the group's owner can see them.
+func (a *API) GetBans() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		bans, err := a.groupService.GetBans(ctx, groupID, int32(reqCtx.GetPlayerID(ctx)))
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, bans)
+	}
+}
+
+// LiftBan lets a banned player join the group again. Only the group's owner can lift bans.
+func (a *API) LiftBan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		playerID := utils.StringToInt(vars["playerId"])
+		if playerID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("playerId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		if err := a.groupService.LiftBan(ctx, groupID, int32(reqCtx.GetPlayerID(ctx)), int32(playerID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
+
+// DeleteGroup removes the group along with its memberships. Only the group's owner can delete it.
 func (a *API) DeleteGroup() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		err := errors.New("test error")
-		httputil.InternalServerError(ctx, w, err)
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		if err := a.groupService.DeleteGroup(ctx, groupID, int32(reqCtx.GetPlayerID(ctx))); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		// The owner keeps their player, but no longer has a group
+		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+			PlayerID: reqCtx.GetPlayerID(ctx),
+			GroupID:  "",
+		}, []auth.Right{})
 
-		// TODO: Generate token with groupId = "", and remove group owner rights
-		return
+		httputil.NoContent(w)
 	}
 }
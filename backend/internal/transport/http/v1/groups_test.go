@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
 	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
 	"github.com/jcserv/rivalslfg/internal/test"
 	"github.com/jcserv/rivalslfg/internal/test/mocks"
 	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
@@ -58,6 +60,31 @@ func TestIntegration_CreateGroup(t *testing.T) {
 		r.ServeHTTP(rec, req)
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
+	t.Run("Should return 400 if the group breaks its community's rules", func(t *testing.T) {
+		mockGroupService.EXPECT().CreateGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			return x.(repository.CreateGroupParams).CommunityID == 3
+		})).Return(repository.CreateGroupRow{}, services.NewError(http.StatusBadRequest, "Groups in this community must be private.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups", test.GetBody(
+			map[string]interface{}{
+				"owner":       "imphungky",
+				"gamemode":    "competitive",
+				"region":      "na",
+				"open":        true,
+				"communityId": 3,
+				"platform":    "co",
+				"role":        "vanguard",
+				"rankId":      "d3",
+				"vanguards":   2,
+				"duelists":    2,
+				"strategists": 2,
+			},
+		))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "must be private")
+	})
 }
 
 func TestIntegration_GetGroups(t *testing.T) {
@@ -196,6 +223,112 @@ func TestIntegration_GetGroups(t *testing.T) {
 		r.ServeHTTP(rec, req)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
+
+	t.Run("Should sort by composition with the player's characters", func(t *testing.T) {
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.GetGroupsParams)
+			return arg.CompositionSort == "desc" && assert.ObjectsAreEqual([]string{"Hulk"}, arg.Characters)
+		})).Return([]repository.GroupWithPlayers{}, int32(0), nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups?sort=-composition", test.GetBody(map[string]interface{}{
+			"role":       "vanguard",
+			"characters": []string{"Hulk"},
+		}))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Should filter by community", func(t *testing.T) {
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.GetGroupsParams)
+			return arg.CommunityFilter != nil && *arg.CommunityFilter == 3
+		})).Return([]repository.GroupWithPlayers{}, int32(0), nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups?filter=community%20eq%203", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Should return 400 if the community filter isn't a number", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups?filter=community%20eq%20abc", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Should filter scheduled groups by when they start", func(t *testing.T) {
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.GetGroupsParams)
+			return arg.StartsAfter != nil && arg.StartsAfter.Equal(time.Date(2025, time.January, 1, 18, 0, 0, 0, time.UTC)) &&
+				arg.StartsBefore != nil && arg.StartsBefore.Equal(time.Date(2025, time.January, 1, 22, 0, 0, 0, time.UTC))
+		})).Return([]repository.GroupWithPlayers{}, int32(0), nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups?filter=startsAt%20ge%20%222025-01-01T18:00:00Z%22%20and%20startsAt%20le%20%222025-01-01T22:00:00Z%22", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Should return 400 if the start time filter isn't a range", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups?filter=startsAt%20eq%20%222025-01-01T18:00:00Z%22", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Should return 400 if other filters aren't an exact match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups?filter=community%20ge%203", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Should filter by tags and language", func(t *testing.T) {
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.GetGroupsParams)
+			return assert.ObjectsAreEqual([]string{"chill"}, arg.TagsFilter) &&
+				assert.ObjectsAreEqual([]string{"learning", "18+"}, arg.AnyTagsFilter) &&
+				assert.ObjectsAreEqual([]string{"en", "es"}, arg.LanguagesFilter)
+		})).Return([]repository.GroupWithPlayers{}, int32(0), nil)
+		req := httptest.NewRequest(http.MethodGet, `/api/v1/groups?filter=tag%20eq%20%22chill%22%20and%20tag%20in%20(%22learning%22,%2218%2B%22)%20and%20language%20in%20(%22EN%22,%22es%22)`, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Should return 400 if a tag filter isn't a tag", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups?filter=tag%20in%20(%22chill%22,%22toxic%22)", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Should leave out groups that the player is banned from", func(t *testing.T) {
+		mockGroupService.EXPECT().GetGroups(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.GetGroupsParams)
+			return arg.PlayerID != nil && *arg.PlayerID == 2 && arg.SessionID != nil
+		})).Return([]repository.GroupWithPlayers{}, int32(0), nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups", nil), 2, ""))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Should return 400 if sorting by composition without player requirements", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups?sort=-composition", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
 }
 
 func TestIntegration_GetGroupByID(t *testing.T) {
@@ -240,6 +373,19 @@ func TestIntegration_GetGroupByID(t *testing.T) {
 		r.ServeHTTP(rec, req)
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
+	t.Run("Should reissue a member's token if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().GetGroupByID(gomock.Any(), "AAAA", true).Return(&repository.GroupWithPlayers{
+			GroupDTO: repository.GroupDTO{ID: "AAAA", OwnerID: 2, Open: true},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.False(t, auth.HasRight(claims, auth.RightDeleteGroup))
+	})
 	t.Run("Should return 500 if unexpected error", func(t *testing.T) {
 		mockGroupService.EXPECT().GetGroupByID(gomock.Any(), "AAAA", false).Return(nil, fmt.Errorf("unexpected err"))
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA", test.GetBody(
@@ -327,3 +473,612 @@ func TestIntegration_GetGroupByID(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, rec.Code)
 	})
 }
+
+func TestIntegration_GetEligibility(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+	mockPlayerService := mocks.NewMockIPlayer(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mockPlayerService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	eligibilityRequest := func(query map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/eligibility", nil)
+		q := req.URL.Query()
+		for k, v := range query {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+		return req
+	}
+	playerQuery := func() map[string]string {
+		return map[string]string{
+			"gamemode":  "competitive",
+			"region":    "na",
+			"platform":  "pc",
+			"role":      "vanguard",
+			"rankId":    "d3",
+			"voiceChat": "true",
+			"mic":       "false",
+		}
+	}
+
+	t.Run("Should return 200 with the evaluated requirements", func(t *testing.T) {
+		mockGroupService.EXPECT().GetEligibility(gomock.Any(), "AAAA", gomock.Cond(func(x any) bool {
+			arg := x.(repository.JoinGroupParams)
+			return arg.RankVal == int32(40) && arg.VoiceChat && !arg.Mic
+		})).Return(&services.Eligibility{
+			GroupID:  "AAAA",
+			Eligible: false,
+			Requirements: []services.Requirement{
+				{Name: services.RequirementMic, Met: false, Expected: true, Actual: false},
+			},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, eligibilityRequest(playerQuery()))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{
+			"groupId": "AAAA",
+			"eligible": false,
+			"requirements": [{"name": "mic", "met": false, "expected": true, "actual": false}]
+		}`, rec.Body.String())
+	})
+	t.Run("Should return 400 on invalid player info", func(t *testing.T) {
+		query := playerQuery()
+		query["rankId"] = "invalid"
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, eligibilityRequest(query))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 404 if group does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().GetEligibility(gomock.Any(), "AAAA", gomock.Any()).Return(nil, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, eligibilityRequest(playerQuery()))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 500 on unexpected error", func(t *testing.T) {
+		mockGroupService.EXPECT().GetEligibility(gomock.Any(), "AAAA", gomock.Any()).Return(nil, fmt.Errorf("unexpected error"))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, eligibilityRequest(playerQuery()))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestIntegration_DeleteGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	request := func(groupID string, rights ...auth.Right) *http.Request {
+		return withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA", nil), 1, groupID, rights...)
+	}
+
+	t.Run("Should return 204 and clear the owner's group from their token", func(t *testing.T) {
+		mockGroupService.EXPECT().DeleteGroup(gomock.Any(), "AAAA", int32(1)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", claims["playerId"])
+		assert.Equal(t, "", claims["groupId"])
+		assert.False(t, auth.HasRight(claims, auth.RightDeleteGroup))
+	})
+	t.Run("Should return 403 if the player doesn't own the group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 403 if the player owns another group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the group does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().DeleteGroup(gomock.Any(), "AAAA", int32(1)).Return(services.NewError(http.StatusNotFound, "Group not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 401 if unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().DeleteGroup(gomock.Any(), "AAAA", int32(1)).Return(services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_UpdateGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	request := func(groupID string, body map[string]interface{}, rights ...auth.Right) *http.Request {
+		return withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/groups/AAAA", test.GetBody(body)), 1, groupID, rights...)
+	}
+
+	t.Run("Should return 200 with the updated group", func(t *testing.T) {
+		mockGroupService.EXPECT().UpdateGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(services.UpdateGroupParams)
+			return arg.GroupID == "AAAA" && arg.OwnerID == 1 && *arg.Open == false && *arg.Duelists == 3 && arg.Region == nil
+		})).Return(&repository.GroupWithPlayers{
+			GroupDTO: repository.GroupDTO{ID: "AAAA", Open: false},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{
+			"open":     false,
+			"duelists": 3,
+		}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"id":"AAAA"`)
+	})
+	t.Run("Should return 400 with the members that the settings exclude", func(t *testing.T) {
+		mockGroupService.EXPECT().UpdateGroup(gomock.Any(), gomock.Any()).Return(nil,
+			services.NewDetailedError(http.StatusBadRequest, "Group settings exclude current members.", map[string]any{
+				"members": []map[string]any{{"playerId": 2}},
+			}, nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{"duelists": 0}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"playerId":2`)
+	})
+	t.Run("Should update the description, language and tags", func(t *testing.T) {
+		mockGroupService.EXPECT().UpdateGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(services.UpdateGroupParams)
+			return *arg.Description == "Chill games tonight" && *arg.Language == "es" &&
+				len(*arg.Tags) == 2 && (*arg.Tags)[0] == "chill" && arg.Open == nil
+		})).Return(&repository.GroupWithPlayers{
+			GroupDTO: repository.GroupDTO{ID: "AAAA"},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{
+			"description": "  Chill games tonight ",
+			"language":    "ES",
+			"tags":        []string{"Chill", "18+"},
+		}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Should return 400 if a setting is invalid", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{"region": "xx"}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if the description isn't allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{"description": "no sh1t talkers"}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if the group would be open and take join requests", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{"open": true, "requestToJoin": true}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if no settings are given", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if the player doesn't own the group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{"open": false}, auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 403 if the player owns another group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("BBBB", map[string]interface{}{"open": false}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the group does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().UpdateGroup(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusNotFound, "Group not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{"open": false}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().UpdateGroup(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, request("AAAA", map[string]interface{}{"open": false}, auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_GetPasscode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the passcode", func(t *testing.T) {
+		mockGroupService.EXPECT().GetPasscode(gomock.Any(), "AAAA", int32(1)).Return("X1Y2", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/passcode", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"passcode":"X1Y2"`)
+	})
+	t.Run("Should return 403 if the player doesn't own the group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/passcode", nil), 1, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the group does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().GetPasscode(gomock.Any(), "AAAA", int32(1)).Return("", services.NewError(http.StatusNotFound, "Group not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/passcode", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().GetPasscode(gomock.Any(), "AAAA", int32(1)).Return("", services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/passcode", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_RotatePasscode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the new passcode", func(t *testing.T) {
+		mockGroupService.EXPECT().RotatePasscode(gomock.Any(), "AAAA", int32(1), false).Return("Z9Z9", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/passcode", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"passcode":"Z9Z9"`)
+	})
+	t.Run("Should revoke pending invites if asked", func(t *testing.T) {
+		mockGroupService.EXPECT().RotatePasscode(gomock.Any(), "AAAA", int32(1), true).Return("Z9Z9", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/passcode", test.GetBody(map[string]interface{}{
+			"revokeInvites": true,
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/passcode", nil), 1, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 401 if unauthenticated", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/passcode", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().RotatePasscode(gomock.Any(), "AAAA", int32(1), false).Return("", services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/passcode", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_CreateInvite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with a single use invite by default", func(t *testing.T) {
+		mockGroupService.EXPECT().CreateInvite(gomock.Any(), services.CreateInviteParams{
+			GroupID:   "AAAA",
+			CreatedBy: 1,
+			MaxUses:   1,
+			ExpiresIn: services.DefaultInviteTTL,
+		}).Return(&services.InviteDTO{ID: 1, Token: "abc", MaxUses: 1}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/invites", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"token":"abc"`)
+	})
+	t.Run("Should return 200 with the given uses and expiry", func(t *testing.T) {
+		mockGroupService.EXPECT().CreateInvite(gomock.Any(), services.CreateInviteParams{
+			GroupID:   "AAAA",
+			CreatedBy: 1,
+			MaxUses:   3,
+			ExpiresIn: 30 * time.Minute,
+		}).Return(&services.InviteDTO{ID: 2, Token: "def", MaxUses: 3}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/invites", test.GetBody(map[string]interface{}{
+			"maxUses":   3,
+			"expiresIn": "30m",
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Should return 400 if the uses are out of range", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/invites", test.GetBody(map[string]interface{}{
+			"maxUses": 0,
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if the expiry is too long", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/invites", test.GetBody(map[string]interface{}{
+			"expiresIn": "720h",
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/invites", nil), 1, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the group does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().CreateInvite(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusNotFound, "Group not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/invites", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().CreateInvite(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/invites", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_GetInvites(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the invites and who redeemed them", func(t *testing.T) {
+		mockGroupService.EXPECT().GetInvites(gomock.Any(), "AAAA", int32(1)).Return([]services.InviteDTO{
+			{
+				ID:      1,
+				Token:   "abc",
+				MaxUses: 1,
+				Uses:    1,
+				Redemptions: []services.InviteRedemption{
+					{PlayerID: 2, Name: "imphungky"},
+				},
+			},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/invites", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"playerId":2`)
+	})
+	t.Run("Should return 403 if the player owns another group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/invites", nil), 1, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().GetInvites(gomock.Any(), "AAAA", int32(1)).Return(nil, services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/invites", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_RevokeInvite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 204 once revoked", func(t *testing.T) {
+		mockGroupService.EXPECT().RevokeInvite(gomock.Any(), "AAAA", int32(1), int32(1)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/invites/1", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 400 if the invite ID is invalid", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/invites/abc", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 404 if the invite does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().RevokeInvite(gomock.Any(), "AAAA", int32(2), int32(1)).Return(services.NewError(http.StatusNotFound, "Invite not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/invites/2", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().RevokeInvite(gomock.Any(), "AAAA", int32(1), int32(1)).Return(services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/invites/1", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_GetBans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the banned players", func(t *testing.T) {
+		mockGroupService.EXPECT().GetBans(gomock.Any(), "AAAA", int32(1)).Return([]services.BanDTO{
+			{PlayerID: 2, Name: "imphungky", BannedBy: 1},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/bans", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"playerId":2`)
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/bans", nil), 1, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().GetBans(gomock.Any(), "AAAA", int32(1)).Return(nil, services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/bans", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_LiftBan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 204 once lifted", func(t *testing.T) {
+		mockGroupService.EXPECT().LiftBan(gomock.Any(), "AAAA", int32(1), int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/bans/2", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 404 if the player isn't banned", func(t *testing.T) {
+		mockGroupService.EXPECT().LiftBan(gomock.Any(), "AAAA", int32(1), int32(3)).Return(services.NewError(http.StatusNotFound, "Ban not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/bans/3", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 403 if the player owns another group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/bans/2", nil), 1, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().LiftBan(gomock.Any(), "AAAA", int32(1), int32(2)).Return(services.NewError(http.StatusForbidden, "Only the group owner can manage the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/bans/2", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/jcserv/rivalslfg/internal/utils"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// ProposeMerge asks the owner of another group to take in the group's
+// members. Only the group's owner can propose merges, and the groups have to
+// fit together for the proposal to be made.
+func (a *API) ProposeMerge() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var input ProposeMerge
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.GroupID = vars["id"]
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		if !reqCtx.IsGroupOwner(ctx, input.GroupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		merge, err := a.groupService.ProposeMerge(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.Accepted(w, merge)
+	}
+}
+
+// GetMergeProposals lists the pending merges that the group proposed or was sent. Only the group's owner can see them.
+func (a *API) GetMergeProposals() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		merges, err := a.groupService.GetMergeProposals(ctx, groupID)
+		if err != nil {
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, merges)
+	}
+}
+
+// AcceptMerge moves the members of the group that proposed the merge into
+// this one. Only the owner of the group being merged into can accept merges.
+func (a *API) AcceptMerge() http.HandlerFunc {
+	return a.resolveMerge(true)
+}
+
+// DeclineMerge turns the merge down. Either group's owner can decline it.
+func (a *API) DeclineMerge() http.HandlerFunc {
+	return a.resolveMerge(false)
+}
+
+func (a *API) resolveMerge(accept bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		mergeID := utils.StringToInt(vars["mergeId"])
+		if mergeID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("mergeId is invalid"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		ownerID := reqCtx.GetPlayerID(ctx)
+		merge, err := a.groupService.ResolveMerge(ctx, groupID, int32(mergeID), int32(ownerID), accept)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, merge)
+	}
+}
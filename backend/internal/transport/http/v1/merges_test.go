@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIntegration_ProposeMerge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 202 with the proposed merge", func(t *testing.T) {
+		mockGroupService.EXPECT().ProposeMerge(gomock.Any(), services.ProposeMergeParams{
+			SourceID: "AAAA",
+			TargetID: "BBBB",
+			OwnerID:  1,
+		}).Return(&services.MergeDTO{
+			ID:         1,
+			SourceID:   "AAAA",
+			TargetID:   "BBBB",
+			ProposedBy: 1,
+			Status:     services.MergePending,
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/merges", test.GetBody(map[string]interface{}{
+			"targetId": "BBBB",
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"pending"`)
+	})
+	t.Run("Should return 400 with the members who don't fit", func(t *testing.T) {
+		mockGroupService.EXPECT().ProposeMerge(gomock.Any(), gomock.Any()).Return(nil, services.NewDetailedError(
+			http.StatusBadRequest, "Groups can't be merged without excluding members.", map[string]any{
+				"members": []map[string]any{{"playerId": 2}},
+			}, nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/merges", test.GetBody(map[string]interface{}{
+			"targetId": "BBBB",
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"playerId":2`)
+	})
+	t.Run("Should return 400 if the group is merged with itself", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/merges", test.GetBody(map[string]interface{}{
+			"targetId": "AAAA",
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 404 if the target group does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().ProposeMerge(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusNotFound, "Group not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/merges", test.GetBody(map[string]interface{}{
+			"targetId": "ZZZZ",
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/merges", test.GetBody(map[string]interface{}{
+			"targetId": "BBBB",
+		})), 3, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_GetMergeProposals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the pending merges", func(t *testing.T) {
+		mockGroupService.EXPECT().GetMergeProposals(gomock.Any(), "BBBB").Return([]services.MergeDTO{
+			{ID: 1, SourceID: "AAAA", TargetID: "BBBB", ProposedBy: 1, Status: services.MergePending},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/BBBB/merges", nil), 2, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"sourceId":"AAAA"`)
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/BBBB/merges", nil), 3, "BBBB", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_ResolveMerge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 once accepted", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveMerge(gomock.Any(), "BBBB", int32(1), int32(2), true).Return(&services.MergeDTO{
+			ID:       1,
+			SourceID: "AAAA",
+			TargetID: "BBBB",
+			Status:   services.MergeAccepted,
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/BBBB/merges/1/accept", nil), 2, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"accepted"`)
+	})
+	t.Run("Should return 200 once declined", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveMerge(gomock.Any(), "AAAA", int32(1), int32(1), false).Return(&services.MergeDTO{
+			ID:       1,
+			SourceID: "AAAA",
+			TargetID: "BBBB",
+			Status:   services.MergeDeclined,
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/merges/1/decline", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"declined"`)
+	})
+	t.Run("Should return 400 if the groups no longer fit together", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveMerge(gomock.Any(), "BBBB", int32(1), int32(2), true).
+			Return(nil, services.NewError(http.StatusBadRequest, "Groups have more members between them than fit in the team.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/BBBB/merges/1/accept", nil), 2, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if the proposing owner accepts their own merge", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveMerge(gomock.Any(), "AAAA", int32(1), int32(1), true).
+			Return(nil, services.NewError(http.StatusForbidden, "Only the owner of the group being merged into can accept the merge.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/merges/1/accept", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the merge does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveMerge(gomock.Any(), "BBBB", int32(2), int32(2), true).
+			Return(nil, services.NewError(http.StatusNotFound, "Merge not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/BBBB/merges/2/accept", nil), 2, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/BBBB/merges/1/accept", nil), 3, "BBBB", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
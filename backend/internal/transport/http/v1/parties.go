@@ -0,0 +1,282 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/jcserv/rivalslfg/internal/utils"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+func (a *API) CreateParty() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if reqCtx.GetGroupID(ctx) != "" {
+			httputil.BadRequest(w, fmt.Errorf("player is already in a group"))
+			return
+		}
+
+		var input JoinParty
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		// The party keeps the member's session, so that bans keyed to it apply once the party joins a group
+		input.SessionID = reqCtx.GetSessionID(ctx)
+		if input.SessionID == "" {
+			if input.SessionID, err = auth.NewSessionID(); err != nil {
+				httputil.InternalServerError(ctx, w, err)
+				return
+			}
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		partyID, playerID, err := a.partyService.CreateParty(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusBadRequest {
+				httputil.BadRequest(w, serviceErr)
+				return
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+			PlayerID:  int(playerID),
+			GroupID:   "",
+			SessionID: input.SessionID,
+		}, []auth.Right{})
+
+		httputil.OK(w, map[string]any{
+			"partyId":  partyID,
+			"playerId": playerID,
+		})
+	}
+}
+
+func (a *API) GetParty() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		partyID := vars["id"]
+		if partyID == "" {
+			httputil.BadRequest(w, fmt.Errorf("partyId is required"))
+			return
+		}
+
+		party, err := a.partyService.GetParty(ctx, partyID)
+		if err != nil {
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		if party == nil {
+			httputil.NotFound(w)
+			return
+		}
+
+		member := party.Member(int32(reqCtx.GetPlayerID(ctx)))
+		if member == nil {
+			httputil.Forbidden(w)
+			return
+		}
+
+		// Members may have been brought into a group by the party leader
+		if member.GroupID != "" && reqCtx.GetGroupID(ctx) != member.GroupID {
+			httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+				PlayerID: int(member.ID),
+				GroupID:  member.GroupID,
+			}, auth.GroupMemberRights)
+		}
+		httputil.OK(w, party)
+	}
+}
+
+func (a *API) JoinParty() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if reqCtx.GetGroupID(ctx) != "" {
+			httputil.BadRequest(w, fmt.Errorf("player is already in a group"))
+			return
+		}
+
+		var input JoinParty
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.PartyID = vars["id"]
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		// The party keeps the member's session, so that bans keyed to it apply once the party joins a group
+		input.SessionID = reqCtx.GetSessionID(ctx)
+		if input.SessionID == "" {
+			if input.SessionID, err = auth.NewSessionID(); err != nil {
+				httputil.InternalServerError(ctx, w, err)
+				return
+			}
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		playerID, err := a.partyService.JoinParty(ctx, input.PartyID, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+			PlayerID:  int(playerID),
+			GroupID:   "",
+			SessionID: input.SessionID,
+		}, []auth.Right{})
+
+		httputil.OK(w, map[string]any{
+			"partyId":  input.PartyID,
+			"playerId": playerID,
+		})
+	}
+}
+
+func (a *API) LeaveParty() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		partyID := vars["id"]
+		playerToRemoveID := utils.StringToInt(vars["playerId"])
+		if partyID == "" || playerToRemoveID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("partyId and playerId are required"))
+			return
+		}
+
+		party, err := a.partyService.GetParty(ctx, partyID)
+		if err != nil {
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		if party == nil {
+			httputil.NotFound(w)
+			return
+		}
+
+		// Players can only remove themselves, unless they lead the party
+		requesterID := reqCtx.GetPlayerID(ctx)
+		if requesterID == 0 || (requesterID != playerToRemoveID && requesterID != int(party.LeaderID)) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		if err := a.partyService.LeaveParty(ctx, partyID, int32(playerToRemoveID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusNotFound {
+				httputil.NotFound(w)
+				return
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.NoContent(w)
+	}
+}
+
+func (a *API) JoinGroupAsParty() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var input JoinGroupAsParty
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.GroupID = vars["id"]
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		if input.PlayerID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		playerIDs, err := a.partyService.JoinGroup(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		// Leaders that brought their party into their own group keep their rights
+		if !reqCtx.IsGroupMember(ctx, input.GroupID) {
+			httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+				PlayerID: input.PlayerID,
+				GroupID:  input.GroupID,
+			}, auth.GroupMemberRights)
+		}
+
+		httputil.OK(w, map[string]any{
+			"groupId":   input.GroupID,
+			"playerIds": playerIDs,
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func partyMemberBody() map[string]interface{} {
+	return map[string]interface{}{
+		"name":     "imphungky",
+		"platform": "pc",
+		"roles":    []string{"duelist", "strategist"},
+		"rankId":   "d3",
+		"characters": []string{
+			"Psylocke",
+		},
+		"voiceChat": true,
+		"mic":       true,
+	}
+}
+
+func testParty() *services.PartyDTO {
+	return &services.PartyDTO{
+		ID:       "ABCD",
+		LeaderID: 1,
+		Members: []services.PartyMember{
+			{ID: 1, Name: "imphungky", Leader: true, Platform: "pc", Roles: []string{"vanguard"}, Rank: "d3"},
+			{ID: 2, Name: "dunkel", Platform: "pc", Roles: []string{"duelist"}, Rank: "d2", GroupID: "AAAA"},
+		},
+	}
+}
+
+func withPlayer(req *http.Request, playerID int, groupID string, rights ...auth.Right) *http.Request {
+	token, _ := auth.GenerateToken(fmt.Sprint(playerID), map[string]string{
+		"playerId": fmt.Sprint(playerID),
+		"groupId":  groupID,
+	}, rights...)
+	req.Header.Set("Authorization", token)
+	claims, _ := auth.ValidateToken(token)
+	return reqCtx.WithAuthInfo(req, &reqCtx.AuthInfo{
+		PlayerID:  playerID,
+		GroupID:   groupID,
+		SessionID: claims["sessionId"].(string),
+		Token:     token,
+	})
+}
+
+func TestIntegration_CreateParty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockPartyService := mocks.NewMockIParty(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			PartyService: mockPartyService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+	t.Run("Should return 200 with a token for the leader", func(t *testing.T) {
+		mockPartyService.EXPECT().CreateParty(gomock.Any(), gomock.Any()).Return("ABCD", int32(1), nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/parties", test.GetBody(partyMemberBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", claims["playerId"])
+	})
+	t.Run("Should record the player's session on the party", func(t *testing.T) {
+		req := withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/parties", test.GetBody(partyMemberBody())), 1, "")
+		sessionID := reqCtx.GetSessionID(req.Context())
+		mockPartyService.EXPECT().CreateParty(gomock.Any(), gomock.Cond(func(x any) bool {
+			return x.(repository.JoinGroupParams).SessionID == sessionID
+		})).Return("ABCD", int32(1), nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, sessionID, claims["sessionId"])
+	})
+	t.Run("Should return 400 if required field is missing/empty", func(t *testing.T) {
+		body := partyMemberBody()
+		body["rankId"] = ""
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/parties", test.GetBody(body))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if player is already in a party", func(t *testing.T) {
+		mockPartyService.EXPECT().CreateParty(gomock.Any(), gomock.Any()).Return("", int32(0), services.NewError(http.StatusBadRequest, "Player is already in a party.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/parties", test.GetBody(partyMemberBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if player is already in a group", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/parties", test.GetBody(partyMemberBody()))
+		req = withPlayer(req, 1, "AAAA", auth.GroupMemberRights...)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestIntegration_JoinParty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockPartyService := mocks.NewMockIParty(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			PartyService: mockPartyService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+	t.Run("Should return 200 with a token for the player", func(t *testing.T) {
+		mockPartyService.EXPECT().JoinParty(gomock.Any(), "ABCD", gomock.Any()).Return(int32(2), nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/parties/ABCD/players", test.GetBody(partyMemberBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "2", claims["playerId"])
+	})
+	t.Run("Should return 400 if the party is full", func(t *testing.T) {
+		mockPartyService.EXPECT().JoinParty(gomock.Any(), "ABCD", gomock.Any()).Return(int32(0), services.NewError(http.StatusBadRequest, "Party is full.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/parties/ABCD/players", test.GetBody(partyMemberBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 404 if the party does not exist", func(t *testing.T) {
+		mockPartyService.EXPECT().JoinParty(gomock.Any(), "ABCD", gomock.Any()).Return(int32(0), services.NewError(http.StatusNotFound, "Party not found.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/parties/ABCD/players", test.GetBody(partyMemberBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestIntegration_GetParty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockPartyService := mocks.NewMockIParty(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			PartyService: mockPartyService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+	t.Run("Should return the party to its members", func(t *testing.T) {
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(testParty(), nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/parties/ABCD", nil)
+		req = withPlayer(req, 1, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-Token"))
+	})
+	t.Run("Should return a token for the group a member was brought into", func(t *testing.T) {
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(testParty(), nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/parties/ABCD", nil)
+		req = withPlayer(req, 2, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", claims["groupId"])
+		assert.True(t, auth.HasRight(claims, auth.RightLeaveGroup))
+	})
+	t.Run("Should return 403 if the player is not a member", func(t *testing.T) {
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(testParty(), nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/parties/ABCD", nil)
+		req = withPlayer(req, 3, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the party does not exist", func(t *testing.T) {
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(nil, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/parties/ABCD", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestIntegration_LeaveParty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockPartyService := mocks.NewMockIParty(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			PartyService: mockPartyService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+	t.Run("Should allow members to leave", func(t *testing.T) {
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(testParty(), nil)
+		mockPartyService.EXPECT().LeaveParty(gomock.Any(), "ABCD", int32(2)).Return(nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/parties/ABCD/players/2", nil)
+		req = withPlayer(req, 2, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should allow the leader to remove members", func(t *testing.T) {
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(testParty(), nil)
+		mockPartyService.EXPECT().LeaveParty(gomock.Any(), "ABCD", int32(2)).Return(nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/parties/ABCD/players/2", nil)
+		req = withPlayer(req, 1, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 403 if a member attempts to remove another member", func(t *testing.T) {
+		mockPartyService.EXPECT().GetParty(gomock.Any(), "ABCD").Return(testParty(), nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/parties/ABCD/players/1", nil)
+		req = withPlayer(req, 2, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 401 if unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/parties/ABCD/players/2", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestIntegration_JoinGroupAsParty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockPartyService := mocks.NewMockIParty(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			PartyService: mockPartyService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	body := func() map[string]interface{} {
+		return map[string]interface{}{
+			"partyId":  "ABCD",
+			"passcode": "abcd",
+		}
+	}
+
+	t.Run("Should return 200 with a token for the group", func(t *testing.T) {
+		mockPartyService.EXPECT().JoinGroup(gomock.Any(), services.JoinGroupAsPartyParams{
+			GroupID:  "AAAA",
+			PartyID:  "ABCD",
+			LeaderID: 1,
+			Passcode: "abcd",
+		}).Return([]int32{1, 2}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/parties", test.GetBody(body()))
+		req = withPlayer(req, 1, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", claims["groupId"])
+	})
+	t.Run("Should keep the rights of a leader that is already in the group", func(t *testing.T) {
+		mockPartyService.EXPECT().JoinGroup(gomock.Any(), gomock.Any()).Return([]int32{2}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/parties", test.GetBody(body()))
+		req = withPlayer(req, 1, "AAAA", auth.GroupOwnerRights...)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-Token"))
+	})
+	t.Run("Should return the unmet requirements if a member does not meet them", func(t *testing.T) {
+		mockPartyService.EXPECT().JoinGroup(gomock.Any(), gomock.Any()).Return(nil, services.NewDetailedError(
+			http.StatusBadRequest,
+			"dunkel does not meet the group requirements.",
+			map[string]any{
+				"playerId": int32(2),
+				"requirements": []services.Requirement{
+					{Name: services.RequirementRank, Met: false, Expected: []string{"d3"}, Actual: "b3"},
+				},
+			},
+			nil,
+		))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/parties", test.GetBody(body()))
+		req = withPlayer(req, 1, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"playerId":2`)
+		assert.Contains(t, rec.Body.String(), `"name":"rank"`)
+	})
+	t.Run("Should return 403 if the player does not lead the party", func(t *testing.T) {
+		mockPartyService.EXPECT().JoinGroup(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Only the party leader can join a group.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/parties", test.GetBody(body()))
+		req = withPlayer(req, 2, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 401 if unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/parties", test.GetBody(body()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -29,6 +29,14 @@ func (a *API) JoinGroup() http.HandlerFunc {
 		vars := mux.Vars(r)
 		input.GroupID = vars["id"]
 		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		// Players joining for the first time are given their session up front, so that it's recorded on the membership
+		input.SessionID = reqCtx.GetSessionID(ctx)
+		if input.SessionID == "" {
+			if input.SessionID, err = auth.NewSessionID(); err != nil {
+				httputil.InternalServerError(ctx, w, err)
+				return
+			}
+		}
 
 		params, err := input.Parse()
 		if err != nil {
@@ -40,6 +48,10 @@ func (a *API) JoinGroup() http.HandlerFunc {
 		playerID, err := a.playerService.JoinGroup(ctx, *params)
 		if err != nil {
 			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
 				if serviceErr.Code() == http.StatusBadRequest {
 					httputil.BadRequest(w, serviceErr)
 					return
@@ -58,8 +70,9 @@ func (a *API) JoinGroup() http.HandlerFunc {
 		}
 
 		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
-			PlayerID: int(playerID),
-			GroupID:  input.GroupID,
+			PlayerID:  int(playerID),
+			GroupID:   input.GroupID,
+			SessionID: input.SessionID,
 		}, auth.GroupMemberRights)
 
 		httputil.OK(w, map[string]int32{
@@ -76,6 +89,8 @@ func (a *API) RemovePlayer() http.HandlerFunc {
 		input := RemovePlayer{
 			GroupID:          vars["id"],
 			PlayerToRemoveID: utils.StringToInt(vars["playerId"]),
+			Ban:              r.URL.Query().Get("ban") == "true",
+			BannedBy:         reqCtx.GetPlayerID(ctx),
 		}
 
 		params, err := input.Parse()
@@ -130,3 +145,107 @@ func (a *API) RemovePlayer() http.HandlerFunc {
 		httputil.OK(w, nil)
 	}
 }
+
+// UpdatePlayer moves a member to another role in the group, or changes their
+// character pool. Members can change their own, and the owner can change anyone's.
+func (a *API) UpdatePlayer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var input UpdateMember
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.GroupID = vars["id"]
+		input.PlayerID = utils.StringToInt(vars["playerId"])
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		requesterID := reqCtx.GetPlayerID(ctx)
+		if !reqCtx.IsGroupMember(ctx, input.GroupID) ||
+			(requesterID != input.PlayerID && !reqCtx.IsGroupOwner(ctx, input.GroupID)) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		group, err := a.groupService.UpdateMember(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, group)
+	}
+}
+
+// PromotePlayer hands ownership of the group to one of its members. The
+// requester is sent a member's token, and the new owner gets theirs over the websocket.
+func (a *API) PromotePlayer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		playerID := utils.StringToInt(vars["playerId"])
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		if playerID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("playerId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		ownerID := reqCtx.GetPlayerID(ctx)
+		if err := a.groupService.PromoteMember(ctx, groupID, int32(ownerID), int32(playerID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+			PlayerID: ownerID,
+			GroupID:  groupID,
+		}, auth.GroupMemberRights)
+
+		httputil.OK(w, map[string]int{
+			"ownerId": playerID,
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/repository"
 	"github.com/jcserv/rivalslfg/internal/services"
 	"github.com/jcserv/rivalslfg/internal/test"
 	"github.com/jcserv/rivalslfg/internal/test/mocks"
@@ -56,6 +57,73 @@ func TestIntegration_JoinGroup(t *testing.T) {
 		r.ServeHTTP(rec, req)
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
+	t.Run("Should allow players to join with an invite instead of the passcode", func(t *testing.T) {
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.JoinGroupParams)
+			return arg.Invite == "abc" && arg.Passcode == ""
+		})).Return(int32(1), nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players", test.GetBody(
+			map[string]interface{}{
+				"name":       "imphungky",
+				"invite":     "abc",
+				"gamemode":   "competitive",
+				"region":     "na",
+				"platform":   "pc",
+				"role":       "vanguard",
+				"rankId":     "d3",
+				"characters": []string{},
+			},
+		))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Should record the player's session on the membership", func(t *testing.T) {
+		var sessionID string
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Cond(func(x any) bool {
+			sessionID = x.(repository.JoinGroupParams).SessionID
+			return sessionID != ""
+		})).Return(int32(1), nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players", test.GetBody(
+			map[string]interface{}{
+				"name":       "imphungky",
+				"gamemode":   "competitive",
+				"region":     "na",
+				"platform":   "pc",
+				"role":       "vanguard",
+				"rankId":     "d3",
+				"characters": []string{},
+			},
+		))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		// The session is kept in the player's token, so that it follows them
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, sessionID, claims["sessionId"])
+	})
+	t.Run("Should return 403 if the player is banned from the group", func(t *testing.T) {
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Any()).Return(int32(0), services.NewError(http.StatusForbidden, "Player is banned from the group.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players", test.GetBody(
+			map[string]interface{}{
+				"name":       "imphungky",
+				"gamemode":   "competitive",
+				"region":     "na",
+				"platform":   "pc",
+				"role":       "vanguard",
+				"rankId":     "d3",
+				"characters": []string{},
+			},
+		))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
 	t.Run("Should return 400 if required field is missing/empty", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players", test.GetBody(
 			map[string]interface{}{
@@ -107,6 +175,43 @@ func TestIntegration_JoinGroup(t *testing.T) {
 		r.ServeHTTP(rec, req)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
+	t.Run("Should return the unmet requirements if requirements are not met", func(t *testing.T) {
+		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Any()).Return(int32(0), services.NewDetailedError(http.StatusBadRequest, "Group requirements not met.", map[string]any{
+			"requirements": []services.Requirement{
+				{Name: services.RequirementMic, Met: false, Expected: true, Actual: false},
+			},
+		}, nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players", test.GetBody(
+			map[string]interface{}{
+				"name":     "imphungky",
+				"passcode": "abcd",
+				"gamemode": "competitive",
+				"region":   "na",
+				"platform": "co",
+				"role":     "vanguard",
+				"rankId":   "d3",
+				"characters": []string{
+					"Doctor Strange",
+				},
+				"voiceChat":   true,
+				"mic":         false,
+				"vanguards":   2,
+				"duelists":    2,
+				"strategists": 2,
+			},
+		))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{
+			"code": 400,
+			"message": "Group requirements not met.",
+			"details": {
+				"requirements": [{"name": "mic", "met": false, "expected": true, "actual": false}]
+			}
+		}`, rec.Body.String())
+	})
 	t.Run("Should return 403 if serviceErr.Forbidden returned", func(t *testing.T) {
 		mockPlayerService.EXPECT().JoinGroup(gomock.Any(), gomock.Any()).Return(int32(0), services.NewError(http.StatusForbidden, "forbidden", nil))
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players", test.GetBody(
@@ -223,6 +328,26 @@ func TestIntegration_RemovePlayer(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
 
+	t.Run("Should ban the removed member if asked", func(t *testing.T) {
+		mockPlayerService.EXPECT().RemovePlayer(gomock.Any(), repository.RemovePlayerParams{
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Ban:      true,
+			BannedBy: 1,
+		}).Return("200", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/players/2?ban=true", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Should return 400 if players try to ban themselves", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/players/1?ban=true", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
 	t.Run("Should allow group member to remove themselves", func(t *testing.T) {
 		mockPlayerService.EXPECT().RemovePlayer(gomock.Any(), gomock.Any()).Return("200", nil)
 
@@ -334,3 +459,152 @@ func TestIntegration_RemovePlayer(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	})
 }
+
+func TestIntegration_UpdatePlayer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should let members change their own role", func(t *testing.T) {
+		role := "strategist"
+		mockGroupService.EXPECT().UpdateMember(gomock.Any(), services.UpdateMemberParams{
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Role:     &role,
+		}).Return(&repository.GroupWithPlayers{GroupDTO: repository.GroupDTO{ID: "AAAA"}}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/groups/AAAA/players/2", test.GetBody(map[string]interface{}{
+			"role": "Strategist",
+		})), 2, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Should let the owner change a member's characters", func(t *testing.T) {
+		characters := []string{"Luna Snow", "Mantis"}
+		mockGroupService.EXPECT().UpdateMember(gomock.Any(), services.UpdateMemberParams{
+			GroupID:    "AAAA",
+			PlayerID:   2,
+			Characters: &characters,
+		}).Return(&repository.GroupWithPlayers{GroupDTO: repository.GroupDTO{ID: "AAAA"}}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/groups/AAAA/players/2", test.GetBody(map[string]interface{}{
+			"characters": characters,
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Should return 403 if a member changes someone else", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/groups/AAAA/players/3", test.GetBody(map[string]interface{}{
+			"role": "duelist",
+		})), 2, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 403 if the player is in another group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/groups/AAAA/players/2", test.GetBody(map[string]interface{}{
+			"role": "duelist",
+		})), 2, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 400 if the role is invalid", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/groups/AAAA/players/2", test.GetBody(map[string]interface{}{
+			"role": "healer",
+		})), 2, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if the role has no open slots", func(t *testing.T) {
+		mockGroupService.EXPECT().UpdateMember(gomock.Any(), gomock.Any()).
+			Return(nil, services.NewError(http.StatusBadRequest, "Group has no open duelist slots.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/groups/AAAA/players/2", test.GetBody(map[string]interface{}{
+			"role": "duelist",
+		})), 2, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "no open duelist slots")
+	})
+	t.Run("Should return 404 if the player isn't in the group", func(t *testing.T) {
+		mockGroupService.EXPECT().UpdateMember(gomock.Any(), gomock.Any()).
+			Return(nil, services.NewError(http.StatusNotFound, "Player not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPatch, "/api/v1/groups/AAAA/players/4", test.GetBody(map[string]interface{}{
+			"role": "duelist",
+		})), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestIntegration_PromotePlayer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should hand ownership over and reissue the owner a member's token", func(t *testing.T) {
+		mockGroupService.EXPECT().PromoteMember(gomock.Any(), "AAAA", int32(1), int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players/2/promote", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"ownerId":2`)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", claims["groupId"])
+		assert.True(t, auth.HasRight(claims, auth.RightLeaveGroup))
+		assert.False(t, auth.HasRight(claims, auth.RightDeleteGroup))
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players/2/promote", nil), 1, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 403 if the player no longer owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().PromoteMember(gomock.Any(), "AAAA", int32(1), int32(3)).
+			Return(services.NewError(http.StatusForbidden, "Only the group owner can promote members.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players/3/promote", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the player isn't in the group", func(t *testing.T) {
+		mockGroupService.EXPECT().PromoteMember(gomock.Any(), "AAAA", int32(1), int32(4)).
+			Return(services.NewError(http.StatusNotFound, "Player not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players/4/promote", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 400 if the owner promotes themselves", func(t *testing.T) {
+		mockGroupService.EXPECT().PromoteMember(gomock.Any(), "AAAA", int32(1), int32(1)).
+			Return(services.NewError(http.StatusBadRequest, "Player already owns the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/players/1/promote", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+func (a *API) EnqueuePlayer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if reqCtx.GetGroupID(ctx) != "" {
+			httputil.BadRequest(w, fmt.Errorf("player is already in a group"))
+			return
+		}
+
+		var input Queue
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+		relaxation, err := input.ParseRelaxation()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		var entry *services.QueueEntry
+		if input.PartyID != "" {
+			entry, err = a.matcher.EnqueueParty(ctx, input.PartyID, *params, relaxation)
+		} else {
+			entry, err = a.matcher.Enqueue(ctx, *params, relaxation)
+		}
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		if entry.Match != nil {
+			rights := auth.GroupMemberRights
+			if entry.Match.Leader {
+				rights = auth.GroupOwnerRights
+			}
+			httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+				PlayerID: int(entry.Match.PlayerID),
+				GroupID:  entry.Match.GroupID,
+			}, rights)
+
+			httputil.OK(w, entry.Match)
+			return
+		}
+
+		// Queued players are identified by their token until they are matched
+		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+			PlayerID: int(entry.PlayerID),
+			GroupID:  "",
+		}, []auth.Right{})
+
+		httputil.Accepted(w, entry)
+	}
+}
+
+func (a *API) DequeuePlayer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		playerID := reqCtx.GetPlayerID(ctx)
+		if playerID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+
+		if err := a.matcher.Dequeue(ctx, int32(playerID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusNotFound {
+				httputil.NotFound(w)
+				return
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.NoContent(w)
+	}
+}
+
+func (a *API) GetQueueStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		playerID := reqCtx.GetPlayerID(ctx)
+		if playerID == 0 {
+			httputil.Unauthorized(w)
+			return
+		}
+
+		status, err := a.matcher.Status(ctx, int32(playerID))
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusNotFound {
+				httputil.NotFound(w)
+				return
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.OK(w, status)
+	}
+}
@@ -0,0 +1,274 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func queueBody() map[string]interface{} {
+	return map[string]interface{}{
+		"name":     "imphungky",
+		"gamemode": "competitive",
+		"region":   "na",
+		"platform": "pc",
+		"role":     "vanguard",
+		"rankId":   "d3",
+		"characters": []string{
+			"Doctor Strange",
+		},
+		"voiceChat": true,
+		"mic":       true,
+	}
+}
+
+func TestIntegration_EnqueuePlayer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockMatcher := mocks.NewMockIMatcher(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mocks.NewMockIGroup(ctrl),
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+			Matcher:       mockMatcher,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+	t.Run("Should return 200 with a token for the group if matched", func(t *testing.T) {
+		mockMatcher.EXPECT().Enqueue(gomock.Any(), gomock.Any(), gomock.Any()).Return(&services.QueueEntry{
+			PlayerID: 1,
+			Match: &services.MatchResult{
+				GroupID:  "AAAA",
+				PlayerID: 1,
+			},
+		}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/queue", test.GetBody(queueBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", claims["groupId"])
+		assert.True(t, auth.HasRight(claims, auth.RightLeaveGroup))
+		assert.False(t, auth.HasRight(claims, auth.RightDeleteGroup))
+	})
+	t.Run("Should return 200 with owner rights if leading a formed group", func(t *testing.T) {
+		mockMatcher.EXPECT().Enqueue(gomock.Any(), gomock.Any(), gomock.Any()).Return(&services.QueueEntry{
+			PlayerID: 1,
+			Match: &services.MatchResult{
+				GroupID:  "AAAA",
+				PlayerID: 1,
+				Leader:   true,
+			},
+		}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/queue", test.GetBody(queueBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.True(t, auth.HasRight(claims, auth.RightDeleteGroup))
+	})
+	t.Run("Should return 202 with a token for the player if queued", func(t *testing.T) {
+		mockMatcher.EXPECT().Enqueue(gomock.Any(), gomock.Any(), gomock.Any()).Return(&services.QueueEntry{
+			PlayerID: 1,
+			QueuedAt: time.Now(),
+		}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/queue", test.GetBody(queueBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", claims["playerId"])
+		assert.Equal(t, "", claims["groupId"])
+	})
+	t.Run("Should return 400 if required field is missing/empty", func(t *testing.T) {
+		body := queueBody()
+		body["region"] = ""
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/queue", test.GetBody(body))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if player is already in a group", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/queue", test.GetBody(queueBody()))
+		req = reqCtx.WithAuthInfo(req, &reqCtx.AuthInfo{
+			PlayerID: 1,
+			GroupID:  "AAAA",
+		})
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should queue the player's party if given", func(t *testing.T) {
+		mockMatcher.EXPECT().EnqueueParty(gomock.Any(), "ABCD", gomock.Any(), gomock.Any()).Return(&services.QueueEntry{
+			PlayerID:  1,
+			PartyID:   "ABCD",
+			PartySize: 2,
+			QueuedAt:  time.Now(),
+		}, nil)
+		body := queueBody()
+		body["partyId"] = "ABCD"
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/queue", test.GetBody(body))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+	t.Run("Should return 403 if the player does not lead the party", func(t *testing.T) {
+		mockMatcher.EXPECT().EnqueueParty(gomock.Any(), "ABCD", gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Only the party leader can queue the party.", nil))
+		body := queueBody()
+		body["partyId"] = "ABCD"
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/queue", test.GetBody(body))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 500 if unexpected error", func(t *testing.T) {
+		mockMatcher.EXPECT().Enqueue(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("unexpected error"))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/queue", test.GetBody(queueBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestIntegration_DequeuePlayer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockMatcher := mocks.NewMockIMatcher(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mocks.NewMockIGroup(ctrl),
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+			Matcher:       mockMatcher,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	queuedRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/queue", nil)
+		token, _ := auth.GenerateToken("1", map[string]string{
+			"playerId": "1",
+			"groupId":  "",
+		})
+		req.Header.Set("Authorization", token)
+		return reqCtx.WithAuthInfo(req, &reqCtx.AuthInfo{
+			PlayerID: 1,
+			Token:    token,
+		})
+	}
+
+	t.Run("Should return 204 if player was dequeued", func(t *testing.T) {
+		mockMatcher.EXPECT().Dequeue(gomock.Any(), int32(1)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, queuedRequest())
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 404 if player is not queued", func(t *testing.T) {
+		mockMatcher.EXPECT().Dequeue(gomock.Any(), int32(1)).Return(services.NewError(http.StatusNotFound, "Player is not queued.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, queuedRequest())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 401 if unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/queue", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestIntegration_GetQueueStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockMatcher := mocks.NewMockIMatcher(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mocks.NewMockIGroup(ctrl),
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+			Matcher:       mockMatcher,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	queuedRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/queue/me", nil)
+		token, _ := auth.GenerateToken("1", map[string]string{
+			"playerId": "1",
+			"groupId":  "",
+		})
+		req.Header.Set("Authorization", token)
+		return reqCtx.WithAuthInfo(req, &reqCtx.AuthInfo{
+			PlayerID: 1,
+			Token:    token,
+		})
+	}
+
+	t.Run("Should return 200 with the player's queue status", func(t *testing.T) {
+		estimate := 90
+		mockMatcher.EXPECT().Status(gomock.Any(), int32(1)).Return(&services.QueueStatus{
+			QueueEntry: &services.QueueEntry{
+				PlayerID: 1,
+				QueuedAt: time.Now(),
+			},
+			Position:             3,
+			WaitedSeconds:        42,
+			EstimatedWaitSeconds: &estimate,
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, queuedRequest())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		assert.Contains(t, rec.Body.String(), `"playerId":1`)
+		assert.Contains(t, rec.Body.String(), `"position":3`)
+		assert.Contains(t, rec.Body.String(), `"waitedSeconds":42`)
+		assert.Contains(t, rec.Body.String(), `"estimatedWaitSeconds":90`)
+	})
+	t.Run("Should return 404 if player is not queued", func(t *testing.T) {
+		mockMatcher.EXPECT().Status(gomock.Any(), int32(1)).Return(nil, services.NewError(http.StatusNotFound, "Player is not queued.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, queuedRequest())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 401 if unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/queue/me", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
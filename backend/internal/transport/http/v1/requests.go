@@ -0,0 +1,267 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/jcserv/rivalslfg/internal/utils"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// RequestToJoin asks the owner of a group that's taking requests to let the
+// player in. The player is given a token without a group, so that they can
+// connect to hear back, or check on the request later.
+func (a *API) RequestToJoin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if reqCtx.GetGroupID(ctx) != "" {
+			httputil.BadRequest(w, fmt.Errorf("player is already in a group"))
+			return
+		}
+
+		var input JoinGroup
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.GroupID = vars["id"]
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		// The session is recorded on the request, and carried onto the membership once it's approved
+		input.SessionID = reqCtx.GetSessionID(ctx)
+		if input.SessionID == "" {
+			if input.SessionID, err = auth.NewSessionID(); err != nil {
+				httputil.InternalServerError(ctx, w, err)
+				return
+			}
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		request, err := a.groupService.RequestToJoin(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+			PlayerID:  int(request.PlayerID),
+			GroupID:   "",
+			SessionID: input.SessionID,
+		}, []auth.Right{})
+
+		httputil.Accepted(w, request)
+	}
+}
+
+// GetJoinRequests lists the group's pending requests. Only the group's owner can see them.
+func (a *API) GetJoinRequests() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		requests, err := a.groupService.GetJoinRequests(ctx, groupID)
+		if err != nil {
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, requests)
+	}
+}
+
+// GetJoinRequest shows the request to the group's owner, or to the player who
+// made it. Players whose request was approved are given their token for the
+// group, in case they weren't connected when it happened, as long as they're
+// still in it.
+func (a *API) GetJoinRequest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		requestID := utils.StringToInt(vars["requestId"])
+		if requestID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("requestId is invalid"))
+			return
+		}
+
+		request, err := a.groupService.GetJoinRequest(ctx, groupID, int32(requestID))
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusNotFound {
+				httputil.NotFound(w)
+				return
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		playerID := reqCtx.GetPlayerID(ctx)
+		if int(request.PlayerID) != playerID {
+			if !reqCtx.IsGroupOwner(ctx, groupID) {
+				httputil.Forbidden(w)
+				return
+			}
+			httputil.OK(w, request)
+			return
+		}
+
+		if request.Status == services.JoinRequestApproved && reqCtx.GetGroupID(ctx) != groupID {
+			// Players who were removed since the request was approved can't use it to get back in
+			memberOf, err := a.playerService.GetPlayerGroup(ctx, int32(playerID))
+			if err != nil {
+				httputil.InternalServerError(ctx, w, err)
+				return
+			}
+			if memberOf != groupID {
+				httputil.Forbidden(w)
+				return
+			}
+			httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+				PlayerID:  playerID,
+				GroupID:   groupID,
+				SessionID: request.SessionID,
+			}, auth.GroupMemberRights)
+		}
+		httputil.OK(w, request)
+	}
+}
+
+// CancelJoinRequest withdraws a pending request. Only the player who made it can cancel it.
+func (a *API) CancelJoinRequest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		requestID := utils.StringToInt(vars["requestId"])
+		if requestID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("requestId is invalid"))
+			return
+		}
+
+		playerID := reqCtx.GetPlayerID(ctx)
+		if err := a.groupService.CancelJoinRequest(ctx, groupID, int32(requestID), int32(playerID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
+
+// ApproveJoinRequest seats the player who asked. Only the group's owner can approve requests.
+func (a *API) ApproveJoinRequest() http.HandlerFunc {
+	return a.resolveJoinRequest(true)
+}
+
+// DenyJoinRequest turns the player who asked away. Only the group's owner can deny requests.
+func (a *API) DenyJoinRequest() http.HandlerFunc {
+	return a.resolveJoinRequest(false)
+}
+
+func (a *API) resolveJoinRequest(approve bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		requestID := utils.StringToInt(vars["requestId"])
+		if requestID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("requestId is invalid"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		ownerID := reqCtx.GetPlayerID(ctx)
+		request, err := a.groupService.ResolveJoinRequest(ctx, groupID, int32(requestID), int32(ownerID), approve)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, request)
+	}
+}
@@ -0,0 +1,297 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func joinRequestBody() map[string]interface{} {
+	return map[string]interface{}{
+		"name":       "imphungky",
+		"gamemode":   "competitive",
+		"region":     "na",
+		"platform":   "pc",
+		"role":       "vanguard",
+		"rankId":     "d3",
+		"characters": []string{"Doctor Strange"},
+	}
+}
+
+func TestIntegration_RequestToJoin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 202 and a token without a group, recording the player's session", func(t *testing.T) {
+		mockGroupService.EXPECT().RequestToJoin(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.JoinGroupParams)
+			return arg.GroupID == "AAAA" && arg.SessionID != ""
+		})).Return(&services.JoinRequestDTO{
+			ID:       1,
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Status:   services.JoinRequestPending,
+		}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests", test.GetBody(joinRequestBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"pending"`)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "2", claims["playerId"])
+		assert.Equal(t, "", claims["groupId"])
+		assert.NotEmpty(t, claims["sessionId"])
+	})
+	t.Run("Should return 400 with the unmet requirements if the player isn't eligible", func(t *testing.T) {
+		mockGroupService.EXPECT().RequestToJoin(gomock.Any(), gomock.Any()).Return(nil, services.NewDetailedError(http.StatusBadRequest, "Group requirements not met.", map[string]any{
+			"requirements": []services.Requirement{{Name: services.RequirementRegion}},
+		}, nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests", test.GetBody(joinRequestBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), services.RequirementRegion)
+	})
+	t.Run("Should return 400 if the player already has a request pending", func(t *testing.T) {
+		mockGroupService.EXPECT().RequestToJoin(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusBadRequest, "Player already has a pending join request.", nil))
+		req := withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests", test.GetBody(joinRequestBody())), 2, "")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if the player is already in a group", func(t *testing.T) {
+		req := withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests", test.GetBody(joinRequestBody())), 2, "BBBB", auth.GroupMemberRights...)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if the player is banned from the group", func(t *testing.T) {
+		mockGroupService.EXPECT().RequestToJoin(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Player is banned from the group.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests", test.GetBody(joinRequestBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_GetJoinRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the pending requests", func(t *testing.T) {
+		mockGroupService.EXPECT().GetJoinRequests(gomock.Any(), "AAAA").Return([]services.JoinRequestDTO{
+			{ID: 1, GroupID: "AAAA", PlayerID: 2, Name: "imphungky", Status: services.JoinRequestPending},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/requests", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"playerId":2`)
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/requests", nil), 3, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_GetJoinRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+	mockPlayerService := mocks.NewMockIPlayer(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mockPlayerService,
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should give the player who asked a token for the group once approved", func(t *testing.T) {
+		mockGroupService.EXPECT().GetJoinRequest(gomock.Any(), "AAAA", int32(1)).Return(&services.JoinRequestDTO{
+			ID:       1,
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Status:   services.JoinRequestApproved,
+		}, nil)
+		mockPlayerService.EXPECT().GetPlayerGroup(gomock.Any(), int32(2)).Return("AAAA", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/requests/1", nil), 2, ""))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", claims["groupId"])
+	})
+	t.Run("Should return 403 if the player was removed since the request was approved", func(t *testing.T) {
+		mockGroupService.EXPECT().GetJoinRequest(gomock.Any(), "AAAA", int32(1)).Return(&services.JoinRequestDTO{
+			ID:       1,
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Status:   services.JoinRequestApproved,
+		}, nil)
+		mockPlayerService.EXPECT().GetPlayerGroup(gomock.Any(), int32(2)).Return("", nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/requests/1", nil), 2, ""))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-Token"))
+	})
+	t.Run("Should not give the player a token while the request is pending", func(t *testing.T) {
+		mockGroupService.EXPECT().GetJoinRequest(gomock.Any(), "AAAA", int32(1)).Return(&services.JoinRequestDTO{
+			ID:       1,
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Status:   services.JoinRequestPending,
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/requests/1", nil), 2, ""))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-Token"))
+	})
+	t.Run("Should return 403 if the player neither made the request nor owns the group", func(t *testing.T) {
+		mockGroupService.EXPECT().GetJoinRequest(gomock.Any(), "AAAA", int32(1)).Return(&services.JoinRequestDTO{
+			ID:       1,
+			GroupID:  "AAAA",
+			PlayerID: 2,
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/requests/1", nil), 3, ""))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_CancelJoinRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 204 once cancelled", func(t *testing.T) {
+		mockGroupService.EXPECT().CancelJoinRequest(gomock.Any(), "AAAA", int32(1), int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/requests/1", nil), 2, ""))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 400 if the request was already resolved", func(t *testing.T) {
+		mockGroupService.EXPECT().CancelJoinRequest(gomock.Any(), "AAAA", int32(1), int32(2)).Return(services.NewError(http.StatusBadRequest, "Join request is no longer pending.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/requests/1", nil), 2, ""))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestIntegration_ResolveJoinRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 once approved", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveJoinRequest(gomock.Any(), "AAAA", int32(1), int32(1), true).Return(&services.JoinRequestDTO{
+			ID:       1,
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Status:   services.JoinRequestApproved,
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests/1/approve", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"approved"`)
+	})
+	t.Run("Should return 200 once denied", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveJoinRequest(gomock.Any(), "AAAA", int32(1), int32(1), false).Return(&services.JoinRequestDTO{
+			ID:       1,
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Status:   services.JoinRequestDenied,
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests/1/deny", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"denied"`)
+	})
+	t.Run("Should return 400 if the group filled up since the player asked", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveJoinRequest(gomock.Any(), "AAAA", int32(1), int32(1), true).Return(nil, services.NewError(http.StatusBadRequest, "Group is full.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests/1/approve", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 404 if the request does not exist", func(t *testing.T) {
+		mockGroupService.EXPECT().ResolveJoinRequest(gomock.Any(), "AAAA", int32(2), int32(1), true).Return(nil, services.NewError(http.StatusNotFound, "Join request not found.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests/2/approve", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Should return 403 if the player is only a member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/requests/1/approve", nil), 3, "AAAA", auth.GroupMemberRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/jcserv/rivalslfg/internal/utils"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// Rsvp holds a slot in a scheduled group for the player until it starts. Players
+// can RSVP while they're in another group, which they have to leave by the time it starts.
+func (a *API) Rsvp() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var input JoinGroup
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.GroupID = vars["id"]
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		// The session is recorded on the RSVP, and carried onto the membership once the group starts
+		input.SessionID = reqCtx.GetSessionID(ctx)
+		if input.SessionID == "" {
+			if input.SessionID, err = auth.NewSessionID(); err != nil {
+				httputil.InternalServerError(ctx, w, err)
+				return
+			}
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		rsvp, err := a.groupService.Rsvp(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		// Players who are in a group keep their token for it
+		if reqCtx.GetGroupID(ctx) == "" {
+			httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+				PlayerID:  int(rsvp.PlayerID),
+				GroupID:   "",
+				SessionID: input.SessionID,
+			}, []auth.Right{})
+		}
+
+		httputil.Accepted(w, rsvp)
+	}
+}
+
+// GetRsvps lists the players who RSVP'd to the group, in the order they did.
+func (a *API) GetRsvps() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		rsvps, err := a.groupService.GetRsvps(ctx, groupID)
+		if err != nil {
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, rsvps)
+	}
+}
+
+// CancelRsvp gives up the slot a player was holding in the scheduled group.
+// Players can cancel their own RSVP, and the group's owner can cancel anyone's.
+func (a *API) CancelRsvp() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		playerID := utils.StringToInt(vars["playerId"])
+		if playerID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("playerId is invalid"))
+			return
+		}
+
+		if playerID != reqCtx.GetPlayerID(ctx) && !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		if err := a.groupService.CancelRsvp(ctx, groupID, int32(playerID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusNotFound {
+				httputil.NotFound(w)
+				return
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIntegration_Rsvp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 202 and a token without a group", func(t *testing.T) {
+		mockGroupService.EXPECT().Rsvp(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(repository.JoinGroupParams)
+			return arg.GroupID == "AAAA" && arg.SessionID != ""
+		})).Return(&services.RsvpDTO{
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Role:     "vanguard",
+		}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/rsvps", test.GetBody(joinRequestBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"role":"vanguard"`)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "2", claims["playerId"])
+		assert.Equal(t, "", claims["groupId"])
+		assert.NotEmpty(t, claims["sessionId"])
+	})
+	t.Run("Should keep the token of a player who's in a group", func(t *testing.T) {
+		mockGroupService.EXPECT().Rsvp(gomock.Any(), gomock.Any()).Return(&services.RsvpDTO{GroupID: "AAAA", PlayerID: 2}, nil)
+		req := withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/rsvps", test.GetBody(joinRequestBody())), 2, "BBBB", auth.GroupMemberRights...)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-Token"))
+	})
+	t.Run("Should return 400 if the group has already started", func(t *testing.T) {
+		mockGroupService.EXPECT().Rsvp(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusBadRequest, "Group has already started, so the player can join it directly.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/rsvps", test.GetBody(joinRequestBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if the passcode is wrong", func(t *testing.T) {
+		mockGroupService.EXPECT().Rsvp(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Access denied.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/rsvps", test.GetBody(joinRequestBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_GetRsvps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the RSVPs", func(t *testing.T) {
+		mockGroupService.EXPECT().GetRsvps(gomock.Any(), "AAAA").Return([]services.RsvpDTO{
+			{GroupID: "AAAA", PlayerID: 2, Name: "imphungky", Role: "duelist"},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/rsvps", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "imphungky")
+	})
+}
+
+func TestIntegration_CancelRsvp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 204 if the player cancels their own RSVP", func(t *testing.T) {
+		mockGroupService.EXPECT().CancelRsvp(gomock.Any(), "AAAA", int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/rsvps/2", nil), 2, ""))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 204 if the owner cancels the player's RSVP", func(t *testing.T) {
+		mockGroupService.EXPECT().CancelRsvp(gomock.Any(), "AAAA", int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/rsvps/2", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 403 if anyone else tries to cancel it", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/rsvps/2", nil), 3, ""))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the player hasn't RSVP'd", func(t *testing.T) {
+		mockGroupService.EXPECT().CancelRsvp(gomock.Any(), "AAAA", int32(2)).Return(services.NewError(http.StatusNotFound, "Player has not RSVP'd to the group.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/rsvps/2", nil), 2, ""))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -19,26 +19,76 @@ const (
 	group        = groups + byId
 	groupDetails = group + "/details"
 
+	groupEligibility = group + "/eligibility"
+	groupPasscode    = group + "/passcode"
+	groupInvites     = group + "/invites"
+	groupInvite      = groupInvites + "/{inviteId}"
+	groupBans        = group + "/bans"
+	groupBan         = groupBans + byPlayerID
+
+	groupRequests       = group + "/requests"
+	groupRequest        = groupRequests + "/{requestId}"
+	approveGroupRequest = groupRequest + "/approve"
+	denyGroupRequest    = groupRequest + "/deny"
+
+	groupWaitlist       = group + "/waitlist"
+	groupWaitlistEntry  = groupWaitlist + byPlayerID
+	acceptWaitlistOffer = groupWaitlistEntry + "/accept"
+
+	groupMerges  = group + "/merges"
+	groupMerge   = groupMerges + "/{mergeId}"
+	acceptMerge  = groupMerge + "/accept"
+	declineMerge = groupMerge + "/decline"
+
+	groupRsvps = group + "/rsvps"
+	groupRsvp  = groupRsvps + byPlayerID
+
 	players = APIV1URLPath + "players"
 
-	groupMembers = group + "/players"
-	groupMember  = groupMembers + byPlayerID
+	groupMembers       = group + "/players"
+	groupMember        = groupMembers + byPlayerID
+	promoteGroupMember = groupMember + "/promote"
+
+	groupParties = group + "/parties"
+
+	parties      = APIV1URLPath + "parties"
+	party        = parties + byId
+	partyMembers = party + "/players"
+	partyMember  = partyMembers + byPlayerID
+
+	queue   = APIV1URLPath + "queue"
+	queueMe = queue + "/me"
+
+	communities         = APIV1URLPath + "communities"
+	community           = communities + byId
+	communityModerators = community + "/moderators"
+	communityModerator  = communityModerators + byPlayerID
+	communityGroup      = community + "/groups/{groupId}"
 )
 
 type API struct {
-	groupService  services.IGroup
-	playerService services.IPlayer
+	groupService     services.IGroup
+	playerService    services.IPlayer
+	partyService     services.IParty
+	communityService services.ICommunity
+	matcher          services.IMatcher
 }
 
 type Dependencies struct {
-	GroupService  services.IGroup
-	PlayerService services.IPlayer
+	GroupService     services.IGroup
+	PlayerService    services.IPlayer
+	PartyService     services.IParty
+	CommunityService services.ICommunity
+	Matcher          services.IMatcher
 }
 
 func NewAPI(deps *Dependencies) *API {
 	return &API{
-		groupService:  deps.GroupService,
-		playerService: deps.PlayerService,
+		groupService:     deps.GroupService,
+		playerService:    deps.PlayerService,
+		partyService:     deps.PartyService,
+		communityService: deps.CommunityService,
+		matcher:          deps.Matcher,
 	}
 }
 
@@ -50,6 +100,121 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc(findGroup, a.GetGroups()).Methods(http.MethodPost)
 
 	r.HandleFunc(group, a.GetGroupByID()).Methods(http.MethodGet)
+	r.HandleFunc(group,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.UpdateGroup(),
+		),
+	).Methods(http.MethodPatch)
+	r.HandleFunc(group,
+		middleware.RequireRight(auth.RightDeleteGroup)(
+			a.DeleteGroup(),
+		),
+	).Methods(http.MethodDelete)
+	r.HandleFunc(groupEligibility, a.GetEligibility()).Methods(http.MethodGet)
+	r.HandleFunc(groupPasscode,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.GetPasscode(),
+		),
+	).Methods(http.MethodGet)
+	r.HandleFunc(groupPasscode,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.RotatePasscode(),
+		),
+	).Methods(http.MethodPost)
+	r.HandleFunc(groupInvites,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.CreateInvite(),
+		),
+	).Methods(http.MethodPost)
+	r.HandleFunc(groupInvites,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.GetInvites(),
+		),
+	).Methods(http.MethodGet)
+	r.HandleFunc(groupInvite,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.RevokeInvite(),
+		),
+	).Methods(http.MethodDelete)
+	r.HandleFunc(groupBans,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.GetBans(),
+		),
+	).Methods(http.MethodGet)
+	r.HandleFunc(groupBan,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.LiftBan(),
+		),
+	).Methods(http.MethodDelete)
+	r.HandleFunc(groupRequests, a.RequestToJoin()).Methods(http.MethodPost)
+	r.HandleFunc(groupRequests,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.GetJoinRequests(),
+		),
+	).Methods(http.MethodGet)
+	r.HandleFunc(groupRequest,
+		middleware.RequireRight(auth.RightJoinGroup)(
+			a.GetJoinRequest(),
+		),
+	).Methods(http.MethodGet)
+	r.HandleFunc(groupRequest,
+		middleware.RequireRight(auth.RightJoinGroup)(
+			a.CancelJoinRequest(),
+		),
+	).Methods(http.MethodDelete)
+	r.HandleFunc(approveGroupRequest,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.ApproveJoinRequest(),
+		),
+	).Methods(http.MethodPost)
+	r.HandleFunc(denyGroupRequest,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.DenyJoinRequest(),
+		),
+	).Methods(http.MethodPost)
+	r.HandleFunc(groupWaitlist, a.JoinWaitlist()).Methods(http.MethodPost)
+	r.HandleFunc(groupWaitlist,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.GetWaitlist(),
+		),
+	).Methods(http.MethodGet)
+	r.HandleFunc(groupWaitlistEntry,
+		middleware.RequireRight(auth.RightJoinGroup)(
+			a.LeaveWaitlist(),
+		),
+	).Methods(http.MethodDelete)
+	r.HandleFunc(acceptWaitlistOffer,
+		middleware.RequireRight(auth.RightJoinGroup)(
+			a.AcceptWaitlistOffer(),
+		),
+	).Methods(http.MethodPost)
+	r.HandleFunc(groupMerges,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.ProposeMerge(),
+		),
+	).Methods(http.MethodPost)
+	r.HandleFunc(groupMerges,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.GetMergeProposals(),
+		),
+	).Methods(http.MethodGet)
+	r.HandleFunc(acceptMerge,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.AcceptMerge(),
+		),
+	).Methods(http.MethodPost)
+	r.HandleFunc(declineMerge,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.DeclineMerge(),
+		),
+	).Methods(http.MethodPost)
+	r.HandleFunc(groupRsvps, a.Rsvp()).Methods(http.MethodPost)
+	r.HandleFunc(groupRsvps, a.GetRsvps()).Methods(http.MethodGet)
+	r.HandleFunc(groupRsvp,
+		middleware.RequireRight(auth.RightJoinGroup)(
+			a.CancelRsvp(),
+		),
+	).Methods(http.MethodDelete)
 	r.HandleFunc(groupMembers, a.JoinGroup()).Methods(http.MethodPost)
 
 	r.HandleFunc(groupMember,
@@ -57,4 +222,47 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 			a.RemovePlayer(),
 		),
 	).Methods(http.MethodDelete)
+	r.HandleFunc(groupMember,
+		middleware.RequireRight(auth.RightLeaveGroup)(
+			a.UpdatePlayer(),
+		),
+	).Methods(http.MethodPatch)
+
+	r.HandleFunc(promoteGroupMember,
+		middleware.RequireRight(auth.RightUpdateGroup)(
+			a.PromotePlayer(),
+		),
+	).Methods(http.MethodPost)
+
+	r.HandleFunc(groupParties, a.JoinGroupAsParty()).Methods(http.MethodPost)
+
+	r.HandleFunc(parties, a.CreateParty()).Methods(http.MethodPost)
+	r.HandleFunc(party, a.GetParty()).Methods(http.MethodGet)
+	r.HandleFunc(partyMembers, a.JoinParty()).Methods(http.MethodPost)
+	r.HandleFunc(partyMember,
+		middleware.RequireRight(auth.RightJoinGroup)(
+			a.LeaveParty(),
+		),
+	).Methods(http.MethodDelete)
+
+	r.HandleFunc(queue, a.EnqueuePlayer()).Methods(http.MethodPost)
+	r.HandleFunc(queue,
+		middleware.RequireRight(auth.RightJoinGroup)(
+			a.DequeuePlayer(),
+		),
+	).Methods(http.MethodDelete)
+	r.HandleFunc(queueMe,
+		middleware.RequireRight(auth.RightJoinGroup)(
+			a.GetQueueStatus(),
+		),
+	).Methods(http.MethodGet)
+
+	r.HandleFunc(communities, a.CreateCommunity()).Methods(http.MethodPost)
+	r.HandleFunc(communities, a.GetCommunities()).Methods(http.MethodGet)
+	r.HandleFunc(community, a.GetCommunity()).Methods(http.MethodGet)
+	r.HandleFunc(community, a.UpdateCommunity()).Methods(http.MethodPatch)
+	r.HandleFunc(community, a.DeleteCommunity()).Methods(http.MethodDelete)
+	r.HandleFunc(communityModerators, a.AddCommunityModerator()).Methods(http.MethodPost)
+	r.HandleFunc(communityModerator, a.RemoveCommunityModerator()).Methods(http.MethodDelete)
+	r.HandleFunc(communityGroup, a.DeleteCommunityGroup()).Methods(http.MethodDelete)
 }
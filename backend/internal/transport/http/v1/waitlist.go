@@ -0,0 +1,198 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/transport/http/httputil"
+	"github.com/jcserv/rivalslfg/internal/transport/http/reqCtx"
+	"github.com/jcserv/rivalslfg/internal/utils"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// JoinWaitlist puts the player in line for the next slot that opens up in a
+// full group. The player is given a token without a group, so that they can
+// connect to be offered the slot.
+func (a *API) JoinWaitlist() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if reqCtx.GetGroupID(ctx) != "" {
+			httputil.BadRequest(w, fmt.Errorf("player is already in a group"))
+			return
+		}
+
+		var input JoinWaitlist
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, fmt.Errorf("unable to decode request body"))
+			return
+		}
+
+		vars := mux.Vars(r)
+		input.GroupID = vars["id"]
+		input.PlayerID = reqCtx.GetPlayerID(ctx)
+		// The session is recorded on the waitlist, and carried onto the membership once the player accepts
+		input.SessionID = reqCtx.GetSessionID(ctx)
+		if input.SessionID == "" {
+			if input.SessionID, err = auth.NewSessionID(); err != nil {
+				httputil.InternalServerError(ctx, w, err)
+				return
+			}
+		}
+
+		params, err := input.Parse()
+		if err != nil {
+			log.Debug(ctx, err.Error())
+			httputil.BadRequest(w, err)
+			return
+		}
+
+		entry, err := a.groupService.JoinWaitlist(ctx, *params)
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusForbidden:
+					httputil.Forbidden(w)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+			PlayerID:  int(entry.PlayerID),
+			GroupID:   "",
+			SessionID: input.SessionID,
+		}, []auth.Right{})
+
+		httputil.Accepted(w, entry)
+	}
+}
+
+// GetWaitlist lists the players waiting on the group, in order. Only the group's owner can see them.
+func (a *API) GetWaitlist() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+
+		if !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		waitlist, err := a.groupService.GetWaitlist(ctx, groupID)
+		if err != nil {
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.OK(w, waitlist)
+	}
+}
+
+// LeaveWaitlist takes a player off the group's waitlist, turning down any slot
+// being held for them. Players can take themselves off, and the group's owner
+// can take anyone off.
+func (a *API) LeaveWaitlist() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		playerID := utils.StringToInt(vars["playerId"])
+		if playerID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("playerId is invalid"))
+			return
+		}
+
+		if playerID != reqCtx.GetPlayerID(ctx) && !reqCtx.IsGroupOwner(ctx, groupID) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		if err := a.groupService.LeaveWaitlist(ctx, groupID, int32(playerID)); err != nil {
+			if serviceErr, ok := err.(services.Error); ok && serviceErr.Code() == http.StatusNotFound {
+				httputil.NotFound(w)
+				return
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+		httputil.NoContent(w)
+	}
+}
+
+// AcceptWaitlistOffer seats the player in the slot being held for them. Only
+// the player themselves can accept, and they're given their token for the group.
+func (a *API) AcceptWaitlistOffer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		groupID := vars["id"]
+		if groupID == "" {
+			httputil.BadRequest(w, fmt.Errorf("groupId is required"))
+			return
+		}
+		playerID := utils.StringToInt(vars["playerId"])
+		if playerID <= 0 {
+			httputil.BadRequest(w, fmt.Errorf("playerId is invalid"))
+			return
+		}
+
+		if playerID != reqCtx.GetPlayerID(ctx) {
+			httputil.Forbidden(w)
+			return
+		}
+
+		entry, err := a.groupService.AcceptWaitlistOffer(ctx, groupID, int32(playerID))
+		if err != nil {
+			if serviceErr, ok := err.(services.Error); ok {
+				if detailedErr, ok := serviceErr.(services.DetailedError); ok && serviceErr.Code() == http.StatusBadRequest {
+					httputil.BadRequest(w, serviceErr, detailedErr.Details())
+					return
+				}
+				switch serviceErr.Code() {
+				case http.StatusBadRequest:
+					httputil.BadRequest(w, serviceErr)
+					return
+				case http.StatusNotFound:
+					httputil.NotFound(w)
+					return
+				}
+			}
+			httputil.InternalServerError(ctx, w, err)
+			return
+		}
+
+		httputil.EmbedTokenInResponse(ctx, w, &reqCtx.AuthInfo{
+			PlayerID:  playerID,
+			GroupID:   groupID,
+			SessionID: entry.SessionID,
+		}, auth.GroupMemberRights)
+		httputil.OK(w, entry)
+	}
+}
@@ -0,0 +1,212 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/test"
+	"github.com/jcserv/rivalslfg/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIntegration_JoinWaitlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 202 and a token without a group, waiting for the role asked for", func(t *testing.T) {
+		mockGroupService.EXPECT().JoinWaitlist(gomock.Any(), gomock.Cond(func(x any) bool {
+			arg := x.(services.JoinWaitlistParams)
+			return arg.Player.GroupID == "AAAA" && arg.Player.SessionID != "" && arg.Role == "vanguard"
+		})).Return(&services.WaitlistEntryDTO{
+			GroupID:  "AAAA",
+			PlayerID: 2,
+			Position: 3,
+			Role:     "vanguard",
+		}, nil)
+		body := joinRequestBody()
+		body["forRole"] = "Vanguard"
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/waitlist", test.GetBody(body))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"position":3`)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "2", claims["playerId"])
+		assert.Equal(t, "", claims["groupId"])
+		assert.NotEmpty(t, claims["sessionId"])
+	})
+	t.Run("Should return 400 if the role asked for isn't one of the player's", func(t *testing.T) {
+		body := joinRequestBody()
+		body["forRole"] = "strategist"
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/waitlist", test.GetBody(body))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if the group has room", func(t *testing.T) {
+		mockGroupService.EXPECT().JoinWaitlist(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusBadRequest, "Group has room, so the player can join it directly.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/waitlist", test.GetBody(joinRequestBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 400 if the player is already in a group", func(t *testing.T) {
+		req := withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/waitlist", test.GetBody(joinRequestBody())), 2, "BBBB", auth.GroupMemberRights...)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if the passcode is wrong", func(t *testing.T) {
+		mockGroupService.EXPECT().JoinWaitlist(gomock.Any(), gomock.Any()).Return(nil, services.NewError(http.StatusForbidden, "Access denied.", nil))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/waitlist", test.GetBody(joinRequestBody()))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_GetWaitlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 with the waitlist", func(t *testing.T) {
+		mockGroupService.EXPECT().GetWaitlist(gomock.Any(), "AAAA").Return([]services.WaitlistEntryDTO{
+			{GroupID: "AAAA", PlayerID: 2, Name: "imphungky", Position: 1},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/waitlist", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "imphungky")
+	})
+	t.Run("Should return 403 if the requester doesn't own the group", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodGet, "/api/v1/groups/AAAA/waitlist", nil), 1, "BBBB", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestIntegration_LeaveWaitlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 204 if the player takes themselves off", func(t *testing.T) {
+		mockGroupService.EXPECT().LeaveWaitlist(gomock.Any(), "AAAA", int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/waitlist/2", nil), 2, ""))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 204 if the owner takes the player off", func(t *testing.T) {
+		mockGroupService.EXPECT().LeaveWaitlist(gomock.Any(), "AAAA", int32(2)).Return(nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/waitlist/2", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Should return 403 if anyone else tries to take the player off", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/waitlist/2", nil), 3, ""))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Should return 404 if the player isn't on the waitlist", func(t *testing.T) {
+		mockGroupService.EXPECT().LeaveWaitlist(gomock.Any(), "AAAA", int32(2)).Return(services.NewError(http.StatusNotFound, "Player is not on the group's waitlist.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodDelete, "/api/v1/groups/AAAA/waitlist/2", nil), 2, ""))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestIntegration_AcceptWaitlistOffer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := mux.NewRouter()
+	mockGroupService := mocks.NewMockIGroup(ctrl)
+
+	a := NewAPI(
+		&Dependencies{
+			GroupService:  mockGroupService,
+			PlayerService: mocks.NewMockIPlayer(ctrl),
+		},
+	)
+	a.RegisterRoutes(r)
+	t.Parallel()
+
+	t.Run("Should return 200 and a member's token for the group, keeping the player's session", func(t *testing.T) {
+		expiresAt := time.Now().Add(services.WaitlistOfferTTL)
+		mockGroupService.EXPECT().AcceptWaitlistOffer(gomock.Any(), "AAAA", int32(2)).Return(&services.WaitlistEntryDTO{
+			GroupID:        "AAAA",
+			PlayerID:       2,
+			OfferedRole:    "vanguard",
+			OfferExpiresAt: &expiresAt,
+			SessionID:      "session",
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/waitlist/2/accept", nil), 2, ""))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		claims, err := auth.ValidateToken(rec.Header().Get("X-Token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "AAAA", claims["groupId"])
+		assert.Equal(t, "session", claims["sessionId"])
+	})
+	t.Run("Should return 400 if no slot is being held for the player", func(t *testing.T) {
+		mockGroupService.EXPECT().AcceptWaitlistOffer(gomock.Any(), "AAAA", int32(2)).Return(nil, services.NewError(http.StatusBadRequest, "No slot is being held for the player.", nil))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/waitlist/2/accept", nil), 2, ""))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Should return 403 if someone else tries to accept the offer", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, withPlayer(httptest.NewRequest(http.MethodPost, "/api/v1/groups/AAAA/waitlist/2/accept", nil), 1, "AAAA", auth.GroupOwnerRights...))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
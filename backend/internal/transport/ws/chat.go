@@ -25,5 +25,8 @@ func (h *ChatHandler) Handle(ctx context.Context, client *Client, payload json.R
 	if err := json.Unmarshal(payload, &msg); err != nil {
 		return err
 	}
-	return h.hub.Broadcast(msg)
+	if err := h.hub.Broadcast(msg); err != nil {
+		return err
+	}
+	return h.hub.RecordActivity(ctx, client)
 }
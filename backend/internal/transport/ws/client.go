@@ -3,7 +3,9 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +22,8 @@ type Client struct {
 	hub           *Hub
 	conn          *gws.Conn
 	eventHandlers map[WebSocketEventType]EventHandler
+	// The player that the connection was authorized for
+	playerID int
 }
 
 func NewClient(hub *Hub, conn *gws.Conn) *Client {
@@ -42,7 +46,8 @@ type ClientHandler struct {
 
 func (h *ClientHandler) OnOpen(socket *gws.Conn) {
 	_ = socket.SetDeadline(time.Now().Add(PingInterval + PingWait))
-	h.client = NewClient(h.hub, socket)
+	// The client was already registered with the hub when upgrading, so it's kept rather than replaced
+	h.client.conn = socket
 }
 
 func (h *ClientHandler) OnClose(socket *gws.Conn, _ error) {
@@ -79,9 +84,7 @@ func (h *ClientHandler) OnMessage(socket *gws.Conn, message *gws.Message) {
 }
 
 func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
-	client := &Client{
-		hub: hub,
-	}
+	client := NewClient(hub, nil)
 
 	handler := &ClientHandler{
 		hub:    hub,
@@ -102,7 +105,7 @@ func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
 			groupId := r.URL.Query().Get("groupId")
 			token := r.URL.Query().Get("access_token")
 
-			if groupId == "" || token == "" {
+			if token == "" {
 				return false
 			}
 
@@ -111,6 +114,16 @@ func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
 				return false
 			}
 
+			// Players without a group connect to receive queue updates
+			if groupId == "" {
+				if !auth.HasNotCreatedGroup(claims) {
+					return false
+				}
+				session.Store("playerId", claims["playerId"])
+				session.Store("websocketKey", r.Header.Get("Sec-WebSocket-Key"))
+				return true
+			}
+
 			if !auth.IsGroupMember(claims, groupId) {
 				return false
 			}
@@ -128,12 +141,18 @@ func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
 	}
 
 	client.conn = conn
-	groupID, exists := conn.Session().Load("groupId")
-	if !exists {
+	playerID, _ := conn.Session().Load("playerId")
+	id, err := strconv.Atoi(fmt.Sprint(playerID))
+	if err != nil {
 		return
 	}
+	client.playerID = id
 
-	hub.RegisterClient(groupID.(string), client)
+	if groupID, exists := conn.Session().Load("groupId"); exists {
+		hub.RegisterClient(groupID.(string), client)
+	} else {
+		hub.RegisterPlayer(id, client)
+	}
 
 	go func() {
 		conn.ReadLoop() // Blocking prevents the context from being GC
@@ -0,0 +1,387 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcserv/rivalslfg/internal/auth"
+	"github.com/jcserv/rivalslfg/internal/repository"
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/utils"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// GroupUpdatedPayload is the group's new settings. The passcode is left out, since not every member can see it.
+type GroupUpdatedPayload struct {
+	GroupID       string                    `json:"groupId"`
+	Region        string                    `json:"region"`
+	Gamemode      string                    `json:"gamemode"`
+	Open          bool                      `json:"open"`
+	RequestToJoin bool                      `json:"requestToJoin"`
+	RoleQueue     *repository.RoleQueue     `json:"roleQueue"`
+	GroupSettings *repository.GroupSettings `json:"groupSettings"`
+	Description   string                    `json:"description"`
+	Language      string                    `json:"language,omitempty"`
+	Tags          []string                  `json:"tags"`
+}
+
+type GroupDeletedPayload struct {
+	GroupID string `json:"groupId"`
+}
+
+// GroupIdlePayload warns members that the group will be deleted at ExpiresAt, unless someone is active in it.
+type GroupIdlePayload struct {
+	GroupID   string    `json:"groupId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// PasscodeRotatedPayload lets members know the passcode changed. Only the owner can see the new one.
+type PasscodeRotatedPayload struct {
+	GroupID string `json:"groupId"`
+}
+
+// PromotionPayload announces the group's new owner. The new owner is also
+// sent a token with the owner's rights, since they didn't make the request
+// that promoted them.
+type PromotionPayload struct {
+	GroupID         string `json:"groupId"`
+	PreviousOwnerID int32  `json:"previousOwnerId"`
+	OwnerID         int32  `json:"ownerId"`
+	Token           string `json:"token,omitempty"`
+}
+
+// JoinResolvedPayload tells the owner and the player who asked how a request
+// to join was resolved. Approved players are also sent a token for the
+// group, since they didn't make the request that seated them.
+type JoinResolvedPayload struct {
+	GroupID   string `json:"groupId"`
+	RequestID int32  `json:"requestId"`
+	PlayerID  int32  `json:"playerId"`
+	Status    string `json:"status"`
+	Token     string `json:"token,omitempty"`
+}
+
+// GroupStartedPayload announces that a scheduled group has gone live. Players
+// who RSVP'd are each sent whether they were seated, along with a token for
+// the group if they were, since they didn't make the request that seated them.
+type GroupStartedPayload struct {
+	GroupID  string `json:"groupId"`
+	PlayerID int32  `json:"playerId,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// MergeResolvedPayload tells the owners how a proposed merge was resolved.
+// Once it's accepted, the target group's members are told about it too, and
+// each of the source group's members is sent a token for the target group,
+// since they didn't make the request that moved them.
+type MergeResolvedPayload struct {
+	MergeID  int32  `json:"mergeId"`
+	SourceID string `json:"sourceId"`
+	TargetID string `json:"targetId"`
+	Status   string `json:"status"`
+	PlayerID int32  `json:"playerId,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// GroupNotifier pushes group changes to the group's connected members.
+type GroupNotifier struct {
+	hub *Hub
+}
+
+func NewGroupNotifier(hub *Hub) *GroupNotifier {
+	return &GroupNotifier{hub: hub}
+}
+
+// GroupUpdated sends the group's new settings to its members.
+func (n *GroupNotifier) GroupUpdated(ctx context.Context, group *repository.GroupWithPlayers) {
+	err := n.hub.Broadcast(Message{
+		GroupID: group.ID,
+		Op:      OpGroupUpdated,
+		Payload: GroupUpdatedPayload{
+			GroupID:       group.ID,
+			Region:        group.Region,
+			Gamemode:      group.Gamemode,
+			Open:          group.Open,
+			RequestToJoin: group.RequestToJoin,
+			RoleQueue:     group.RoleQueue,
+			GroupSettings: group.GroupSettings,
+			Description:   group.Description,
+			Language:      group.Language,
+			Tags:          group.Tags,
+		},
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify members of updated group %s: %v", group.ID, err))
+	}
+}
+
+// GroupIdle warns members that the group is about to be deleted for being idle.
+func (n *GroupNotifier) GroupIdle(ctx context.Context, groupID string, expiresAt time.Time) {
+	err := n.hub.Broadcast(Message{
+		GroupID: groupID,
+		Op:      OpGroupIdle,
+		Payload: GroupIdlePayload{GroupID: groupID, ExpiresAt: expiresAt},
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to warn members of idle group %s: %v", groupID, err))
+	}
+}
+
+// PasscodeRotated lets members know that the old passcode no longer works.
+func (n *GroupNotifier) PasscodeRotated(ctx context.Context, groupID string) {
+	err := n.hub.Broadcast(Message{
+		GroupID: groupID,
+		Op:      OpPasscodeRotated,
+		Payload: PasscodeRotatedPayload{GroupID: groupID},
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify members of rotated passcode for group %s: %v", groupID, err))
+	}
+}
+
+// PlayerPromoted lets members know who owns the group now, and hands the new
+// owner their token, carrying over the session they joined the group from.
+func (n *GroupNotifier) PlayerPromoted(ctx context.Context, groupID string, previousOwnerID, ownerID int32, sessionID string) {
+	payload := PromotionPayload{
+		GroupID:         groupID,
+		PreviousOwnerID: previousOwnerID,
+		OwnerID:         ownerID,
+	}
+	err := n.hub.BroadcastExcept(Message{
+		GroupID: groupID,
+		Op:      OpGroupPromotion,
+		Payload: payload,
+	}, int(ownerID))
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify members of promotion in group %s: %v", groupID, err))
+	}
+
+	pID := utils.IntToString(int(ownerID))
+	claims := map[string]string{
+		"playerId": pID,
+		"groupId":  groupID,
+	}
+	if sessionID != "" {
+		claims["sessionId"] = sessionID
+	}
+	payload.Token, err = auth.GenerateToken(pID, claims, auth.GroupOwnerRights...)
+	if err != nil {
+		log.Error(ctx, fmt.Sprintf("unable to generate token for new owner of group %s: %v", groupID, err))
+		return
+	}
+	err = n.hub.SendToGroupMember(int(ownerID), Message{
+		GroupID: groupID,
+		Op:      OpGroupPromotion,
+		Payload: payload,
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify new owner of group %s: %v", groupID, err))
+	}
+}
+
+// JoinRequested sends the group's owner the player who's asking to join.
+func (n *GroupNotifier) JoinRequested(ctx context.Context, ownerID int32, request *services.JoinRequestDTO) {
+	err := n.hub.SendToGroupMember(int(ownerID), Message{
+		GroupID: request.GroupID,
+		Op:      OpJoinRequested,
+		Payload: request,
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify owner of join request for group %s: %v", request.GroupID, err))
+	}
+}
+
+// JoinRequestResolved lets the owner and the player who asked know how the
+// request was resolved, and hands approved players their token.
+func (n *GroupNotifier) JoinRequestResolved(ctx context.Context, ownerID int32, request *services.JoinRequestDTO) {
+	payload := JoinResolvedPayload{
+		GroupID:   request.GroupID,
+		RequestID: request.ID,
+		PlayerID:  request.PlayerID,
+		Status:    request.Status,
+	}
+	err := n.hub.SendToGroupMember(int(ownerID), Message{
+		GroupID: request.GroupID,
+		Op:      OpJoinResolved,
+		Payload: payload,
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify owner of resolved join request for group %s: %v", request.GroupID, err))
+	}
+
+	if request.Status == services.JoinRequestApproved {
+		pID := utils.IntToString(int(request.PlayerID))
+		claims := map[string]string{
+			"playerId": pID,
+			"groupId":  request.GroupID,
+		}
+		if request.SessionID != "" {
+			claims["sessionId"] = request.SessionID
+		}
+		payload.Token, err = auth.GenerateToken(pID, claims, auth.GroupMemberRights...)
+		if err != nil {
+			log.Error(ctx, fmt.Sprintf("unable to generate token for player approved to join group %s: %v", request.GroupID, err))
+			return
+		}
+	}
+	err = n.hub.SendToPlayer(int(request.PlayerID), Message{
+		GroupID: request.GroupID,
+		Op:      OpJoinResolved,
+		Payload: payload,
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify player of resolved join request for group %s: %v", request.GroupID, err))
+	}
+}
+
+// WaitlistOffered tells the waiting player that a slot is being held for them, and until when.
+func (n *GroupNotifier) WaitlistOffered(ctx context.Context, entry *services.WaitlistEntryDTO) {
+	err := n.hub.SendToPlayer(int(entry.PlayerID), Message{
+		GroupID: entry.GroupID,
+		Op:      OpWaitlistOffer,
+		Payload: entry,
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to offer waitlisted player a slot in group %s: %v", entry.GroupID, err))
+	}
+}
+
+// GroupStarted lets the owner know that the scheduled group has gone live, and
+// tells each player who RSVP'd whether they were seated.
+func (n *GroupNotifier) GroupStarted(ctx context.Context, groupID string, rsvps []services.RsvpDTO) {
+	err := n.hub.Broadcast(Message{
+		GroupID: groupID,
+		Op:      OpGroupStarted,
+		Payload: GroupStartedPayload{GroupID: groupID},
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify members of started group %s: %v", groupID, err))
+	}
+
+	for _, rsvp := range rsvps {
+		payload := GroupStartedPayload{
+			GroupID:  groupID,
+			PlayerID: rsvp.PlayerID,
+			Status:   rsvp.Status,
+		}
+		if rsvp.Status == services.RsvpSeated {
+			pID := utils.IntToString(int(rsvp.PlayerID))
+			claims := map[string]string{
+				"playerId": pID,
+				"groupId":  groupID,
+			}
+			if rsvp.SessionID != "" {
+				claims["sessionId"] = rsvp.SessionID
+			}
+			payload.Token, err = auth.GenerateToken(pID, claims, auth.GroupMemberRights...)
+			if err != nil {
+				log.Error(ctx, fmt.Sprintf("unable to generate token for player seated in started group %s: %v", groupID, err))
+				continue
+			}
+		}
+		err = n.hub.SendToPlayer(int(rsvp.PlayerID), Message{
+			GroupID: groupID,
+			Op:      OpGroupStarted,
+			Payload: payload,
+		})
+		if err != nil {
+			log.Debug(ctx, fmt.Sprintf("unable to notify player %d that group %s started: %v", rsvp.PlayerID, groupID, err))
+		}
+	}
+}
+
+// MergeProposed sends the owner of the target group the merge that they're being asked to accept.
+func (n *GroupNotifier) MergeProposed(ctx context.Context, merge *services.MergeDTO) {
+	err := n.hub.SendToGroupMember(int(merge.TargetOwnerID), Message{
+		GroupID: merge.TargetID,
+		Op:      OpMergeProposed,
+		Payload: merge,
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify owner of merge proposed to group %s: %v", merge.TargetID, err))
+	}
+}
+
+// MergeResolved lets both owners know how the merge was resolved. Accepted
+// merges also hand each moved member their token for the target group, before
+// the source group's connections are closed.
+func (n *GroupNotifier) MergeResolved(ctx context.Context, merge *services.MergeDTO) {
+	payload := MergeResolvedPayload{
+		MergeID:  merge.ID,
+		SourceID: merge.SourceID,
+		TargetID: merge.TargetID,
+		Status:   merge.Status,
+	}
+	if merge.Status != services.MergeAccepted {
+		for groupID, ownerID := range map[string]int32{merge.SourceID: merge.ProposedBy, merge.TargetID: merge.TargetOwnerID} {
+			err := n.hub.SendToGroupMember(int(ownerID), Message{
+				GroupID: groupID,
+				Op:      OpMergeResolved,
+				Payload: payload,
+			})
+			if err != nil {
+				log.Debug(ctx, fmt.Sprintf("unable to notify owner of resolved merge for group %s: %v", groupID, err))
+			}
+		}
+		return
+	}
+
+	for _, member := range merge.Moved {
+		pID := utils.IntToString(int(member.PlayerID))
+		claims := map[string]string{
+			"playerId": pID,
+			"groupId":  merge.TargetID,
+		}
+		if member.SessionID != "" {
+			claims["sessionId"] = member.SessionID
+		}
+		token, err := auth.GenerateToken(pID, claims, auth.GroupMemberRights...)
+		if err != nil {
+			log.Error(ctx, fmt.Sprintf("unable to generate token for player moved into group %s: %v", merge.TargetID, err))
+			continue
+		}
+
+		moved := payload
+		moved.PlayerID = member.PlayerID
+		moved.Token = token
+		err = n.hub.SendToGroupMember(int(member.PlayerID), Message{
+			GroupID: merge.SourceID,
+			Op:      OpMergeResolved,
+			Payload: moved,
+		})
+		if err != nil {
+			log.Debug(ctx, fmt.Sprintf("unable to notify player %d moved into group %s: %v", member.PlayerID, merge.TargetID, err))
+		}
+	}
+
+	err := n.hub.CloseGroup(merge.SourceID, Message{
+		GroupID: merge.SourceID,
+		Op:      OpMergeResolved,
+		Payload: payload,
+	}, "group merged")
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify members of merged group %s: %v", merge.SourceID, err))
+	}
+	err = n.hub.Broadcast(Message{
+		GroupID: merge.TargetID,
+		Op:      OpMergeResolved,
+		Payload: payload,
+	})
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify members of group %s that it was merged into: %v", merge.TargetID, err))
+	}
+}
+
+// GroupDeleted lets members know that the group is gone, and disconnects them from it.
+func (n *GroupNotifier) GroupDeleted(ctx context.Context, groupID string) {
+	err := n.hub.CloseGroup(groupID, Message{
+		GroupID: groupID,
+		Op:      OpGroupDeleted,
+		Payload: GroupDeletedPayload{GroupID: groupID},
+	}, "group deleted")
+	if err != nil {
+		log.Debug(ctx, fmt.Sprintf("unable to notify members of deleted group %s: %v", groupID, err))
+	}
+}
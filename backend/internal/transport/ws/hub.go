@@ -20,12 +20,26 @@ type Hub struct {
 	groups map[string]map[*Client]bool
 	// Map of client to its current group ID
 	clientGroups map[*Client]string
+	// Map of queued player ID to set of client connections
+	players map[int]map[*Client]bool
+	// Map of client to its queued player ID
+	clientPlayers map[*Client]int
+
+	// Told when members are active in their group over the websocket
+	activity ActivityRecorder
+}
+
+// ActivityRecorder keeps track of when groups were last active, so that idle groups can be expired.
+type ActivityRecorder interface {
+	RecordActivity(ctx context.Context, groupID string) error
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		groups:       make(map[string]map[*Client]bool),
-		clientGroups: make(map[*Client]string),
+		groups:        make(map[string]map[*Client]bool),
+		clientGroups:  make(map[*Client]string),
+		players:       make(map[int]map[*Client]bool),
+		clientPlayers: make(map[*Client]int),
 	}
 }
 
@@ -42,6 +56,13 @@ func (h *Hub) Run(ctx context.Context) {
 		}
 		delete(h.groups, groupID)
 	}
+	for playerID, clients := range h.players {
+		for client := range clients {
+			client.conn.NetConn().Close()
+			delete(h.clientPlayers, client)
+		}
+		delete(h.players, playerID)
+	}
 }
 
 func (h *Hub) RegisterClient(groupID string, client *Client) {
@@ -55,10 +76,52 @@ func (h *Hub) RegisterClient(groupID string, client *Client) {
 	h.clientGroups[client] = groupID
 }
 
+// RegisterPlayer registers the client of a queued player, who isn't in a group yet.
+func (h *Hub) RegisterPlayer(playerID int, client *Client) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.players[playerID] == nil {
+		h.players[playerID] = make(map[*Client]bool)
+	}
+	h.players[playerID][client] = true
+	h.clientPlayers[client] = playerID
+}
+
+// SetActivityRecorder sets what group activity over the websocket is recorded with.
+func (h *Hub) SetActivityRecorder(activity ActivityRecorder) {
+	h.Lock()
+	defer h.Unlock()
+	h.activity = activity
+}
+
+// RecordActivity marks the client's group as active, if the client is in one.
+func (h *Hub) RecordActivity(ctx context.Context, client *Client) error {
+	h.RLock()
+	activity := h.activity
+	groupID, ok := h.clientGroups[client]
+	h.RUnlock()
+
+	if activity == nil || !ok {
+		return nil
+	}
+	return activity.RecordActivity(ctx, groupID)
+}
+
 func (h *Hub) UnregisterClient(client *Client) {
 	h.Lock()
 	defer h.Unlock()
 
+	if playerID, ok := h.clientPlayers[client]; ok {
+		delete(h.clientPlayers, client)
+		if clients, exists := h.players[playerID]; exists {
+			delete(clients, client)
+			if len(clients) == 0 {
+				delete(h.players, playerID)
+			}
+		}
+	}
+
 	if groupID, ok := h.clientGroups[client]; ok {
 		delete(h.clientGroups, client)
 		if clients, exists := h.groups[groupID]; exists {
@@ -89,3 +152,94 @@ func (h *Hub) Broadcast(msg Message) error {
 	}
 	return nil
 }
+
+// BroadcastExcept sends the message to the group's clients, other than the player's.
+func (h *Hub) BroadcastExcept(msg Message, playerID int) error {
+	h.RLock()
+	defer h.RUnlock()
+
+	clients, exists := h.groups[msg.GroupID]
+	if !exists {
+		return nil
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	for client := range clients {
+		if client.playerID != playerID {
+			client.conn.WriteMessage(gws.OpcodeText, msgBytes)
+		}
+	}
+	return nil
+}
+
+// SendToGroupMember sends the message to the clients of one of the group's members.
+func (h *Hub) SendToGroupMember(playerID int, msg Message) error {
+	h.RLock()
+	defer h.RUnlock()
+
+	clients, exists := h.groups[msg.GroupID]
+	if !exists {
+		return nil
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	for client := range clients {
+		if client.playerID == playerID {
+			client.conn.WriteMessage(gws.OpcodeText, msgBytes)
+		}
+	}
+	return nil
+}
+
+// CloseGroup sends the message to the group's clients, and then closes their connections.
+func (h *Hub) CloseGroup(groupID string, msg Message, reason string) error {
+	h.Lock()
+	defer h.Unlock()
+
+	clients, exists := h.groups[groupID]
+	if !exists {
+		return nil
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	for client := range clients {
+		client.conn.WriteMessage(gws.OpcodeText, msgBytes)
+		client.conn.WriteClose(1000, []byte(reason))
+		delete(h.clientGroups, client)
+	}
+	delete(h.groups, groupID)
+	return nil
+}
+
+// SendToPlayer sends the message to the clients of a queued player.
+func (h *Hub) SendToPlayer(playerID int, msg Message) error {
+	h.RLock()
+	defer h.RUnlock()
+
+	clients, exists := h.players[playerID]
+	if !exists {
+		return nil
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	for client := range clients {
+		client.conn.WriteMessage(gws.OpcodeText, msgBytes)
+	}
+	return nil
+}
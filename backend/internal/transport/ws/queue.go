@@ -0,0 +1,30 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcserv/rivalslfg/internal/services"
+	"github.com/jcserv/rivalslfg/internal/utils/log"
+)
+
+// QueueNotifier pushes queue updates to queued players that are connected.
+type QueueNotifier struct {
+	hub *Hub
+}
+
+func NewQueueNotifier(hub *Hub) *QueueNotifier {
+	return &QueueNotifier{hub: hub}
+}
+
+func (n *QueueNotifier) NotifyQueue(ctx context.Context, statuses []*services.QueueStatus) {
+	for _, status := range statuses {
+		err := n.hub.SendToPlayer(int(status.PlayerID), Message{
+			Op:      OpQueueUpdate,
+			Payload: status,
+		})
+		if err != nil {
+			log.Debug(ctx, fmt.Sprintf("unable to send queue update to player %d: %v", status.PlayerID, err))
+		}
+	}
+}
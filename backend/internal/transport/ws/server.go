@@ -17,6 +17,21 @@ func NewServer(allowedOrigins []string) *Server {
 	}
 }
 
+// QueueNotifier pushes queue updates to the server's queued players.
+func (s *Server) QueueNotifier() *QueueNotifier {
+	return NewQueueNotifier(s.hub)
+}
+
+// GroupNotifier pushes group changes to the server's group members.
+func (s *Server) GroupNotifier() *GroupNotifier {
+	return NewGroupNotifier(s.hub)
+}
+
+// SetActivityRecorder records chat messages as activity in the sender's group.
+func (s *Server) SetActivityRecorder(activity ActivityRecorder) {
+	s.hub.SetActivityRecorder(activity)
+}
+
 func (s *Server) RegisterHandlers(mux *http.ServeMux) {
 	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		ServeWS(s.hub, w, r)
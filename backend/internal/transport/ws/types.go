@@ -8,10 +8,21 @@ import (
 type WebSocketEventType int
 
 const (
-	OpGroupChat      WebSocketEventType = iota + 1
-	OpGroupJoin      WebSocketEventType = iota + 2
-	OpGroupLeave     WebSocketEventType = iota + 3
-	OpGroupPromotion WebSocketEventType = iota + 4
+	OpGroupChat       WebSocketEventType = iota + 1
+	OpGroupJoin       WebSocketEventType = iota + 2
+	OpGroupLeave      WebSocketEventType = iota + 3
+	OpGroupPromotion  WebSocketEventType = iota + 4
+	OpQueueUpdate     WebSocketEventType = iota + 5
+	OpGroupDeleted    WebSocketEventType = iota + 6
+	OpGroupUpdated    WebSocketEventType = iota + 7
+	OpGroupIdle       WebSocketEventType = iota + 8
+	OpPasscodeRotated WebSocketEventType = iota + 9
+	OpJoinRequested   WebSocketEventType = iota + 10
+	OpJoinResolved    WebSocketEventType = iota + 11
+	OpWaitlistOffer   WebSocketEventType = iota + 12
+	OpGroupStarted    WebSocketEventType = iota + 13
+	OpMergeProposed   WebSocketEventType = iota + 14
+	OpMergeResolved   WebSocketEventType = iota + 15
 )
 
 type EventHandler interface {
@@ -0,0 +1,125 @@
+package types
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Blocked words are matched against whole words, so that e.g. "Scunthorpe" is let through.
+//
+//go:embed data/blocked_words.json
+var blockedWordsJSON []byte
+
+var blockedWords = NewSet[string]()
+
+func init() {
+	var words []string
+	if err := json.Unmarshal(blockedWordsJSON, &words); err != nil {
+		panic(err)
+	}
+	blockedWords.Add(words...)
+}
+
+// Tags are the curated set of tags that groups can describe their vibe with.
+var Tags = NewSet(
+	"chill",
+	"tryhard",
+	"learning",
+	"18+",
+	"casual",
+	"comms",
+	"no-comms",
+	"coaching",
+	"team-ups",
+	"one-tricks",
+	"newbie-friendly",
+)
+
+// MaxTags is how many tags a group can have.
+const MaxTags = 5
+
+func ValidateTags(tags []string) error {
+	if len(tags) > MaxTags {
+		return fmt.Errorf("groups can have at most %d tags", MaxTags)
+	}
+
+	seen := NewSet[string]()
+	for _, tag := range tags {
+		if !Tags.Contains(strings.ToLower(tag)) {
+			return fmt.Errorf("tag %s is not supported", tag)
+		}
+		if seen.Contains(strings.ToLower(tag)) {
+			return fmt.Errorf("tag %s is listed more than once", tag)
+		}
+		seen.Add(strings.ToLower(tag))
+	}
+	return nil
+}
+
+// Languages are the ISO 639-1 codes of the languages that groups can be played in.
+var Languages = NewSet("ar", "de", "en", "es", "fr", "id", "it", "ja", "ko", "pl", "pt", "ru", "th", "tl", "tr", "vi", "zh")
+
+// ValidateLanguage validates the group's language, which is optional.
+func ValidateLanguage(language string) error {
+	if language != "" && !Languages.Contains(strings.ToLower(language)) {
+		return fmt.Errorf("language %s is not supported", language)
+	}
+	return nil
+}
+
+// MaxDescriptionLength is how many characters a group's description can be.
+const MaxDescriptionLength = 280
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.|discord\.gg/)`)
+
+// ValidateDescription checks that the group's description is short enough,
+// and that it doesn't contain links or blocked words.
+func ValidateDescription(description string) error {
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", MaxDescriptionLength)
+	}
+	if linkPattern.MatchString(description) {
+		return fmt.Errorf("description can't contain links")
+	}
+	if ContainsBlockedWord(description) {
+		return fmt.Errorf("description contains language that isn't allowed")
+	}
+	return nil
+}
+
+// leetReplacer undoes common letter substitutions, e.g. "sh1t".
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// ContainsBlockedWord reports whether any word in the text is blocked, once
+// letter substitutions and stretched out letters (e.g. "fuuuck") are undone.
+func ContainsBlockedWord(text string) bool {
+	text = leetReplacer.Replace(strings.ToLower(text))
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		if blockedWords.Contains(word) || blockedWords.Contains(squeeze(word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// squeeze collapses runs of the same letter into one.
+func squeeze(word string) string {
+	var b strings.Builder
+	var last rune
+	for i, r := range word {
+		if i > 0 && r == last {
+			continue
+		}
+		b.WriteRune(r)
+		last = r
+	}
+	return b.String()
+}
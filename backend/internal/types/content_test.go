@@ -0,0 +1,46 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTags(t *testing.T) {
+	assert.NoError(t, ValidateTags(nil))
+	assert.NoError(t, ValidateTags([]string{"chill", "18+", "Learning"}))
+	assert.ErrorContains(t, ValidateTags([]string{"chill", "toxic"}), "tag toxic is not supported")
+	assert.ErrorContains(t, ValidateTags([]string{"chill", "Chill"}), "tag Chill is listed more than once")
+	assert.ErrorContains(t, ValidateTags([]string{"chill", "tryhard", "learning", "18+", "casual", "comms"}), "at most 5 tags")
+}
+
+func TestValidateLanguage(t *testing.T) {
+	assert.NoError(t, ValidateLanguage(""))
+	assert.NoError(t, ValidateLanguage("en"))
+	assert.NoError(t, ValidateLanguage("PT"))
+	assert.ErrorContains(t, ValidateLanguage("english"), "language english is not supported")
+}
+
+func TestValidateDescription(t *testing.T) {
+	t.Run("Should allow a short description", func(t *testing.T) {
+		assert.NoError(t, ValidateDescription(""))
+		assert.NoError(t, ValidateDescription("Chill ranked grind, Scunthorpe time zone. Bring snacks!"))
+	})
+
+	t.Run("Should validate length", func(t *testing.T) {
+		assert.NoError(t, ValidateDescription(strings.Repeat("é", MaxDescriptionLength)))
+		assert.ErrorContains(t, ValidateDescription(strings.Repeat("a", MaxDescriptionLength+1)), "at most 280 characters")
+	})
+
+	t.Run("Should not allow links", func(t *testing.T) {
+		assert.ErrorContains(t, ValidateDescription("join us at https://example.com"), "can't contain links")
+		assert.ErrorContains(t, ValidateDescription("discord.gg/abc"), "can't contain links")
+	})
+
+	t.Run("Should not allow blocked words", func(t *testing.T) {
+		assert.ErrorContains(t, ValidateDescription("no shit talking"), "isn't allowed")
+		assert.ErrorContains(t, ValidateDescription("SH1T"), "isn't allowed")
+		assert.ErrorContains(t, ValidateDescription("fuuuuck this"), "isn't allowed")
+	})
+}
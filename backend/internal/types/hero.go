@@ -0,0 +1,80 @@
+package types
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// Hero data is kept in sync with the frontend's character and team-up assets.
+var (
+	//go:embed data/heroes.json
+	heroesJSON []byte
+	//go:embed data/teamups.json
+	teamUpsJSON []byte
+)
+
+type Hero struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// TeamUp is active when a team has every hero in AllOf, and at least one of OneOf if it lists any.
+type TeamUp struct {
+	Name  string   `json:"name"`
+	AllOf []string `json:"allOf"`
+	OneOf []string `json:"oneOf"`
+}
+
+var (
+	// HeroRoles maps each hero to their role.
+	HeroRoles = map[string]string{}
+	TeamUps   = []TeamUp{}
+)
+
+func init() {
+	var heroes []Hero
+	if err := json.Unmarshal(heroesJSON, &heroes); err != nil {
+		panic(err)
+	}
+	for _, hero := range heroes {
+		HeroRoles[hero.Name] = hero.Role
+	}
+
+	if err := json.Unmarshal(teamUpsJSON, &TeamUps); err != nil {
+		panic(err)
+	}
+}
+
+// ActiveWith reports whether the heroes can activate the team-up.
+func (t TeamUp) ActiveWith(heroes Set[string]) bool {
+	for _, hero := range t.AllOf {
+		if !heroes.Contains(hero) {
+			return false
+		}
+	}
+	if len(t.OneOf) == 0 {
+		return true
+	}
+	for _, hero := range t.OneOf {
+		if heroes.Contains(hero) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCharacters checks that each of the player's characters is a known hero, listed once.
+func ValidateCharacters(characters []string) error {
+	seen := NewSet[string]()
+	for _, character := range characters {
+		if _, ok := HeroRoles[character]; !ok {
+			return fmt.Errorf("character %s is not supported", character)
+		}
+		if seen.Contains(character) {
+			return fmt.Errorf("character %s is listed more than once", character)
+		}
+		seen.Add(character)
+	}
+	return nil
+}
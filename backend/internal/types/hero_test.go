@@ -0,0 +1,34 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamUps(t *testing.T) {
+	t.Run("Should only reference known heroes", func(t *testing.T) {
+		for _, teamUp := range TeamUps {
+			for _, hero := range append(teamUp.AllOf, teamUp.OneOf...) {
+				_, ok := HeroRoles[hero]
+				assert.True(t, ok, "%s in %s", hero, teamUp.Name)
+			}
+		}
+	})
+}
+
+func TestTeamUp_ActiveWith(t *testing.T) {
+	teamUp := TeamUp{Name: "Gamma Charge", AllOf: []string{"Hulk"}, OneOf: []string{"Doctor Strange", "Iron Man"}}
+
+	assert.True(t, teamUp.ActiveWith(NewSet("Hulk", "Iron Man")))
+	assert.False(t, teamUp.ActiveWith(NewSet("Hulk")))
+	assert.False(t, teamUp.ActiveWith(NewSet("Doctor Strange", "Iron Man")))
+	assert.True(t, TeamUp{AllOf: []string{"Hulk"}}.ActiveWith(NewSet("Hulk")))
+}
+
+func TestValidateCharacters(t *testing.T) {
+	assert.NoError(t, ValidateCharacters(nil))
+	assert.NoError(t, ValidateCharacters([]string{"Hulk", "Doctor Strange"}))
+	assert.ErrorContains(t, ValidateCharacters([]string{"Hulk", "Batman"}), "character Batman is not supported")
+	assert.ErrorContains(t, ValidateCharacters([]string{"Hulk", "Hulk"}), "character Hulk is listed more than once")
+}
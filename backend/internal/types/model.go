@@ -20,6 +20,32 @@ func ValidateGamemode(gamemode string) error {
 	return nil
 }
 
+// DefaultTeamSize is how many players make up a team in gamemodes that don't say otherwise.
+const DefaultTeamSize = 6
+
+// TeamSizes is the most players that a group can have, by gamemode.
+var TeamSizes = map[string]int{
+	"competitive": 6,
+	"quickplay":   6,
+}
+
+// TeamSize returns the most players that a group in the gamemode can have.
+func TeamSize(gamemode string) int {
+	if size, ok := TeamSizes[gamemode]; ok {
+		return size
+	}
+	return DefaultTeamSize
+}
+
+// ValidateTeamSize checks that a role queue fits into a team of the gamemode.
+func ValidateTeamSize(gamemode string, vanguards, duelists, strategists int) error {
+	size := TeamSize(gamemode)
+	if vanguards+duelists+strategists > size {
+		return fmt.Errorf("role queue must have at most %d slots for %s", size, gamemode)
+	}
+	return nil
+}
+
 var Platforms = NewSet("pc", "co")
 
 func ValidatePlatform(platform string) error {
@@ -69,6 +95,44 @@ func ValidateRoles(roles []string) error {
 	return nil
 }
 
+// ValidateRolePreferences validates the roles a player is willing to play, in
+// order of preference. The main role is only required if no preferences are given.
+func ValidateRolePreferences(role string, roles []string) error {
+	if len(roles) == 0 {
+		return ValidateRole(role)
+	}
+
+	if role != "" {
+		if err := ValidateRole(role); err != nil {
+			return err
+		}
+	}
+
+	seen := NewSet[string]()
+	for _, r := range roles {
+		if err := ValidateRole(r); err != nil {
+			return err
+		}
+		if seen.Contains(strings.ToLower(r)) {
+			return fmt.Errorf("role %s is listed more than once", r)
+		}
+		seen.Add(strings.ToLower(r))
+	}
+	return nil
+}
+
+// RolePreferences returns the roles a player is willing to play in order of
+// preference, falling back to their main role if none were given.
+func RolePreferences(role string, roles []string) []string {
+	if len(roles) == 0 {
+		if role == "" {
+			return []string{}
+		}
+		return []string{strings.ToLower(role)}
+	}
+	return utils.StringSliceToLower(roles)
+}
+
 var RankIDToRankVal = map[string]int{
 	"b3":  0,
 	"b2":  1,
@@ -0,0 +1,125 @@
+package types
+
+import "sort"
+
+// RankRange is an inclusive range of rank values.
+type RankRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+func (r RankRange) Contains(rankVal int) bool {
+	return rankVal >= r.Min && rankVal <= r.Max
+}
+
+// RankRestriction limits who players within Ranks can group with, regardless
+// of bands or spread.
+type RankRestriction struct {
+	Ranks   RankRange `json:"ranks"`
+	Allowed RankRange `json:"allowed"`
+}
+
+// RankPolicy decides which ranks can group together. Two players are
+// compatible if neither is restricted from grouping with the other, and they
+// either share a band or are at most MaxSpread apart. A group is compatible
+// with a player if every member is.
+type RankPolicy struct {
+	Bands        []RankRange       `json:"bands"`
+	MaxSpread    int               `json:"maxSpread"`
+	Restrictions []RankRestriction `json:"restrictions"`
+}
+
+var DefaultRankPolicy = RankPolicy{
+	Bands: []RankRange{
+		// Bronze-Gold can always group with each other
+		{Min: RankIDToRankVal["b3"], Max: RankIDToRankVal["g1"]},
+	},
+	MaxSpread: 10,
+	Restrictions: []RankRestriction{
+		// Eternity and One Above All can only group with Celestial and above
+		{
+			Ranks:   RankRange{Min: RankIDToRankVal["e"], Max: RankIDToRankVal["oa"]},
+			Allowed: RankRange{Min: RankIDToRankVal["c3"], Max: RankIDToRankVal["oa"]},
+		},
+	},
+}
+
+// RankPolicies holds the rank policy of each gamemode.
+var RankPolicies = map[string]RankPolicy{
+	"competitive": DefaultRankPolicy,
+	"quickplay":   DefaultRankPolicy,
+}
+
+// RankPolicyFor returns the gamemode's rank policy, falling back to DefaultRankPolicy.
+func RankPolicyFor(gamemode string) RankPolicy {
+	if policy, ok := RankPolicies[gamemode]; ok {
+		return policy
+	}
+	return DefaultRankPolicy
+}
+
+func (p RankPolicy) Compatible(a, b int) bool {
+	for _, restriction := range p.Restrictions {
+		if restriction.Ranks.Contains(a) && !restriction.Allowed.Contains(b) {
+			return false
+		}
+		if restriction.Ranks.Contains(b) && !restriction.Allowed.Contains(a) {
+			return false
+		}
+	}
+
+	for _, band := range p.Bands {
+		if band.Contains(a) && band.Contains(b) {
+			return true
+		}
+	}
+	return abs(a-b) <= p.MaxSpread
+}
+
+// CanJoin reports whether a player of the given rank can join a group with members of the given ranks.
+func (p RankPolicy) CanJoin(rankVal int, memberRanks []int) bool {
+	for _, memberRank := range memberRanks {
+		if !p.Compatible(rankVal, memberRank) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowedRanks returns every rank value that a player of the given rank can
+// group with, in ascending order. This is how the policy is passed to queries.
+func (p RankPolicy) AllowedRanks(rankVal int) []int32 {
+	allowed := make([]int32, 0, len(RankValToRankID))
+	for _, val := range RankVals() {
+		if p.Compatible(rankVal, val) {
+			allowed = append(allowed, int32(val))
+		}
+	}
+	return allowed
+}
+
+// RankVals returns every rank value in ascending order.
+func RankVals() []int {
+	vals := make([]int, 0, len(RankValToRankID))
+	for val := range RankValToRankID {
+		vals = append(vals, val)
+	}
+	sort.Ints(vals)
+	return vals
+}
+
+// AllowedRanksByGamemode returns AllowedRanks for every gamemode.
+func AllowedRanksByGamemode(rankVal int) map[string][]int32 {
+	result := make(map[string][]int32, len(Gamemodes))
+	for _, gamemode := range Gamemodes.Members() {
+		result[gamemode] = RankPolicyFor(gamemode).AllowedRanks(rankVal)
+	}
+	return result
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
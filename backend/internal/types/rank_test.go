@@ -0,0 +1,91 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRankPolicy_Compatible(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		// Bronze-Gold band
+		{"b3", "b3", true},
+		{"b3", "g1", true},
+		{"s2", "g3", true},
+		{"g1", "p3", true},
+		{"b1", "p3", false},
+		{"g3", "p1", false},
+		// Spread
+		{"p3", "d3", true},
+		{"p2", "d2", true},
+		{"p3", "d1", false},
+		{"d1", "gm1", true},
+		{"gm3", "c3", true},
+		{"gm2", "c3", true},
+		{"gm1", "c1", true},
+		{"b3", "gm3", false},
+		// Eternity and One Above All restrictions
+		{"e", "c3", true},
+		{"e", "oa", true},
+		{"oa", "e", true},
+		{"oa", "c3", false},
+		{"e", "gm1", false},
+		{"gm1", "e", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s with %s", tt.a, tt.b), func(t *testing.T) {
+			a, b := RankIDToRankVal[tt.a], RankIDToRankVal[tt.b]
+			assert.Equal(t, tt.expected, DefaultRankPolicy.Compatible(a, b))
+			assert.Equal(t, tt.expected, DefaultRankPolicy.Compatible(b, a))
+		})
+	}
+}
+
+func TestDefaultRankPolicy_CanJoin(t *testing.T) {
+	tests := []struct {
+		name     string
+		rank     string
+		members  []string
+		expected bool
+	}{
+		{"Empty group", "oa", []string{}, true},
+		{"Within the band", "b2", []string{"s1", "g1"}, true},
+		{"Within the spread of every member", "d3", []string{"p3", "d1"}, true},
+		{"Too far from the lowest member", "d2", []string{"p3", "p1"}, false},
+		{"Too far from the highest member", "p2", []string{"d3", "d1"}, false},
+		{"Band does not cover members outside of it", "s1", []string{"g1", "p3"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := make([]int, 0, len(tt.members))
+			for _, member := range tt.members {
+				members = append(members, RankIDToRankVal[member])
+			}
+			assert.Equal(t, tt.expected, DefaultRankPolicy.CanJoin(RankIDToRankVal[tt.rank], members))
+		})
+	}
+}
+
+func TestRankPolicy_AllowedRanks(t *testing.T) {
+	t.Run("Should list every seeded rank the player can group with", func(t *testing.T) {
+		assert.Equal(t, []int32{0, 1, 2, 10, 11, 12, 20, 21, 22, 30, 31, 32}, DefaultRankPolicy.AllowedRanks(RankIDToRankVal["g1"]))
+		assert.Equal(t, []int32{60, 61, 62, 70, 80}, DefaultRankPolicy.AllowedRanks(RankIDToRankVal["e"]))
+		assert.Equal(t, []int32{70, 80}, DefaultRankPolicy.AllowedRanks(RankIDToRankVal["oa"]))
+	})
+
+	t.Run("Should use the gamemode's policy", func(t *testing.T) {
+		original := RankPolicies["quickplay"]
+		defer func() { RankPolicies["quickplay"] = original }()
+		RankPolicies["quickplay"] = RankPolicy{MaxSpread: 100}
+
+		allowed := AllowedRanksByGamemode(RankIDToRankVal["b3"])
+		assert.Len(t, allowed["quickplay"], len(RankValToRankID))
+		assert.Equal(t, []int32{0, 1, 2, 10, 11, 12, 20, 21, 22}, allowed["competitive"])
+	})
+}
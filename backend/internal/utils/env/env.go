@@ -1,6 +1,12 @@
 package env
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 func GetBytes(key string, fallback []byte) []byte {
 	if value, ok := os.LookupEnv(key); ok {
@@ -22,3 +28,33 @@ func GetBool(key string, fallback bool) bool {
 	}
 	return fallback
 }
+
+// GetDuration parses durations like "30m" or "1h30m".
+func GetDuration(key string, fallback time.Duration) (time.Duration, error) {
+	if value, ok := os.LookupEnv(key); ok {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s is not a valid duration: %v", key, err)
+		}
+		return duration, nil
+	}
+	return fallback, nil
+}
+
+// GetInt32s parses a comma separated list of numbers like "1,2,3".
+func GetInt32s(key string) ([]int32, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var result []int32
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.ParseInt(strings.TrimSpace(item), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid list of numbers: %v", key, err)
+		}
+		result = append(result, int32(n))
+	}
+	return result, nil
+}